@@ -1,13 +1,18 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 // Account 账号实体
 type Account struct {
-	ID        int64     `gorm:"column:id;primaryKey;autoIncrement"`
-	Name      string    `gorm:"column:name;type:varchar(255);not null"`
-	Email     string    `gorm:"column:email;type:varchar(255);uniqueIndex:uk_email;not null"`
-	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	ID        int64          `gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string         `gorm:"column:name;type:varchar(255);not null"`
+	Email     string         `gorm:"column:email;type:varchar(255);uniqueIndex:uk_email;not null"`
+	Settings  datatypes.JSON `gorm:"column:settings;type:json"` // 账号级设置（诊断器选择等）
+	CreatedAt time.Time      `gorm:"column:created_at;not null"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// RateContract 账号协议价实体
+type RateContract struct {
+	ID                   int64    `gorm:"column:id;primaryKey"`
+	AccountID            int64    `gorm:"column:account_id;not null;uniqueIndex:uk_account_carrier_service"`
+	Carrier              string   `gorm:"column:carrier;type:varchar(64);not null;uniqueIndex:uk_account_carrier_service"`
+	Service              string   `gorm:"column:service;type:varchar(64);not null;default:'';uniqueIndex:uk_account_carrier_service"` // 空串表示承运商全部服务
+	DiscountPercent      float64  `gorm:"column:discount_percent;type:decimal(5,2);not null;default:0"`
+	FixedFeeAmount       *float64 `gorm:"column:fixed_fee_amount;type:decimal(12,2)"`
+	FixedFeeCurrency     string   `gorm:"column:fixed_fee_currency;type:varchar(3);not null;default:''"`
+	FuelSurchargePercent *float64 `gorm:"column:fuel_surcharge_percent;type:decimal(5,2)"`
+
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// TableName 指定表名
+func (RateContract) TableName() string {
+	return "rate_contracts"
+}
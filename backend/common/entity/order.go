@@ -14,11 +14,15 @@ type Order struct {
 	MerchantOrderNo string `gorm:"column:merchant_order_no;type:varchar(128);not null;uniqueIndex:uk_account_merchant"`
 
 	// 订单数据
-	RawData datatypes.JSON `gorm:"column:shipment;type:json;not null"`
+	RawData         datatypes.JSON `gorm:"column:shipment;type:json;not null"`
+	DiagnoseOptions datatypes.JSON `gorm:"column:diagnose_options;type:json"` // 诊断选项（下单时确定，重新诊断复用）
 
 	// 诊断状态与结果
 	Status         string         `gorm:"column:status;type:varchar(16);not null;default:'DIAGNOSING';index:idx_account_status"`
 	DiagnoseResult datatypes.JSON `gorm:"column:diagnose_result;type:json"`
+	DiagnoseTrace  datatypes.JSON `gorm:"column:diagnose_trace;type:json"` // 诊断执行轨迹（explain 模式，非 explain 诊断会清空）
+
+	DiagnoseRequestID string `gorm:"column:diagnose_request_id;type:varchar(64);not null;default:''"` // 最近一次下发的诊断任务 request_id（只接受该任务的回调）
 
 	// 时间戳
 	CreatedAt time.Time `gorm:"column:created_at;not null;index:idx_created_at"`
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OrderDiagnosis 订单诊断版本实体（每次诊断回调追加一条，不覆盖历史）
+type OrderDiagnosis struct {
+	ID                int64          `gorm:"column:id;primaryKey;autoIncrement"`
+	OrderID           string         `gorm:"column:order_id;type:varchar(64);not null;uniqueIndex:uk_order_version;uniqueIndex:uk_order_request"`
+	Version           int            `gorm:"column:version;not null;uniqueIndex:uk_order_version"`                     // 订单内从 1 开始递增
+	RequestID         string         `gorm:"column:request_id;type:varchar(64);not null;uniqueIndex:uk_order_request"` // 回调 request_id（重复投递时幂等）
+	Status            string         `gorm:"column:status;type:varchar(16);not null"`                                  // DIAGNOSED/FAILED
+	EngineVersion     string         `gorm:"column:engine_version;type:varchar(64);not null;default:''"`
+	DiagnoserVersions datatypes.JSON `gorm:"column:diagnoser_versions;type:json"` // 诊断类型 → 规则/费率卡版本
+	DiagnoseResult    datatypes.JSON `gorm:"column:diagnose_result;type:json"`    // 诊断结果（不含执行轨迹）
+	ErrorMessage      string         `gorm:"column:error_message;type:varchar(1024);not null;default:''"`
+
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+}
+
+// TableName 指定表名
+func (OrderDiagnosis) TableName() string {
+	return "order_diagnoses"
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Product 账号商品目录实体（SKU 主数据，下单时补全订单商品字段）
+type Product struct {
+	ID               int64    `gorm:"column:id;primaryKey"`
+	AccountID        int64    `gorm:"column:account_id;not null;uniqueIndex:uk_account_sku"`
+	SKU              string   `gorm:"column:sku;type:varchar(128);not null;uniqueIndex:uk_account_sku"`
+	Description      string   `gorm:"column:description;type:varchar(255);not null;default:''"` // 品名
+	WeightValue      *float64 `gorm:"column:weight_value;type:decimal(10,3)"`                   // 单件重量
+	WeightUnit       string   `gorm:"column:weight_unit;type:varchar(8);not null;default:''"`
+	DimensionWidth   *float64 `gorm:"column:dimension_width;type:decimal(10,2)"` // 单件尺寸（三边同时为空或同时有值）
+	DimensionHeight  *float64 `gorm:"column:dimension_height;type:decimal(10,2)"`
+	DimensionDepth   *float64 `gorm:"column:dimension_depth;type:decimal(10,2)"`
+	DimensionUnit    string   `gorm:"column:dimension_unit;type:varchar(8);not null;default:''"`
+	HSCode           string   `gorm:"column:hs_code;type:varchar(16);not null;default:''"`
+	OriginCountry    string   `gorm:"column:origin_country;type:varchar(3);not null;default:''"`
+	BatteryChemistry string   `gorm:"column:battery_chemistry;type:varchar(16);not null;default:''"` // 空串表示不含电池
+	BatteryPacking   string   `gorm:"column:battery_packing;type:varchar(32);not null;default:''"`
+
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// TableName 指定表名
+func (Product) TableName() string {
+	return "products"
+}
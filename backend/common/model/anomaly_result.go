@@ -2,15 +2,25 @@ package model
 
 // AnomalyResult 异常检测结果
 type AnomalyResult struct {
-	HasRisk bool          `json:"has_risk"`
-	Issues  []AnomalyItem `json:"issues"`
+	HasRisk       bool           `json:"has_risk"`
+	Issues        []AnomalyItem  `json:"issues"`
+	DeclaredValue *DeclaredValue `json:"declared_value,omitempty"` // 申报货值汇总
+}
+
+// DeclaredValue 申报货值汇总（各商品按汇率换算为统一币种后求和）
+type DeclaredValue struct {
+	Amount        float64        `json:"amount"`
+	Currency      string         `json:"currency"`                 // 汇总币种（汇率表基准货币）
+	Preferred     *Money         `json:"preferred,omitempty"`      // 换算为账号偏好币种后的金额
+	ExchangeRates []ExchangeRate `json:"exchange_rates,omitempty"` // 换算使用的汇率
 }
 
 // AnomalyItem 单个异常项
 type AnomalyItem struct {
-	Type    string `json:"type"`    // HIGH_VALUE/REMOTE_AREA/SKU_MISSING
-	Level   string `json:"level"`   // INFO/WARNING/CRITICAL
-	Message string `json:"message"` // 人类可读描述
+	Type            string   `json:"type"`                        // HIGH_VALUE/REMOTE_AREA/SKU_MISSING/DUPLICATE_ORDER/POSSIBLE_SPLIT_ORDER
+	Level           string   `json:"level"`                       // INFO/WARNING/CRITICAL
+	Message         string   `json:"message"`                     // 人类可读描述
+	RelatedOrderIDs []string `json:"related_order_ids,omitempty"` // 关联的疑似重复订单 ID
 }
 
 // 异常级别常量
@@ -22,7 +32,12 @@ const (
 
 // 异常类型常量
 const (
-	AnomalyTypeHighValue    = "HIGH_VALUE"
-	AnomalyTypeHeavyPackage = "HEAVY_PACKAGE"
-	AnomalyTypeSKUMissing   = "SKU_MISSING"
+	AnomalyTypeHighValue       = "HIGH_VALUE"
+	AnomalyTypeHeavyPackage    = "HEAVY_PACKAGE"
+	AnomalyTypeSKUMissing      = "SKU_MISSING"
+	AnomalyTypeUnknownCurrency = "UNKNOWN_CURRENCY"
+	AnomalyTypeRemoteArea      = "REMOTE_AREA"
+	AnomalyTypeDuplicateOrder  = "DUPLICATE_ORDER"
+	AnomalyTypeSplitOrder      = "POSSIBLE_SPLIT_ORDER"
+	AnomalyTypeDuplicateCheck  = "DUPLICATE_CHECK_UNAVAILABLE"
 )
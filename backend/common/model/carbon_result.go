@@ -0,0 +1,29 @@
+package model
+
+// CarbonResult 运输碳排放估算结果
+type CarbonResult struct {
+	BillableWeightKg   float64        `json:"billable_weight_kg"`             // 计费重合计（各包裹实重与体积重取大）
+	DistanceKm         *float64       `json:"distance_km,omitempty"`          // 线路大圆距离（无法计算时为空，此时不给出排放估算）
+	Options            []CarbonOption `json:"options"`                        // 各候选费率的排放估算（按排放升序）
+	LowestEmissionCode string         `json:"lowest_emission_code,omitempty"` // 排放最低的承运商服务（Carrier_Service）
+	Unavailable        string         `json:"unavailable,omitempty"`          // 无法估算的原因
+	FactorsVersion     string         `json:"factors_version,omitempty"`      // 排放因子版本
+}
+
+// CarbonOption 单个候选费率的排放估算
+type CarbonOption struct {
+	Carrier    string   `json:"carrier"`
+	Service    string   `json:"service"`
+	Mode       string   `json:"mode"`        // 运输方式：AIR/GROUND
+	DistanceKm float64  `json:"distance_km"` // 按运输方式修正后的运输距离
+	Co2eKg     float64  `json:"co2e_kg"`     // CO2e 排放（千克）
+	TotalFee   float64  `json:"total_fee"`   // 应付总运费（与 shipping 诊断一致）
+	Currency   string   `json:"currency"`
+	Tags       []string `json:"tags"` // 与 shipping 诊断的费率标签一致
+}
+
+// 运输方式常量
+const (
+	TransportModeAir    = "AIR"    // 航空快递
+	TransportModeGround = "GROUND" // 陆运
+)
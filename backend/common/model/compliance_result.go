@@ -0,0 +1,37 @@
+package model
+
+// ComplianceResult 海关合规诊断结果
+type ComplianceResult struct {
+	HasIssue    bool             `json:"has_issue"`
+	Destination string           `json:"destination"` // 目的国（ISO 两位代码）
+	Items       []ComplianceItem `json:"items"`       // 需要关注的商品（无问题的商品不出现）
+}
+
+// ComplianceItem 需要关注的单个商品
+type ComplianceItem struct {
+	ParcelIndex   int               `json:"parcel_index"` // 包裹序号（从 0 开始）
+	ItemIndex     int               `json:"item_index"`   // 商品在包裹内的序号（从 0 开始）
+	SKU           string            `json:"sku,omitempty"`
+	Description   string            `json:"description"`
+	HSCode        string            `json:"hs_code,omitempty"`
+	OriginCountry string            `json:"origin_country,omitempty"`
+	Battery       *Battery          `json:"battery,omitempty"` // 申报的电池信息
+	Issues        []ComplianceIssue `json:"issues"`
+}
+
+// ComplianceIssue 单个合规问题
+type ComplianceIssue struct {
+	Type    string `json:"type"`              // MISSING_HS_CODE/INVALID_HS_CODE/VAGUE_DESCRIPTION/RESTRICTED_ITEM/DANGEROUS_GOODS
+	Level   string `json:"level"`             // INFO/WARNING/CRITICAL
+	Keyword string `json:"keyword,omitempty"` // 命中的关键词（关键词类规则）
+	Message string `json:"message"`           // 人类可读描述
+}
+
+// 合规问题类型常量
+const (
+	ComplianceTypeMissingHSCode    = "MISSING_HS_CODE"
+	ComplianceTypeInvalidHSCode    = "INVALID_HS_CODE"
+	ComplianceTypeVagueDescription = "VAGUE_DESCRIPTION"
+	ComplianceTypeRestrictedItem   = "RESTRICTED_ITEM"
+	ComplianceTypeDangerousGoods   = "DANGEROUS_GOODS"
+)
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RateContract 账号与承运商的协议价（随诊断任务下发，dpsync 计算协议价时使用）
+// Service 为空表示对该承运商的所有服务生效；同一承运商下精确匹配服务的合同优先
+type RateContract struct {
+	Carrier              string   `json:"carrier"`
+	Service              string   `json:"service,omitempty"`
+	DiscountPercent      float64  `json:"discount_percent,omitempty"`       // 基础运费折扣百分比（0~100）
+	FixedFee             *Money   `json:"fixed_fee,omitempty"`              // 固定基础运费（设置后忽略折扣）
+	FuelSurchargePercent *float64 `json:"fuel_surcharge_percent,omitempty"` // 燃油附加费百分比（按协议基础运费计算，为空表示不收取）
+}
+
+// Validate 校验合同条款
+func (c *RateContract) Validate() error {
+	if strings.TrimSpace(c.Carrier) == "" {
+		return fmt.Errorf("contract carrier is required")
+	}
+	if c.DiscountPercent < 0 || c.DiscountPercent > 100 {
+		return fmt.Errorf("contract discount_percent must be between 0 and 100")
+	}
+	if c.FixedFee != nil {
+		if c.FixedFee.Amount < 0 {
+			return fmt.Errorf("contract fixed_fee cannot be negative")
+		}
+		if NormalizeCurrency(c.FixedFee.Currency) == "" {
+			return fmt.Errorf("contract fixed_fee currency is required")
+		}
+		if err := ValidateCurrencyCode(c.FixedFee.Currency); err != nil {
+			return err
+		}
+	}
+	if c.FuelSurchargePercent != nil && (*c.FuelSurchargePercent < 0 || *c.FuelSurchargePercent > 100) {
+		return fmt.Errorf("contract fuel_surcharge_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// Matches 合同是否适用于指定承运商服务（大小写不敏感）
+func (c *RateContract) Matches(carrier, service string) bool {
+	if !strings.EqualFold(strings.TrimSpace(c.Carrier), strings.TrimSpace(carrier)) {
+		return false
+	}
+	return c.Service == "" || strings.EqualFold(strings.TrimSpace(c.Service), strings.TrimSpace(service))
+}
+
+// FindRateContract 查找适用于承运商服务的合同（精确匹配服务优先于承运商通用合同）
+func FindRateContract(contracts []RateContract, carrier, service string) *RateContract {
+	var fallback *RateContract
+	for i := range contracts {
+		if !contracts[i].Matches(carrier, service) {
+			continue
+		}
+		if contracts[i].Service != "" {
+			return &contracts[i]
+		}
+		if fallback == nil {
+			fallback = &contracts[i]
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,50 @@
+package model
+
+import "testing"
+
+func TestFindRateContract(t *testing.T) {
+	contracts := []RateContract{
+		{Carrier: "FedEx", DiscountPercent: 10},
+		{Carrier: "FedEx", Service: "Ground", DiscountPercent: 20},
+		{Carrier: "FedEx", DiscountPercent: 30},
+		{Carrier: " ups ", Service: " ground ", DiscountPercent: 40},
+	}
+
+	tests := []struct {
+		name     string
+		carrier  string
+		service  string
+		discount float64 // 0 表示不应匹配
+	}{
+		{name: "exact service wins over carrier-wide", carrier: "FedEx", service: "Ground", discount: 20},
+		{name: "first carrier-wide contract as fallback", carrier: "FedEx", service: "Express", discount: 10},
+		{name: "case and space insensitive", carrier: "FEDEX", service: "ground", discount: 20},
+		{name: "trimmed service-specific contract", carrier: "UPS", service: "Ground", discount: 40},
+		{name: "service-specific contract does not cover other services", carrier: "UPS", service: "Express"},
+		{name: "unknown carrier", carrier: "DHL", service: "Express"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindRateContract(contracts, tt.carrier, tt.service)
+			if tt.discount == 0 {
+				if got != nil {
+					t.Fatalf("FindRateContract(%q, %q) = %+v, want nil", tt.carrier, tt.service, got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("FindRateContract(%q, %q) = nil, want discount %v", tt.carrier, tt.service, tt.discount)
+			}
+			if got.DiscountPercent != tt.discount {
+				t.Errorf("FindRateContract(%q, %q) discount = %v, want %v", tt.carrier, tt.service, got.DiscountPercent, tt.discount)
+			}
+		})
+	}
+}
+
+func TestFindRateContractEmpty(t *testing.T) {
+	if got := FindRateContract(nil, "FedEx", "Ground"); got != nil {
+		t.Fatalf("FindRateContract(nil) = %+v, want nil", got)
+	}
+}
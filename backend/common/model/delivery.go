@@ -0,0 +1,46 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout 日期格式（承诺送达日、预计送达日等）
+const DateLayout = "2006-01-02"
+
+// 承诺送达状态常量
+const (
+	PromiseStatusOnTime = "ON_TIME" // 最晚送达日不晚于承诺日
+	PromiseStatusAtRisk = "AT_RISK" // 最早送达日不晚于承诺日，但最晚送达日晚于承诺日
+	PromiseStatusLate   = "LATE"    // 最早送达日已晚于承诺日，无法兑现
+)
+
+// DeliveryEstimate 预计送达日期区间（YYYY-MM-DD，目的地日期）
+type DeliveryEstimate struct {
+	ShipDate string `json:"ship_date"` // 预计揽收日（始发地日期）
+	Earliest string `json:"earliest"`  // 最早送达日
+	Latest   string `json:"latest"`    // 最晚送达日
+}
+
+// PromiseStatus 对比承诺送达日，返回 ON_TIME/AT_RISK/LATE（日期格式相同，可直接按字典序比较）
+func (e *DeliveryEstimate) PromiseStatus(promisedDate string) string {
+	switch {
+	case e.Latest <= promisedDate:
+		return PromiseStatusOnTime
+	case e.Earliest <= promisedDate:
+		return PromiseStatusAtRisk
+	default:
+		return PromiseStatusLate
+	}
+}
+
+// ValidateDate 校验日期格式（YYYY-MM-DD，空值视为未设置）
+func ValidateDate(date string) error {
+	if date == "" {
+		return nil
+	}
+	if _, err := time.Parse(DateLayout, date); err != nil {
+		return fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
+	}
+	return nil
+}
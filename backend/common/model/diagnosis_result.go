@@ -1,18 +1,26 @@
 package model
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // DiagnosisResultData 诊断结果容器
 type DiagnosisResultData struct {
-	Items []DiagnosisItem `json:"items"`
+	EngineVersion string           `json:"engine_version,omitempty"` // 诊断引擎版本（调度与结果结构）
+	Items         []DiagnosisItem  `json:"items"`
+	Partial       bool             `json:"partial"`               // 是否部分成功（任一诊断项失败或超时）
+	Traces        []DiagnoserTrace `json:"traces,omitempty"`      // 各诊断项的执行轨迹（仅 explain 模式，与 Items 一一对应）
+	Enrichments   []ItemEnrichment `json:"enrichments,omitempty"` // 下单时由商品目录补全的商品字段（诊断基于补全后的货件）
 }
 
 // DiagnosisItem 单个诊断项
 type DiagnosisItem struct {
-	Type     string          `json:"type"`      // shipping/anomaly/risk/compliance
+	Type     string          `json:"type"`      // shipping/anomaly/compliance/landed_cost/packaging/screening/risk/carbon/insurance/service_audit
 	Status   string          `json:"status"`    // SUCCESS/FAILED
 	DataJSON json.RawMessage `json:"data_json"` // 具体数据
 	Error    string          `json:"error,omitempty"`
+	Version  string          `json:"version,omitempty"` // 产生该结果的规则/费率卡等数据版本（诊断器实现 VersionedDiagnoser 时记录）
 }
 
 // 诊断项状态常量
@@ -23,6 +31,48 @@ const (
 
 // 诊断类型常量
 const (
-	DiagnosisTypeShipping = "shipping"
-	DiagnosisTypeAnomaly  = "anomaly"
+	DiagnosisTypeShipping     = "shipping"
+	DiagnosisTypeAnomaly      = "anomaly"
+	DiagnosisTypeCompliance   = "compliance"
+	DiagnosisTypeLandedCost   = "landed_cost"
+	DiagnosisTypePackaging    = "packaging"
+	DiagnosisTypeScreening    = "screening"
+	DiagnosisTypeRisk         = "risk"
+	DiagnosisTypeCarbon       = "carbon"
+	DiagnosisTypeInsurance    = "insurance"
+	DiagnosisTypeServiceAudit = "service_audit"
 )
+
+// DiagnosisTypes 所有可选的诊断类型（用于账号设置和下单请求的诊断器选择校验）
+var DiagnosisTypes = []string{
+	DiagnosisTypeShipping,
+	DiagnosisTypeAnomaly,
+	DiagnosisTypeCompliance,
+	DiagnosisTypeLandedCost,
+	DiagnosisTypePackaging,
+	DiagnosisTypeScreening,
+	DiagnosisTypeRisk,
+	DiagnosisTypeCarbon,
+	DiagnosisTypeInsurance,
+	DiagnosisTypeServiceAudit,
+}
+
+// IsValidDiagnosisType 判断诊断类型是否合法
+func IsValidDiagnosisType(diagnosisType string) bool {
+	for _, t := range DiagnosisTypes {
+		if t == diagnosisType {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDiagnosisTypes 校验诊断类型列表，返回第一个非法类型对应的错误
+func ValidateDiagnosisTypes(diagnosisTypes []string) error {
+	for _, t := range diagnosisTypes {
+		if !IsValidDiagnosisType(t) {
+			return fmt.Errorf("unknown diagnoser: %s (available: %v)", t, DiagnosisTypes)
+		}
+	}
+	return nil
+}
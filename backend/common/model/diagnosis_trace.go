@@ -0,0 +1,31 @@
+package model
+
+// DiagnoserTrace 单个诊断器的执行轨迹（explain 模式下记录，用于向商家解释诊断结论的来源）
+type DiagnoserTrace struct {
+	Type     string                 `json:"type"`                // 诊断类型（与 DiagnosisItem.Type 一致）
+	Inputs   map[string]interface{} `json:"inputs"`              // 诊断器实际使用的输入（如汇总重量、换算后的货值）
+	Rules    []TraceRule            `json:"rules"`               // 按执行顺序记录的规则评估
+	RateRows []TraceRateRow         `json:"rate_rows,omitempty"` // 使用的费率卡行（仅报价类诊断器）
+}
+
+// TraceRule 一条规则的评估记录
+type TraceRule struct {
+	Rule    string                 `json:"rule"`             // 规则标识（如 HEAVY_PACKAGE/SKU_MISSING）
+	Matched bool                   `json:"matched"`          // 是否命中（命中即产生对应的诊断结论）
+	Values  map[string]interface{} `json:"values,omitempty"` // 中间值（如实际值与阈值）
+}
+
+// TraceRateRow 报价使用的费率卡行
+// 运费 = (BaseRate + PerKg × WeightKg) × ZoneMultiplier；实时接口报价只记录结果金额
+type TraceRateRow struct {
+	Carrier        string  `json:"carrier"`
+	Service        string  `json:"service"`
+	Source         string  `json:"source"`                    // 费率卡版本，实时接口报价为 live
+	BaseRate       float64 `json:"base_rate,omitempty"`       // 首重费
+	PerKg          float64 `json:"per_kg,omitempty"`          // 续重单价
+	WeightKg       float64 `json:"weight_kg,omitempty"`       // 计价重量
+	Zone           string  `json:"zone,omitempty"`            // 命中的距离分区
+	ZoneMultiplier float64 `json:"zone_multiplier,omitempty"` // 分区系数
+	Amount         float64 `json:"amount"`                    // 报价金额
+	Currency       string  `json:"currency"`
+}
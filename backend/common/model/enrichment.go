@@ -0,0 +1,20 @@
+package model
+
+// ItemEnrichment 下单时由账号商品目录补全的商品字段
+// 随诊断任务下发并原样出现在诊断结果中，便于区分商家提供的数据与目录补全的数据
+type ItemEnrichment struct {
+	ParcelIndex int      `json:"parcel_index"` // 包裹序号（从 0 开始）
+	ItemIndex   int      `json:"item_index"`   // 商品在包裹内的序号（从 0 开始）
+	SKU         string   `json:"sku"`          // 匹配到的目录商品 SKU
+	Fields      []string `json:"fields"`       // 补全的字段（description/weight/dimension/hs_code/origin_country/battery）
+}
+
+// 补全字段常量（与货件 JSON 字段名一致）
+const (
+	EnrichedFieldDescription   = "description"
+	EnrichedFieldWeight        = "weight"
+	EnrichedFieldDimension     = "dimension"
+	EnrichedFieldHSCode        = "hs_code"
+	EnrichedFieldOriginCountry = "origin_country"
+	EnrichedFieldBattery       = "battery"
+)
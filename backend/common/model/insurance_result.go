@@ -0,0 +1,32 @@
+package model
+
+// InsuranceResult 运输保险建议
+type InsuranceResult struct {
+	Recommended     bool              `json:"recommended"`                // 是否建议为推荐承运商服务投保
+	RecommendedCode string            `json:"recommended_code,omitempty"` // shipping 诊断推荐的承运商服务（Carrier_Service）
+	Reasons         []string          `json:"reasons"`                    // 建议投保（或无需投保）的原因
+	DeclaredValue   *Money            `json:"declared_value,omitempty"`   // 申报货值（已换算为保险参考数据币种）
+	Lane            *InsuranceLane    `json:"lane,omitempty"`             // 线路丢损风险
+	Options         []InsuranceOption `json:"options"`                    // 各候选承运商服务的赔付责任与保费
+	Unavailable     string            `json:"unavailable,omitempty"`      // 无法给出建议的原因
+	TableVersion    string            `json:"table_version,omitempty"`    // 保险参考数据版本
+}
+
+// InsuranceLane 线路丢损风险
+type InsuranceLane struct {
+	Origin          string  `json:"origin"`
+	Destination     string  `json:"destination"`
+	LossRatePercent float64 `json:"loss_rate_percent"` // 丢失/损坏率（%）
+	HighLoss        bool    `json:"high_loss"`         // 是否为高丢损线路
+}
+
+// InsuranceOption 单个承运商服务的保险评估
+type InsuranceOption struct {
+	Carrier        string  `json:"carrier"`
+	Service        string  `json:"service"`
+	Liability      float64 `json:"liability"`         // 承运商默认赔付责任
+	UncoveredValue float64 `json:"uncovered_value"`   // 申报货值超出赔付责任的部分
+	Premium        float64 `json:"premium,omitempty"` // 足额投保的预估保费（货值未超出赔付责任时为 0）
+	Currency       string  `json:"currency"`
+	Recommended    bool    `json:"recommended"` // 是否建议投保
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // OrderDiagnoseJob 订单诊断任务消息（标准化）
 // 用于 dpmain → dpsync 的消息传递
 type OrderDiagnoseJob struct {
@@ -26,8 +28,26 @@ type OrderDiagnoseData struct {
 // OrderDiagnoseBusinessData 订单诊断业务数据
 // 包含 dpsync 执行诊断所需的所有数据（避免查询 DB）
 type OrderDiagnoseBusinessData struct {
-	OrderID         string                 `json:"order_id"`          // 订单 ID
-	AccountID       int64                  `json:"account_id"`        // 账户 ID
-	MerchantOrderNo string                 `json:"merchant_order_no"` // 商家订单号
-	Shipment        map[string]interface{} `json:"shipment"`          // 物流信息（TBC: 未来可定义为具体结构体）
+	OrderID              string                  `json:"order_id"`                         // 订单 ID
+	AccountID            int64                   `json:"account_id"`                       // 账户 ID
+	MerchantOrderNo      string                  `json:"merchant_order_no"`                // 商家订单号
+	Shipment             *Shipment               `json:"shipment"`                         // 物流信息
+	Diagnosers           []string                `json:"diagnosers,omitempty"`             // 需要执行的诊断类型（为空时执行全部）
+	PreferredCurrency    string                  `json:"preferred_currency,omitempty"`     // 账号偏好币种（为空时不换算）
+	Strategy             *RecommendationStrategy `json:"strategy,omitempty"`               // 费率推荐策略（为空时推荐最便宜）
+	Contracts            []RateContract          `json:"contracts,omitempty"`              // 账号协议价（下单时快照）
+	OrderCreatedAt       time.Time               `json:"order_created_at"`                 // 下单时间（时效估算起点）
+	PromisedDeliveryDate string                  `json:"promised_delivery_date,omitempty"` // 承诺送达日（YYYY-MM-DD，可选）
+	Boxes                []Box                   `json:"boxes,omitempty"`                  // 账号箱型目录（下单时快照，用于包装建议）
+	Incoterm             string                  `json:"incoterm,omitempty"`               // 贸易术语 DDP/DDU（为空时按 DDU 估算到岸成本）
+	Account              *AccountProfile         `json:"account,omitempty"`                // 下单账号概况（下单时快照，用于风险评分）
+	Selected             *SelectedService        `json:"selected,omitempty"`               // 商家选用的承运商服务与报价（可选，用于核对是否多付运费）
+	Enrichments          []ItemEnrichment        `json:"enrichments,omitempty"`            // 下单时由商品目录补全的商品字段（诊断结果原样带回）
+	Explain              bool                    `json:"explain,omitempty"`                // explain 模式：回调中附带各诊断器的执行轨迹
+}
+
+// AccountProfile 下单账号概况（下单时快照）
+type AccountProfile struct {
+	CreatedAt   time.Time `json:"created_at"`   // 账号注册时间
+	PriorOrders int64     `json:"prior_orders"` // 本单之前的订单数
 }
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 贸易术语（Incoterms）常量
+const (
+	IncotermDDP = "DDP" // 完税后交货：关税与进口税由发件人（商家）承担
+	IncotermDDU = "DDU" // 未完税交货：关税与进口税由收件人在派送时缴纳
+)
+
+// DefaultIncoterm 订单未指定贸易术语时按 DDU 处理（跨境小包的常见做法）
+const DefaultIncoterm = IncotermDDU
+
+// 到岸成本中税费承担方
+const (
+	LandedCostPaidBySender    = "SENDER"
+	LandedCostPaidByRecipient = "RECIPIENT"
+)
+
+// 到岸成本提示类型常量
+const (
+	LandedCostWarningDDURefusalRisk   = "DDU_REFUSAL_RISK"
+	LandedCostWarningMissingHSCode    = "MISSING_HS_CODE"
+	LandedCostWarningMissingPrice     = "MISSING_PRICE"
+	LandedCostWarningUnknownCurrency  = "UNKNOWN_CURRENCY"
+	LandedCostWarningTariffNotFound   = "TARIFF_NOT_FOUND"
+	LandedCostWarningDestinationUnset = "DESTINATION_UNSET"
+)
+
+// LandedCostResult 到岸成本诊断结果（进口关税与增值税/GST 估算）
+// 金额均以目的国关税计算币种表示
+type LandedCostResult struct {
+	Destination          string              `json:"destination"`                 // 目的国（ISO 两位代码）
+	Incoterm             string              `json:"incoterm"`                    // DDP/DDU
+	PaidBy               string              `json:"paid_by"`                     // 税费承担方：SENDER/RECIPIENT
+	Domestic             bool                `json:"domestic"`                    // 国内件（无进口税费）
+	Currency             string              `json:"currency,omitempty"`          // 计算币种（目的国本币）
+	GoodsValue           float64             `json:"goods_value"`                 // 申报货值
+	Duty                 float64             `json:"duty"`                        // 进口关税
+	TaxName              string              `json:"tax_name,omitempty"`          // 进口环节税名称（VAT/GST 等）
+	TaxPercent           float64             `json:"tax_percent"`                 // 进口环节税率（百分比）
+	Tax                  float64             `json:"tax"`                         // 进口环节税
+	ClearanceFee         float64             `json:"clearance_fee"`               // 承运商代垫税费手续费
+	TotalCharges         float64             `json:"total_charges"`               // 税费合计 = 关税 + 进口税 + 手续费
+	LandedCost           float64             `json:"landed_cost"`                 // 到岸成本 = 货值 + 税费合计（不含运费）
+	PreferredCharges     *Money              `json:"preferred_charges,omitempty"` // 税费合计换算为账号偏好币种
+	DutyDeMinimisApplied bool                `json:"duty_de_minimis_applied"`     // 货值未超过关税起征点
+	TaxDeMinimisApplied  bool                `json:"tax_de_minimis_applied"`      // 货值未超过进口税起征点
+	Items                []LandedCostItem    `json:"items"`                       // 逐项关税明细
+	Warnings             []LandedCostWarning `json:"warnings"`                    // 提示（如 DDU 拒收风险）
+	ExchangeRates        []ExchangeRate      `json:"exchange_rates,omitempty"`    // 换算使用的汇率
+	TariffVersion        string              `json:"tariff_version,omitempty"`    // 税率表版本
+}
+
+// LandedCostItem 单个商品的关税明细
+type LandedCostItem struct {
+	ParcelIndex int     `json:"parcel_index"` // 包裹序号（从 0 开始）
+	ItemIndex   int     `json:"item_index"`   // 商品在包裹内的序号（从 0 开始）
+	SKU         string  `json:"sku,omitempty"`
+	HSChapter   string  `json:"hs_chapter,omitempty"` // HS 编码章（前两位，缺失时为空）
+	Value       float64 `json:"value"`                // 货值（单价 × 数量，已换算为计算币种）
+	DutyPercent float64 `json:"duty_percent"`         // 适用关税税率（百分比）
+	Duty        float64 `json:"duty"`                 // 关税（低于起征点时为 0）
+}
+
+// LandedCostWarning 到岸成本提示
+type LandedCostWarning struct {
+	Type    string `json:"type"`    // DDU_REFUSAL_RISK/MISSING_HS_CODE/MISSING_PRICE/UNKNOWN_CURRENCY/TARIFF_NOT_FOUND/DESTINATION_UNSET
+	Level   string `json:"level"`   // INFO/WARNING/CRITICAL
+	Message string `json:"message"` // 人类可读描述
+}
+
+// NormalizeIncoterm 归一化贸易术语（去空格并转大写）
+func NormalizeIncoterm(incoterm string) string {
+	return strings.ToUpper(strings.TrimSpace(incoterm))
+}
+
+// ValidateIncoterm 校验贸易术语（空值视为未设置）
+func ValidateIncoterm(incoterm string) error {
+	switch NormalizeIncoterm(incoterm) {
+	case "", IncotermDDP, IncotermDDU:
+		return nil
+	default:
+		return fmt.Errorf("invalid incoterm: %s (available: %s, %s)", incoterm, IncotermDDP, IncotermDDU)
+	}
+}
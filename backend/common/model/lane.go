@@ -0,0 +1,25 @@
+package model
+
+// Lane 运输线路（始发地 → 收件地的地理信息，由离线地理编码得出）
+type Lane struct {
+	Type        string       `json:"type"`                  // DOMESTIC/INTERNATIONAL
+	Origin      *GeoLocation `json:"origin,omitempty"`      // 始发地坐标（国家未登记时为空）
+	Destination *GeoLocation `json:"destination,omitempty"` // 收件地坐标（国家未登记时为空）
+	DistanceKm  *float64     `json:"distance_km,omitempty"` // 大圆距离（任一端无法定位，或国内线路只能定位到国家时为空）
+	GeoVersion  string       `json:"geo_version,omitempty"` // 邮编质心数据版本
+}
+
+// GeoLocation 地理编码结果
+type GeoLocation struct {
+	Country      string  `json:"country"`
+	PostalPrefix string  `json:"postal_prefix,omitempty"` // 命中的邮编前缀
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	Precision    string  `json:"precision"` // POSTAL（邮编前缀质心）/COUNTRY（国家质心）
+}
+
+// 线路类型常量
+const (
+	LaneTypeDomestic      = "DOMESTIC"
+	LaneTypeInternational = "INTERNATIONAL"
+)
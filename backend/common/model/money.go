@@ -0,0 +1,42 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Money 金额
+type Money struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// ExchangeRate 诊断使用的汇率（写入诊断结果，便于审计）
+type ExchangeRate struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Rate    float64 `json:"rate"`    // 1 From = Rate To
+	Version string  `json:"version"` // 汇率表版本
+}
+
+// NormalizeCurrency 归一化币种代码（去空格并转大写）
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// ValidateCurrencyCode 校验币种代码格式（ISO 4217 三位字母，空值视为未设置）
+func ValidateCurrencyCode(currency string) error {
+	code := NormalizeCurrency(currency)
+	if code == "" {
+		return nil
+	}
+	if len(code) != 3 {
+		return fmt.Errorf("invalid currency code: %s", currency)
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return fmt.Errorf("invalid currency code: %s", currency)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Box 账号箱型目录中的纸箱（内径尺寸）
+type Box struct {
+	Name      string     `json:"name"`                 // 箱型名称（账号内唯一）
+	Dimension *Dimension `json:"dimension"`            // 内径尺寸
+	MaxWeight *Weight    `json:"max_weight,omitempty"` // 最大承重（为空表示不限）
+}
+
+// Validate 校验箱型（名称必填，尺寸必须为正数）
+func (b *Box) Validate() error {
+	if strings.TrimSpace(b.Name) == "" {
+		return fmt.Errorf("box name is required")
+	}
+	if b.Dimension == nil {
+		return fmt.Errorf("box %s: dimension is required", b.Name)
+	}
+	if err := b.Dimension.validate("box " + b.Name + " dimension"); err != nil {
+		return err
+	}
+	if b.Dimension.Width <= 0 || b.Dimension.Height <= 0 || b.Dimension.Depth <= 0 {
+		return fmt.Errorf("box %s: dimension must be positive", b.Name)
+	}
+	if err := b.MaxWeight.validate("box " + b.Name + " max_weight"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateBoxes 校验箱型目录（逐项校验且名称不重复）
+func ValidateBoxes(boxes []Box) error {
+	seen := make(map[string]bool, len(boxes))
+	for i := range boxes {
+		if err := boxes[i].Validate(); err != nil {
+			return err
+		}
+		name := strings.ToUpper(strings.TrimSpace(boxes[i].Name))
+		if seen[name] {
+			return fmt.Errorf("duplicate box name: %s", boxes[i].Name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// CubicCentimeters 体积（立方厘米，尺寸缺失时为 0）
+func (d *Dimension) CubicCentimeters() float64 {
+	width, height, depth := d.Centimeters()
+	return width * height * depth
+}
+
+// PackagingResult 包装诊断结果（重量一致性、密度合理性与箱型建议）
+type PackagingResult struct {
+	HasIssue         bool                     `json:"has_issue"`
+	DimDivisor       float64                  `json:"dim_divisor"`             // 体积重除数（cm³/kg）
+	BillableWeightKg float64                  `json:"billable_weight_kg"`      // 当前计费重合计（各包裹实重与体积重取大）
+	Parcels          []ParcelPackaging        `json:"parcels"`                 // 逐包裹诊断
+	Consolidation    *ConsolidationSuggestion `json:"consolidation,omitempty"` // 合箱建议（多包裹时）
+	BoxCount         int                      `json:"box_count"`               // 参与推荐的账号箱型数量
+}
+
+// ParcelPackaging 单个包裹的包装诊断
+type ParcelPackaging struct {
+	ParcelIndex      int              `json:"parcel_index"`         // 包裹序号（从 0 开始）
+	WeightKg         float64          `json:"weight_kg"`            // 包裹申报重量
+	ItemsWeightKg    float64          `json:"items_weight_kg"`      // 商品重量合计（单件重量 × 数量，未填重量的商品不计）
+	VolumeCm3        float64          `json:"volume_cm3"`           // 包裹体积（无尺寸时为 0）
+	DensityKgPerM3   float64          `json:"density_kg_per_m3"`    // 密度（无尺寸时为 0）
+	DimWeightKg      float64          `json:"dim_weight_kg"`        // 体积重
+	BillableWeightKg float64          `json:"billable_weight_kg"`   // 计费重
+	Issues           []PackagingIssue `json:"issues"`               // 发现的问题
+	Suggestion       *BoxSuggestion   `json:"suggestion,omitempty"` // 更小箱型建议
+}
+
+// BoxSuggestion 箱型建议
+type BoxSuggestion struct {
+	Box              string  `json:"box"`                // 建议箱型名称
+	DimWeightKg      float64 `json:"dim_weight_kg"`      // 换箱后的体积重
+	BillableWeightKg float64 `json:"billable_weight_kg"` // 换箱后的计费重
+	SavingKg         float64 `json:"saving_kg"`          // 计费重减少量
+}
+
+// ConsolidationSuggestion 合箱建议：多个包裹合并为一箱
+type ConsolidationSuggestion struct {
+	Box                     string  `json:"box"`                        // 建议箱型名称
+	ParcelIndexes           []int   `json:"parcel_indexes"`             // 参与合并的包裹序号
+	CurrentBillableWeightKg float64 `json:"current_billable_weight_kg"` // 合并前计费重合计
+	BillableWeightKg        float64 `json:"billable_weight_kg"`         // 合并后计费重
+	SavingKg                float64 `json:"saving_kg"`                  // 计费重减少量
+}
+
+// PackagingIssue 包装问题
+type PackagingIssue struct {
+	Type    string `json:"type"`    // WEIGHT_BELOW_ITEMS/DENSITY_TOO_HIGH/DENSITY_TOO_LOW/MISSING_DIMENSION
+	Level   string `json:"level"`   // INFO/WARNING/CRITICAL
+	Message string `json:"message"` // 人类可读描述
+}
+
+// 包装问题类型常量
+const (
+	PackagingTypeWeightBelowItems = "WEIGHT_BELOW_ITEMS"
+	PackagingTypeDensityTooHigh   = "DENSITY_TOO_HIGH"
+	PackagingTypeDensityTooLow    = "DENSITY_TOO_LOW"
+	PackagingTypeMissingDimension = "MISSING_DIMENSION"
+)
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 推荐策略常量
+const (
+	RecommendStrategyCheapest       = "CHEAPEST"        // 总运费最低
+	RecommendStrategyFastest        = "FASTEST"         // 时效最短
+	RecommendStrategyCheapestWithin = "CHEAPEST_WITHIN" // 指定天数内送达的最低运费
+	RecommendStrategyWeighted       = "WEIGHTED"        // 运费、时效、承运商可靠性加权评分
+)
+
+// RecommendStrategies 所有可选的推荐策略
+var RecommendStrategies = []string{
+	RecommendStrategyCheapest,
+	RecommendStrategyFastest,
+	RecommendStrategyCheapestWithin,
+	RecommendStrategyWeighted,
+}
+
+// RecommendationStrategy 费率推荐策略（账号级设置，下单请求可覆盖）
+type RecommendationStrategy struct {
+	Name           string           `json:"name"`                       // CHEAPEST/FASTEST/CHEAPEST_WITHIN/WEIGHTED
+	MaxTransitDays int              `json:"max_transit_days,omitempty"` // CHEAPEST_WITHIN 时必填
+	Weights        *StrategyWeights `json:"weights,omitempty"`          // WEIGHTED 时必填
+}
+
+// StrategyWeights 加权评分权重（非负，至少一项大于 0，无需归一化）
+type StrategyWeights struct {
+	Cost        float64 `json:"cost"`
+	TransitTime float64 `json:"transit_time"`
+	Reliability float64 `json:"reliability"`
+}
+
+// DefaultRecommendationStrategy 未设置策略时使用的默认策略
+func DefaultRecommendationStrategy() *RecommendationStrategy {
+	return &RecommendationStrategy{Name: RecommendStrategyCheapest}
+}
+
+// Normalize 归一化策略名（去空格并转大写）
+func (s *RecommendationStrategy) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Name = strings.ToUpper(strings.TrimSpace(s.Name))
+}
+
+// Validate 校验策略参数（nil 视为未设置）
+func (s *RecommendationStrategy) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	switch s.Name {
+	case RecommendStrategyCheapest, RecommendStrategyFastest:
+		return nil
+
+	case RecommendStrategyCheapestWithin:
+		if s.MaxTransitDays <= 0 {
+			return fmt.Errorf("recommendation strategy %s requires max_transit_days > 0", s.Name)
+		}
+		return nil
+
+	case RecommendStrategyWeighted:
+		w := s.Weights
+		if w == nil {
+			return fmt.Errorf("recommendation strategy %s requires weights", s.Name)
+		}
+		if w.Cost < 0 || w.TransitTime < 0 || w.Reliability < 0 {
+			return fmt.Errorf("recommendation strategy weights cannot be negative")
+		}
+		if w.Cost+w.TransitTime+w.Reliability == 0 {
+			return fmt.Errorf("recommendation strategy weights cannot all be zero")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown recommendation strategy: %s (available: %v)", s.Name, RecommendStrategies)
+	}
+}
+
+// Key 策略的规范化字符串（用于诊断结果缓存键，nil 返回空串）
+func (s *RecommendationStrategy) Key() string {
+	if s == nil {
+		return ""
+	}
+
+	switch s.Name {
+	case RecommendStrategyCheapestWithin:
+		return s.Name + ":" + strconv.Itoa(s.MaxTransitDays)
+	case RecommendStrategyWeighted:
+		if s.Weights == nil {
+			return s.Name
+		}
+		return fmt.Sprintf("%s:%g/%g/%g", s.Name, s.Weights.Cost, s.Weights.TransitTime, s.Weights.Reliability)
+	default:
+		return s.Name
+	}
+}
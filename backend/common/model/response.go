@@ -25,6 +25,7 @@ const (
 	ResponseTypeOK              = "OK"
 	ResponseTypeValidationError = "ValidationError"
 	ResponseTypeNotFound        = "NotFound"
+	ResponseTypeUnauthorized    = "Unauthorized"
 	ResponseTypeInternalError   = "InternalError"
 	ResponseTypeProcessing      = "Processing"
 )
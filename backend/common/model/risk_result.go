@@ -0,0 +1,33 @@
+package model
+
+// RiskResult 订单风险评分结果
+type RiskResult struct {
+	Score        int          `json:"score"`                   // 风险分（0-100，命中信号权重之和，超过 100 按 100 计）
+	Level        string       `json:"level"`                   // LOW/MEDIUM/HIGH
+	Factors      []RiskFactor `json:"factors"`                 // 命中的风险信号（按贡献分降序）
+	Unavailable  []string     `json:"unavailable,omitempty"`   // 因数据缺失或依赖不可用而未评估的信号
+	RulesVersion string       `json:"rules_version,omitempty"` // 风险规则版本
+}
+
+// RiskFactor 命中的风险信号
+type RiskFactor struct {
+	Signal  string `json:"signal"`  // 信号类型
+	Points  int    `json:"points"`  // 对风险分的贡献
+	Message string `json:"message"` // 命中原因
+}
+
+// 风险等级常量
+const (
+	RiskLevelLow    = "LOW"
+	RiskLevelMedium = "MEDIUM"
+	RiskLevelHigh   = "HIGH"
+)
+
+// 风险信号常量
+const (
+	RiskSignalForwarderHighValue   = "HIGH_VALUE_TO_FORWARDER"  // 高货值寄往货代/转运地址
+	RiskSignalPhoneCountryMismatch = "PHONE_COUNTRY_MISMATCH"   // 收件人电话国际区号与目的国不一致
+	RiskSignalDisposableEmail      = "DISPOSABLE_EMAIL"         // 收件人邮箱为一次性邮箱域名
+	RiskSignalNewAccountFirstOrder = "NEW_ACCOUNT_FIRST_ORDER"  // 新注册账号的首单
+	RiskSignalRecipientVelocity    = "RECIPIENT_ORDER_VELOCITY" // 短时间内寄往同一收件人的订单过多
+)
@@ -0,0 +1,34 @@
+package model
+
+// ScreeningResult 拒绝往来方筛查结果
+type ScreeningResult struct {
+	ReviewLevel string           `json:"review_level"`           // 整体结论：CLEAR/REVIEW/BLOCK（取各命中项最高级别）
+	Matches     []ScreeningMatch `json:"matches"`                // 命中的名单条目（按得分降序，每个条目只保留最高分字段）
+	Screened    []string         `json:"screened"`               // 参与筛查的收件方字段
+	ListVersion string           `json:"list_version,omitempty"` // 名单版本
+}
+
+// ScreeningMatch 单个名单条目命中
+type ScreeningMatch struct {
+	EntryID          string  `json:"entry_id"`           // 名单条目 ID
+	EntryName        string  `json:"entry_name"`         // 名单主体名称
+	MatchedName      string  `json:"matched_name"`       // 实际命中的名称（主体名称或别名）
+	EntryType        string  `json:"entry_type"`         // INDIVIDUAL/ENTITY
+	Program          string  `json:"program,omitempty"`  // 制裁项目
+	Source           string  `json:"source,omitempty"`   // 来源名单
+	Field            string  `json:"field"`              // 命中的收件方字段：contact_name/company_name/address
+	Query            string  `json:"query"`              // 收件方字段原值
+	Score            float64 `json:"score"`              // 综合得分（0-1）
+	TokenSetScore    float64 `json:"token_set_score"`    // 词元集合相似度
+	JaroWinklerScore float64 `json:"jaro_winkler_score"` // Jaro-Winkler 相似度
+	CountryMatch     bool    `json:"country_match"`      // 名单条目所在国与收件国一致（条目未登记国家时视为一致）
+	AddressMatch     bool    `json:"address_match"`      // 收件地址与名单条目地址相似
+	ReviewLevel      string  `json:"review_level"`       // REVIEW/BLOCK
+}
+
+// 筛查结论常量
+const (
+	ScreeningLevelClear  = "CLEAR"  // 未命中
+	ScreeningLevelReview = "REVIEW" // 疑似命中，需人工复核
+	ScreeningLevelBlock  = "BLOCK"  // 高度疑似命中，发货前必须复核放行
+)
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SelectedService 商家实际选用的承运商服务（下单时可选提供，用于核对是否多付运费）
+type SelectedService struct {
+	Carrier     string `json:"carrier"`                // 承运商
+	Service     string `json:"service"`                // 服务名称
+	QuotedPrice *Money `json:"quoted_price,omitempty"` // 商家拿到的报价（为空时按本系统计算的该服务运费核对）
+}
+
+// Normalize 归一化（去除首尾空格，币种转大写；nil 安全）
+func (s *SelectedService) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Carrier = strings.TrimSpace(s.Carrier)
+	s.Service = strings.TrimSpace(s.Service)
+	if s.QuotedPrice != nil {
+		s.QuotedPrice.Currency = NormalizeCurrency(s.QuotedPrice.Currency)
+	}
+}
+
+// Validate 校验选用服务（nil 视为未提供）
+func (s *SelectedService) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if strings.TrimSpace(s.Carrier) == "" || strings.TrimSpace(s.Service) == "" {
+		return fmt.Errorf("selected_service.carrier and selected_service.service are required")
+	}
+	if s.QuotedPrice != nil {
+		if s.QuotedPrice.Amount < 0 {
+			return fmt.Errorf("selected_service.quoted_price.amount cannot be negative")
+		}
+		if strings.TrimSpace(s.QuotedPrice.Currency) == "" {
+			return fmt.Errorf("selected_service.quoted_price.currency is required")
+		}
+		if err := ValidateCurrencyCode(s.QuotedPrice.Currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Code 承运商服务代码（Carrier_Service，与 ShippingResult.RecommendedCode 格式一致）
+func (s *SelectedService) Code() string {
+	return fmt.Sprintf("%s_%s", s.Carrier, s.Service)
+}
+
+// ServiceAuditResult 商家选用服务核对结果
+type ServiceAuditResult struct {
+	Selected         *SelectedService          `json:"selected,omitempty"`      // 商家选用的服务
+	Matched          bool                      `json:"matched"`                 // 选用服务是否在本次计算的费率中
+	SelectedFee      float64                   `json:"selected_fee,omitempty"`  // 核对基准运费（有报价时为报价换算后的金额，否则为计算出的该服务运费）
+	SelectedDays     int                       `json:"selected_days,omitempty"` // 选用服务时效（天，未匹配时为 0）
+	Currency         string                    `json:"currency,omitempty"`      // 金额币种（与 shipping 诊断费率币种一致）
+	CheapestCode     string                    `json:"cheapest_code,omitempty"` // 最便宜的服务（Carrier_Service）
+	PotentialSavings float64                   `json:"potential_savings"`       // 改用最便宜服务可节省的运费（不多付时为 0）
+	Overpaying       bool                      `json:"overpaying"`              // 是否存在更便宜的可用服务
+	Alternatives     []ServiceAuditAlternative `json:"alternatives"`            // 更便宜或更快的替代服务（按节省金额降序）
+	Unavailable      string                    `json:"unavailable,omitempty"`   // 无法核对的原因（如未提供选用服务）
+}
+
+// ServiceAuditAlternative 替代服务
+type ServiceAuditAlternative struct {
+	Carrier     string  `json:"carrier"`
+	Service     string  `json:"service"`
+	TotalFee    float64 `json:"total_fee"`
+	TransitDays int     `json:"transit_days"`
+	Savings     float64 `json:"savings"`    // 相对核对基准运费节省的金额（为负表示更贵）
+	DaysDelta   int     `json:"days_delta"` // 相对选用服务的时效差（天，为正表示更慢；选用服务未匹配时为 0）
+	Comparison  string  `json:"comparison"` // CHEAPER/CHEAPER_FASTER/CHEAPER_SAME_SPEED/CHEAPER_SLOWER/FASTER_COSTLIER
+}
+
+// 替代服务对比常量
+const (
+	ServiceAuditCheaper          = "CHEAPER" // 更便宜（选用服务未匹配，无法比较时效）
+	ServiceAuditCheaperFaster    = "CHEAPER_FASTER"
+	ServiceAuditCheaperSameSpeed = "CHEAPER_SAME_SPEED"
+	ServiceAuditCheaperSlower    = "CHEAPER_SLOWER"
+	ServiceAuditFasterCostlier   = "FASTER_COSTLIER"
+)
@@ -0,0 +1,298 @@
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Shipment 货件信息（dpmain → dpsync 诊断任务中的标准结构）
+type Shipment struct {
+	ShipFrom *Address  `json:"ship_from"`
+	ShipTo   *Address  `json:"ship_to"`
+	Parcels  []*Parcel `json:"parcels"`
+}
+
+// Address 地址
+type Address struct {
+	ContactName string `json:"contact_name,omitempty"`
+	CompanyName string `json:"company_name,omitempty"`
+	Street1     string `json:"street1,omitempty"`
+	Street2     string `json:"street2,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty"`
+	Country     string `json:"country"`
+	Phone       string `json:"phone,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+// Parcel 包裹
+type Parcel struct {
+	Weight    *Weight    `json:"weight"`
+	Dimension *Dimension `json:"dimension,omitempty"`
+	Items     []*Item    `json:"items"`
+}
+
+// Weight 重量
+type Weight struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"` // kg/g/lb/oz
+}
+
+// Dimension 尺寸
+type Dimension struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Depth  float64 `json:"depth"`
+	Unit   string  `json:"unit"` // cm/mm/m/in
+}
+
+// Item 商品
+type Item struct {
+	Description   string     `json:"description,omitempty"`
+	Quantity      int        `json:"quantity"`
+	Price         *Money     `json:"price,omitempty"`
+	SKU           string     `json:"sku,omitempty"`
+	Weight        *Weight    `json:"weight,omitempty"`         // 单件重量
+	Dimension     *Dimension `json:"dimension,omitempty"`      // 单件尺寸
+	HSCode        string     `json:"hs_code,omitempty"`        // 海关编码
+	OriginCountry string     `json:"origin_country,omitempty"` // 原产国
+	Battery       *Battery   `json:"battery,omitempty"`        // 所含电池（为空表示未申报）
+}
+
+// Battery 商品所含电池
+type Battery struct {
+	Chemistry string `json:"chemistry"`         // LITHIUM_ION/LITHIUM_METAL/OTHER
+	Packing   string `json:"packing,omitempty"` // STANDALONE/PACKED_WITH_EQUIPMENT/CONTAINED_IN_EQUIPMENT（为空按单独运输处理）
+}
+
+// 电池类型常量
+const (
+	BatteryChemistryLithiumIon   = "LITHIUM_ION"   // 锂离子电池（UN3480/UN3481）
+	BatteryChemistryLithiumMetal = "LITHIUM_METAL" // 锂金属电池（UN3090/UN3091）
+	BatteryChemistryOther        = "OTHER"         // 干电池、镍氢电池等非锂电池
+)
+
+// 电池包装方式常量
+const (
+	BatteryPackingStandalone  = "STANDALONE"             // 单独运输的电池（含充电宝）
+	BatteryPackingWithEquip   = "PACKED_WITH_EQUIPMENT"  // 与设备同包装
+	BatteryPackingInEquipment = "CONTAINED_IN_EQUIPMENT" // 安装在设备中
+)
+
+// weightToKg 重量单位 → 千克换算系数
+var weightToKg = map[string]float64{
+	"kg": 1,
+	"g":  0.001,
+	"lb": 0.45359237,
+	"oz": 0.028349523125,
+}
+
+// dimensionToCm 长度单位 → 厘米换算系数
+var dimensionToCm = map[string]float64{
+	"cm": 1,
+	"mm": 0.1,
+	"m":  100,
+	"in": 2.54,
+}
+
+// DecodeShipment 严格解码货件信息（拒绝未知字段）并做结构校验
+func DecodeShipment(data []byte) (*Shipment, error) {
+	if len(bytes.TrimSpace(data)) == 0 || bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil, fmt.Errorf("shipment is required")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var shipment Shipment
+	if err := decoder.Decode(&shipment); err != nil {
+		return nil, fmt.Errorf("decode shipment failed: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("decode shipment failed: unexpected data after shipment")
+	}
+
+	if err := shipment.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+// Validate 结构校验（字段类型、单位与数值范围）
+// 业务层面的缺失（如无包裹、无 SKU）由诊断规则报告，不在此拒绝
+func (s *Shipment) Validate() error {
+	if s.ShipFrom != nil {
+		if err := s.ShipFrom.validate("ship_from"); err != nil {
+			return err
+		}
+	}
+	if s.ShipTo != nil {
+		if err := s.ShipTo.validate("ship_to"); err != nil {
+			return err
+		}
+	}
+
+	for i, parcel := range s.Parcels {
+		path := fmt.Sprintf("parcels[%d]", i)
+		if parcel == nil {
+			return fmt.Errorf("%s cannot be null", path)
+		}
+		if err := parcel.Weight.validate(path + ".weight"); err != nil {
+			return err
+		}
+		if err := parcel.Dimension.validate(path + ".dimension"); err != nil {
+			return err
+		}
+
+		for j, item := range parcel.Items {
+			itemPath := fmt.Sprintf("%s.items[%d]", path, j)
+			if item == nil {
+				return fmt.Errorf("%s cannot be null", itemPath)
+			}
+			if item.Quantity < 0 {
+				return fmt.Errorf("%s.quantity cannot be negative", itemPath)
+			}
+			if item.Price != nil && item.Price.Amount < 0 {
+				return fmt.Errorf("%s.price.amount cannot be negative", itemPath)
+			}
+			if err := item.ValidateAttributes(itemPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateAttributes 校验商品的重量、尺寸与电池信息（下单货件与商品目录共用）
+func (i *Item) ValidateAttributes(path string) error {
+	if err := i.Weight.validate(path + ".weight"); err != nil {
+		return err
+	}
+	if err := i.Dimension.validate(path + ".dimension"); err != nil {
+		return err
+	}
+	return i.Battery.validate(path + ".battery")
+}
+
+// IsLithium 是否为锂电池（航空运输按危险品处理）
+func (b *Battery) IsLithium() bool {
+	if b == nil {
+		return false
+	}
+	return b.Chemistry == BatteryChemistryLithiumIon || b.Chemistry == BatteryChemistryLithiumMetal
+}
+
+// IsStandalone 是否单独运输（包装方式为空时按单独运输处理）
+func (b *Battery) IsStandalone() bool {
+	return b != nil && (b.Packing == "" || b.Packing == BatteryPackingStandalone)
+}
+
+// Fingerprint 货件规范化哈希
+// 序列化字段顺序固定，相同内容的货件得到相同指纹（用于结果缓存与确定性 Mock 报价）
+func (s *Shipment) Fingerprint() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TotalWeightKg 包裹总重量（千克）
+func (s *Shipment) TotalWeightKg() float64 {
+	total := 0.0
+	for _, parcel := range s.Parcels {
+		total += parcel.Weight.Kilograms()
+	}
+	return total
+}
+
+// Kilograms 重量换算为千克（单位缺省按千克处理）
+func (w *Weight) Kilograms() float64 {
+	if w == nil {
+		return 0
+	}
+	factor, ok := weightToKg[strings.ToLower(strings.TrimSpace(w.Unit))]
+	if !ok {
+		factor = 1
+	}
+	return w.Value * factor
+}
+
+// Centimeters 尺寸换算为厘米（单位缺省按厘米处理）
+func (d *Dimension) Centimeters() (width, height, depth float64) {
+	if d == nil {
+		return 0, 0, 0
+	}
+	factor, ok := dimensionToCm[strings.ToLower(strings.TrimSpace(d.Unit))]
+	if !ok {
+		factor = 1
+	}
+	return d.Width * factor, d.Height * factor, d.Depth * factor
+}
+
+// validate 地址校验
+func (a *Address) validate(path string) error {
+	if strings.TrimSpace(a.Country) == "" {
+		return fmt.Errorf("%s.country is required", path)
+	}
+	return nil
+}
+
+// validate 重量校验（nil 视为未提供）
+func (w *Weight) validate(path string) error {
+	if w == nil {
+		return nil
+	}
+	if w.Value < 0 {
+		return fmt.Errorf("%s.value cannot be negative", path)
+	}
+	if unit := strings.ToLower(strings.TrimSpace(w.Unit)); unit != "" {
+		if _, ok := weightToKg[unit]; !ok {
+			return fmt.Errorf("%s.unit %q is not supported", path, w.Unit)
+		}
+	}
+	return nil
+}
+
+// validate 电池校验（nil 视为未申报）
+func (b *Battery) validate(path string) error {
+	if b == nil {
+		return nil
+	}
+	switch b.Chemistry {
+	case BatteryChemistryLithiumIon, BatteryChemistryLithiumMetal, BatteryChemistryOther:
+	default:
+		return fmt.Errorf("%s.chemistry %q is not supported", path, b.Chemistry)
+	}
+	switch b.Packing {
+	case "", BatteryPackingStandalone, BatteryPackingWithEquip, BatteryPackingInEquipment:
+	default:
+		return fmt.Errorf("%s.packing %q is not supported", path, b.Packing)
+	}
+	return nil
+}
+
+// validate 尺寸校验（nil 视为未提供）
+func (d *Dimension) validate(path string) error {
+	if d == nil {
+		return nil
+	}
+	if d.Width < 0 || d.Height < 0 || d.Depth < 0 {
+		return fmt.Errorf("%s cannot be negative", path)
+	}
+	if unit := strings.ToLower(strings.TrimSpace(d.Unit)); unit != "" {
+		if _, ok := dimensionToCm[unit]; !ok {
+			return fmt.Errorf("%s.unit %q is not supported", path, d.Unit)
+		}
+	}
+	return nil
+}
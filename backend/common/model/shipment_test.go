@@ -0,0 +1,59 @@
+package model
+
+import "testing"
+
+func TestShipmentFingerprint(t *testing.T) {
+	base := `{"ship_from":{"country":"US","postal_code":"10001"},"ship_to":{"country":"DE"},"parcels":[{"weight":{"value":1.5,"unit":"kg"},"items":[{"sku":"A-1","quantity":2}]}]}`
+
+	tests := []struct {
+		name  string
+		other string
+		same  bool
+	}{
+		{
+			name:  "identical content",
+			other: base,
+			same:  true,
+		},
+		{
+			name:  "key order and whitespace do not matter",
+			other: `{"parcels":[{"items":[{"quantity":2,"sku":"A-1"}],"weight":{"unit":"kg","value":1.5}}], "ship_to":{"country":"DE"}, "ship_from":{"postal_code":"10001","country":"US"}}`,
+			same:  true,
+		},
+		{
+			name:  "different weight",
+			other: `{"ship_from":{"country":"US","postal_code":"10001"},"ship_to":{"country":"DE"},"parcels":[{"weight":{"value":1.6,"unit":"kg"},"items":[{"sku":"A-1","quantity":2}]}]}`,
+		},
+		{
+			name:  "different destination",
+			other: `{"ship_from":{"country":"US","postal_code":"10001"},"ship_to":{"country":"FR"},"parcels":[{"weight":{"value":1.5,"unit":"kg"},"items":[{"sku":"A-1","quantity":2}]}]}`,
+		},
+		{
+			name:  "different item quantity",
+			other: `{"ship_from":{"country":"US","postal_code":"10001"},"ship_to":{"country":"DE"},"parcels":[{"weight":{"value":1.5,"unit":"kg"},"items":[{"sku":"A-1","quantity":3}]}]}`,
+		},
+	}
+
+	want := mustDecodeShipment(t, base).Fingerprint()
+	if want == "" {
+		t.Fatal("Fingerprint() returned empty string")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustDecodeShipment(t, tt.other).Fingerprint()
+			if (got == want) != tt.same {
+				t.Errorf("Fingerprint() equal = %v, want %v", got == want, tt.same)
+			}
+		})
+	}
+}
+
+func mustDecodeShipment(t *testing.T, data string) *Shipment {
+	t.Helper()
+	shipment, err := DecodeShipment([]byte(data))
+	if err != nil {
+		t.Fatalf("DecodeShipment: %v", err)
+	}
+	return shipment
+}
@@ -2,15 +2,93 @@ package model
 
 // ShippingResult 物流费率诊断结果
 type ShippingResult struct {
-	RecommendedCode string         `json:"recommended_code"`
-	Rates           []ShippingRate `json:"rates"`
+	RecommendedCode      string                  `json:"recommended_code"`
+	Strategy             *RecommendationStrategy `json:"strategy,omitempty"`         // 本次使用的推荐策略
+	RecommendReason      string                  `json:"recommend_reason,omitempty"` // 推荐理由
+	Rates                []ShippingRate          `json:"rates"`
+	PreferredCurrency    string                  `json:"preferred_currency,omitempty"`     // 账号偏好币种
+	ExchangeRate         *ExchangeRate           `json:"exchange_rate,omitempty"`          // 费率币种 → 偏好币种所用汇率
+	AddressType          string                  `json:"address_type,omitempty"`           // 收件地址类型：RESIDENTIAL/COMMERCIAL/UNKNOWN
+	AddressTypeReason    string                  `json:"address_type_reason,omitempty"`    // 地址类型判定依据
+	SurchargeVersion     string                  `json:"surcharge_version,omitempty"`      // 附加费数据集版本
+	CarrierErrors        []CarrierError          `json:"carrier_errors,omitempty"`         // 承运商报价失败记录
+	Excluded             []ExcludedService       `json:"excluded,omitempty"`               // 不满足服务约束而被剔除的服务
+	ConstraintVersion    string                  `json:"constraint_version,omitempty"`     // 服务约束目录版本
+	PromisedDeliveryDate string                  `json:"promised_delivery_date,omitempty"` // 订单承诺送达日（YYYY-MM-DD）
+	CalendarVersion      string                  `json:"calendar_version,omitempty"`       // 时效估算日历版本
+	Lane                 *Lane                   `json:"lane,omitempty"`                   // 运输线路（国内/国际、始发地与收件地距离）
+}
+
+// ExcludedService 被剔除的承运商服务及原因
+type ExcludedService struct {
+	Carrier string   `json:"carrier"`
+	Service string   `json:"service"`
+	Reasons []string `json:"reasons"`
+}
+
+// CarrierError 承运商报价失败记录
+type CarrierError struct {
+	Carrier  string `json:"carrier"`
+	Error    string `json:"error"`
+	Fallback bool   `json:"fallback"` // 是否已使用降级报价
 }
 
 // ShippingRate 单个物流费率
 type ShippingRate struct {
-	Carrier     string   `json:"carrier"`
-	Service     string   `json:"service"`
-	TotalFee    float64  `json:"total_fee"`
-	TransitDays int      `json:"transit_days"`
-	Tags        []string `json:"tags"` // CHEAPEST/FASTEST
+	Carrier           string            `json:"carrier"`
+	Service           string            `json:"service"`
+	BaseFee           float64           `json:"base_fee"`  // 基础运费（牌价，不含附加费）
+	ListFee           float64           `json:"list_fee"`  // 牌价总运费（基础运费 + 附加费）
+	TotalFee          float64           `json:"total_fee"` // 应付总运费（账号有协议价时为协议价，否则等于 ListFee）
+	Currency          string            `json:"currency"`  // 费率原始币种
+	TransitDays       int               `json:"transit_days"`
+	Tags              []string          `json:"tags"`                         // CHEAPEST/FASTEST/LOWEST_EMISSION
+	Surcharges        []Surcharge       `json:"surcharges,omitempty"`         // 附加费明细（已换算为费率币种）
+	Fallback          bool              `json:"fallback,omitempty"`           // 是否为承运商接口不可用时的降级报价
+	PreferredFee      *Money            `json:"preferred_fee,omitempty"`      // 换算为账号偏好币种后的费用
+	Score             *float64          `json:"score,omitempty"`              // 加权评分（仅 WEIGHTED 策略，0~1）
+	Contract          *ContractPrice    `json:"contract,omitempty"`           // 应用的协议价条款
+	EstimatedDelivery *DeliveryEstimate `json:"estimated_delivery,omitempty"` // 预计送达日期区间
+	PromiseStatus     string            `json:"promise_status,omitempty"`     // 相对承诺送达日：ON_TIME/AT_RISK/LATE
+	Zone              string            `json:"zone,omitempty"`               // 按距离分区计价时命中的分区
+	Emission          *RateEmission     `json:"emission,omitempty"`           // 运输碳排放估算（线路距离未知时为空）
+}
+
+// RateEmission 费率对应服务的碳排放估算
+type RateEmission struct {
+	Mode   string  `json:"mode"`    // 运输方式：AIR/GROUND
+	Co2eKg float64 `json:"co2e_kg"` // CO2e 排放（千克）
 }
+
+// ContractPrice 费率应用的协议价
+type ContractPrice struct {
+	Service              string   `json:"service,omitempty"`                // 合同适用服务（为空表示承运商全部服务）
+	DiscountPercent      float64  `json:"discount_percent,omitempty"`       // 基础运费折扣百分比
+	FixedFee             *Money   `json:"fixed_fee,omitempty"`              // 合同约定的固定基础运费（原币种）
+	FuelSurchargePercent *float64 `json:"fuel_surcharge_percent,omitempty"` // 燃油附加费百分比
+	BaseFee              float64  `json:"base_fee"`                         // 协议基础运费（费率币种）
+	Savings              float64  `json:"savings"`                          // 相对牌价节省的金额（ListFee - TotalFee）
+}
+
+// Surcharge 附加费明细
+type Surcharge struct {
+	Type        string  `json:"type"`        // REMOTE_AREA/EXTENDED_AREA/RESIDENTIAL/FUEL
+	Description string  `json:"description"` // 收费依据
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+}
+
+// 附加费类型常量
+const (
+	SurchargeTypeRemoteArea   = "REMOTE_AREA"
+	SurchargeTypeExtendedArea = "EXTENDED_AREA"
+	SurchargeTypeResidential  = "RESIDENTIAL"
+	SurchargeTypeFuel         = "FUEL" // 燃油附加费（仅按协议价收取）
+)
+
+// 收件地址类型常量
+const (
+	AddressTypeResidential = "RESIDENTIAL"
+	AddressTypeCommercial  = "COMMERCIAL"
+	AddressTypeUnknown     = "UNKNOWN"
+)
@@ -15,18 +15,26 @@ import (
 	"oip/dpmain/internal/app/config"
 	"oip/dpmain/internal/app/consumer"
 	"oip/dpmain/internal/app/domains/modules/mdaccount"
+	"oip/dpmain/internal/app/domains/modules/mdcontract"
 	"oip/dpmain/internal/app/domains/modules/mddiagnosis"
 	"oip/dpmain/internal/app/domains/modules/mdorder"
+	"oip/dpmain/internal/app/domains/modules/mdproduct"
 	"oip/dpmain/internal/app/domains/repo/rpaccount"
+	"oip/dpmain/internal/app/domains/repo/rpcontract"
 	"oip/dpmain/internal/app/domains/repo/rporder"
+	"oip/dpmain/internal/app/domains/repo/rpproduct"
 	"oip/dpmain/internal/app/domains/services/svaccount"
 	"oip/dpmain/internal/app/domains/services/svcallback"
+	"oip/dpmain/internal/app/domains/services/svcontract"
 	"oip/dpmain/internal/app/domains/services/svorder"
+	"oip/dpmain/internal/app/domains/services/svproduct"
 	"oip/dpmain/internal/app/infra/mq/lmstfy"
 	"oip/dpmain/internal/app/infra/persistence/redis"
 	"oip/dpmain/internal/app/pkg/logger"
 	"oip/dpmain/internal/app/server/handlers/account"
+	"oip/dpmain/internal/app/server/handlers/contract"
 	"oip/dpmain/internal/app/server/handlers/order"
+	"oip/dpmain/internal/app/server/handlers/product"
 	"oip/dpmain/internal/app/server/routers"
 )
 
@@ -58,6 +66,8 @@ var InfraSet = wire.NewSet(
 	ProvideLmstfyClient,
 	rporder.NewOrderRepository,
 	rpaccount.NewAccountRepository,
+	rpcontract.NewContractRepository,
+	rpproduct.NewProductRepository,
 )
 
 // ModuleSet 模块层依赖
@@ -65,6 +75,8 @@ var ModuleSet = wire.NewSet(
 	mdorder.NewOrderModule,
 	mdaccount.NewAccountModule,
 	mddiagnosis.NewDiagnosisModule,
+	mdcontract.NewContractModule,
+	mdproduct.NewProductModule,
 )
 
 // ServiceSet 服务层依赖
@@ -72,6 +84,8 @@ var ServiceSet = wire.NewSet(
 	svorder.NewOrderService,
 	svaccount.NewAccountService,
 	svcallback.NewCallbackService,
+	svcontract.NewContractService,
+	svproduct.NewProductService,
 	ProvideQueueName,
 )
 
@@ -85,6 +99,8 @@ var ConsumerSet = wire.NewSet(
 var HandlerSet = wire.NewSet(
 	order.NewOrderHandler,
 	account.NewAccountHandler,
+	contract.NewContractHandler,
+	product.NewProductHandler,
 )
 
 // ProvideDB 提供数据库连接
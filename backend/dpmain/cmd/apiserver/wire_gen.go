@@ -14,18 +14,26 @@ import (
 	"oip/dpmain/internal/app/config"
 	"oip/dpmain/internal/app/consumer"
 	"oip/dpmain/internal/app/domains/modules/mdaccount"
+	"oip/dpmain/internal/app/domains/modules/mdcontract"
 	"oip/dpmain/internal/app/domains/modules/mddiagnosis"
 	"oip/dpmain/internal/app/domains/modules/mdorder"
+	"oip/dpmain/internal/app/domains/modules/mdproduct"
 	"oip/dpmain/internal/app/domains/repo/rpaccount"
+	"oip/dpmain/internal/app/domains/repo/rpcontract"
 	"oip/dpmain/internal/app/domains/repo/rporder"
+	"oip/dpmain/internal/app/domains/repo/rpproduct"
 	"oip/dpmain/internal/app/domains/services/svaccount"
 	"oip/dpmain/internal/app/domains/services/svcallback"
+	"oip/dpmain/internal/app/domains/services/svcontract"
 	"oip/dpmain/internal/app/domains/services/svorder"
+	"oip/dpmain/internal/app/domains/services/svproduct"
 	"oip/dpmain/internal/app/infra/mq/lmstfy"
 	"oip/dpmain/internal/app/infra/persistence/redis"
 	"oip/dpmain/internal/app/pkg/logger"
 	"oip/dpmain/internal/app/server/handlers/account"
+	"oip/dpmain/internal/app/server/handlers/contract"
 	"oip/dpmain/internal/app/server/handlers/order"
+	"oip/dpmain/internal/app/server/handlers/product"
 	"oip/dpmain/internal/app/server/routers"
 	"time"
 )
@@ -40,7 +48,9 @@ func InitializeApp(cfg *config.Config) (*App, func(), error) {
 	}
 	orderRepository := rporder.NewOrderRepository(db)
 	accountRepository := rpaccount.NewAccountRepository(db)
-	orderModule := mdorder.NewOrderModule(orderRepository, accountRepository)
+	contractRepository := rpcontract.NewContractRepository(db)
+	productRepository := rpproduct.NewProductRepository(db)
+	orderModule := mdorder.NewOrderModule(orderRepository, accountRepository, contractRepository, productRepository)
 	client := ProvideLmstfyClient(cfg)
 	pubSubClient, cleanup2, err := ProvideRedisClient(cfg)
 	if err != nil {
@@ -54,7 +64,13 @@ func InitializeApp(cfg *config.Config) (*App, func(), error) {
 	accountModule := mdaccount.NewAccountModule(accountRepository)
 	accountService := svaccount.NewAccountService(accountModule)
 	accountHandler := account.NewAccountHandler(accountService)
-	engine := routers.SetupRoutes(orderHandler, accountHandler)
+	contractModule := mdcontract.NewContractModule(contractRepository, accountRepository)
+	contractService := svcontract.NewContractService(contractModule)
+	contractHandler := contract.NewContractHandler(contractService)
+	productModule := mdproduct.NewProductModule(productRepository, accountRepository)
+	productService := svproduct.NewProductService(productModule)
+	productHandler := product.NewProductHandler(productService)
+	engine := routers.SetupRoutes(orderHandler, accountHandler, contractHandler, productHandler)
 	logger := ProvideLogger()
 	callbackService := svcallback.NewCallbackService(orderRepository, pubSubClient, logger)
 	consumerConfig := ProvideConsumerConfig(cfg)
@@ -81,20 +97,20 @@ type App struct {
 var InfraSet = wire.NewSet(
 	ProvideDB,
 	ProvideRedisClient,
-	ProvideLmstfyClient, rporder.NewOrderRepository, rpaccount.NewAccountRepository,
+	ProvideLmstfyClient, rporder.NewOrderRepository, rpaccount.NewAccountRepository, rpcontract.NewContractRepository, rpproduct.NewProductRepository,
 )
 
 // ModuleSet 模块层依赖
-var ModuleSet = wire.NewSet(mdorder.NewOrderModule, mdaccount.NewAccountModule, mddiagnosis.NewDiagnosisModule)
+var ModuleSet = wire.NewSet(mdorder.NewOrderModule, mdaccount.NewAccountModule, mddiagnosis.NewDiagnosisModule, mdcontract.NewContractModule, mdproduct.NewProductModule)
 
 // ServiceSet 服务层依赖
-var ServiceSet = wire.NewSet(svorder.NewOrderService, svaccount.NewAccountService, svcallback.NewCallbackService, ProvideQueueName)
+var ServiceSet = wire.NewSet(svorder.NewOrderService, svaccount.NewAccountService, svcallback.NewCallbackService, svcontract.NewContractService, svproduct.NewProductService, ProvideQueueName)
 
 // ConsumerSet 消费者层依赖
 var ConsumerSet = wire.NewSet(consumer.NewCallbackConsumer, ProvideConsumerConfig)
 
 // HandlerSet 处理器层依赖
-var HandlerSet = wire.NewSet(order.NewOrderHandler, account.NewAccountHandler)
+var HandlerSet = wire.NewSet(order.NewOrderHandler, account.NewAccountHandler, contract.NewContractHandler, product.NewProductHandler)
 
 // ProvideDB 提供数据库连接
 func ProvideDB(cfg *config.Config) (*gorm.DB, func(), error) {
@@ -0,0 +1,32 @@
+package request
+
+import (
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+)
+
+// ContractRequest 创建/更新协议价请求
+type ContractRequest struct {
+	Carrier              string   `json:"carrier" binding:"required" example:"FedEx"`
+	Service              string   `json:"service" example:"Ground"`             // 为空表示对该承运商全部服务生效
+	DiscountPercent      float64  `json:"discount_percent" example:"15"`        // 基础运费折扣百分比（0~100）
+	FixedFee             *Money   `json:"fixed_fee"`                            // 固定基础运费（设置后忽略折扣）
+	FuelSurchargePercent *float64 `json:"fuel_surcharge_percent" example:"8.5"` // 燃油附加费百分比（为空表示不收取）
+}
+
+// ToTermsEntity 将 Request DTO 转换为领域对象
+func (r *ContractRequest) ToTermsEntity() *etcontract.Terms {
+	terms := &etcontract.Terms{
+		Carrier:              r.Carrier,
+		Service:              r.Service,
+		DiscountPercent:      r.DiscountPercent,
+		FuelSurchargePercent: r.FuelSurchargePercent,
+	}
+	if r.FixedFee != nil {
+		terms.FixedFee = &model.Money{
+			Amount:   r.FixedFee.Amount,
+			Currency: r.FixedFee.Currency,
+		}
+	}
+	return terms
+}
@@ -1,6 +1,9 @@
 package request
 
-import "oip/dpmain/internal/app/domains/entity/etorder"
+import (
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etorder"
+)
 
 // ToShipmentEntity 将 Request DTO 转换为领域对象
 func (r *CreateOrderRequest) ToShipmentEntity() *etorder.Shipment {
@@ -11,6 +14,36 @@ func (r *CreateOrderRequest) ToShipmentEntity() *etorder.Shipment {
 	}
 }
 
+// ToDiagnoseOptionsEntity 将请求中的诊断选项转换为领域对象
+func (r *CreateOrderRequest) ToDiagnoseOptionsEntity() *etorder.DiagnoseOptions {
+	r.Strategy.Normalize()
+	return &etorder.DiagnoseOptions{
+		Diagnosers:           r.Diagnosers,
+		Strategy:             r.Strategy,
+		PromisedDeliveryDate: r.PromisedDeliveryDate,
+		Incoterm:             model.NormalizeIncoterm(r.Incoterm),
+		Selected:             r.SelectedService.toModel(),
+	}
+}
+
+func (dto *SelectedService) toModel() *model.SelectedService {
+	if dto == nil {
+		return nil
+	}
+	selected := &model.SelectedService{
+		Carrier: dto.Carrier,
+		Service: dto.Service,
+	}
+	if dto.QuotedPrice != nil {
+		selected.QuotedPrice = &model.Money{
+			Amount:   dto.QuotedPrice.Amount,
+			Currency: dto.QuotedPrice.Currency,
+		}
+	}
+	selected.Normalize()
+	return selected
+}
+
 func toAddressEntity(dto *Address) *etorder.Address {
 	if dto == nil {
 		return nil
@@ -67,16 +100,30 @@ func toItemsEntity(dtos []*Item) []*etorder.Item {
 	items := make([]*etorder.Item, 0, len(dtos))
 	for _, dto := range dtos {
 		items = append(items, &etorder.Item{
-			Description: dto.Description,
-			Quantity:    dto.Quantity,
-			Price:       toMoneyEntity(dto.Price),
-			SKU:         dto.SKU,
-			Weight:      toWeightEntity(dto.Weight),
+			Description:   dto.Description,
+			Quantity:      dto.Quantity,
+			Price:         toMoneyEntity(dto.Price),
+			SKU:           dto.SKU,
+			Weight:        toWeightEntity(dto.Weight),
+			Dimension:     toDimensionEntity(dto.Dimension),
+			HSCode:        dto.HSCode,
+			OriginCountry: dto.OriginCountry,
+			Battery:       toBatteryEntity(dto.Battery),
 		})
 	}
 	return items
 }
 
+func toBatteryEntity(dto *Battery) *etorder.Battery {
+	if dto == nil {
+		return nil
+	}
+	return &etorder.Battery{
+		Chemistry: dto.Chemistry,
+		Packing:   dto.Packing,
+	}
+}
+
 func toMoneyEntity(dto *Money) *etorder.Money {
 	if dto == nil {
 		return nil
@@ -1,10 +1,24 @@
 package request
 
+import "oip/common/model"
+
 // CreateOrderRequest 创建订单请求
 type CreateOrderRequest struct {
-	AccountID       int64     `json:"account_id" binding:"required" example:"1"`
-	MerchantOrderNo string    `json:"merchant_order_no" binding:"required" example:"ORD-20240101-001"`
-	Shipment        *Shipment `json:"shipment" binding:"required"`
+	AccountID            int64                         `json:"account_id" binding:"required" example:"1"`
+	MerchantOrderNo      string                        `json:"merchant_order_no" binding:"required" example:"ORD-20240101-001"`
+	Shipment             *Shipment                     `json:"shipment" binding:"required"`
+	Diagnosers           []string                      `json:"diagnosers" example:"shipping,compliance"`    // 本单执行的诊断类型（可选，缺省使用账号设置）
+	Strategy             *model.RecommendationStrategy `json:"strategy"`                                    // 本单费率推荐策略（可选，缺省使用账号设置）
+	PromisedDeliveryDate string                        `json:"promised_delivery_date" example:"2026-01-15"` // 对买家承诺的送达日（可选，YYYY-MM-DD）
+	Incoterm             string                        `json:"incoterm" example:"DDP" enums:"DDP,DDU"`      // 贸易术语（可选，缺省按 DDU 估算到岸成本）
+	SelectedService      *SelectedService              `json:"selected_service"`                            // 商家选用的承运商服务与报价（可选，用于核对是否多付运费）
+}
+
+// SelectedService 商家选用的承运商服务
+type SelectedService struct {
+	Carrier     string `json:"carrier" binding:"required" example:"UPS"`
+	Service     string `json:"service" binding:"required" example:"Ground"`
+	QuotedPrice *Money `json:"quoted_price"` // 商家拿到的报价（可选，缺省按计算出的该服务运费核对）
 }
 
 // Shipment 货件信息
@@ -51,11 +65,21 @@ type Dimension struct {
 
 // Item 商品信息
 type Item struct {
-	Description string  `json:"description" binding:"required" example:"T-Shirt"`
-	Quantity    int     `json:"quantity" binding:"required" example:"2"`
-	Price       *Money  `json:"price" binding:"required"`
-	SKU         string  `json:"sku" example:"TSH-001"`
-	Weight      *Weight `json:"weight"`
+	Description   string     `json:"description" binding:"required" example:"T-Shirt"`
+	Quantity      int        `json:"quantity" binding:"required" example:"2"`
+	Price         *Money     `json:"price" binding:"required"`
+	SKU           string     `json:"sku" example:"TSH-001"`       // 缺失的重量、尺寸、HS 编码等字段按 SKU 从账号商品目录补全
+	Weight        *Weight    `json:"weight"`                      // 单件重量（可选）
+	Dimension     *Dimension `json:"dimension"`                   // 单件尺寸（可选）
+	HSCode        string     `json:"hs_code" example:"6109.10"`   // 海关编码（HS Code，可选）
+	OriginCountry string     `json:"origin_country" example:"CN"` // 原产国（ISO 两位代码，可选）
+	Battery       *Battery   `json:"battery"`                     // 所含电池（可选）
+}
+
+// Battery 电池信息
+type Battery struct {
+	Chemistry string `json:"chemistry" binding:"required" example:"LITHIUM_ION" enums:"LITHIUM_ION,LITHIUM_METAL,OTHER"`
+	Packing   string `json:"packing" example:"CONTAINED_IN_EQUIPMENT" enums:"STANDALONE,PACKED_WITH_EQUIPMENT,CONTAINED_IN_EQUIPMENT"` // 为空按单独运输处理
 }
 
 // Money 金额信息
@@ -0,0 +1,62 @@
+package request
+
+import (
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+)
+
+// ProductRequest 创建/更新商品请求（整体替换商品属性）
+type ProductRequest struct {
+	SKU           string     `json:"sku" binding:"required" example:"TSH-001"`
+	Description   string     `json:"description" example:"Cotton T-Shirt"` // 品名（订单商品未填品名时补全）
+	Weight        *Weight    `json:"weight"`                               // 单件重量
+	Dimension     *Dimension `json:"dimension"`                            // 单件尺寸
+	HSCode        string     `json:"hs_code" example:"6109.10"`
+	OriginCountry string     `json:"origin_country" example:"CN"`
+	Battery       *Battery   `json:"battery"` // 所含电池（为空表示不含电池）
+}
+
+// ImportProductsRequest 批量导入商品请求（按 SKU 新增或整体替换）
+type ImportProductsRequest struct {
+	Products []*ProductRequest `json:"products" binding:"required,dive"`
+}
+
+// ToAttributesEntity 将 Request DTO 转换为领域对象
+func (r *ProductRequest) ToAttributesEntity() *etproduct.Attributes {
+	attrs := &etproduct.Attributes{
+		SKU:           r.SKU,
+		Description:   r.Description,
+		HSCode:        r.HSCode,
+		OriginCountry: r.OriginCountry,
+	}
+	if r.Weight != nil {
+		attrs.Weight = &model.Weight{
+			Value: r.Weight.Value,
+			Unit:  r.Weight.Unit,
+		}
+	}
+	if r.Dimension != nil {
+		attrs.Dimension = &model.Dimension{
+			Width:  r.Dimension.Width,
+			Height: r.Dimension.Height,
+			Depth:  r.Dimension.Depth,
+			Unit:   r.Dimension.Unit,
+		}
+	}
+	if r.Battery != nil {
+		attrs.Battery = &model.Battery{
+			Chemistry: r.Battery.Chemistry,
+			Packing:   r.Battery.Packing,
+		}
+	}
+	return attrs
+}
+
+// ToAttributesEntities 批量转换
+func (r *ImportProductsRequest) ToAttributesEntities() []*etproduct.Attributes {
+	attrsList := make([]*etproduct.Attributes, 0, len(r.Products))
+	for _, product := range r.Products {
+		attrsList = append(attrsList, product.ToAttributesEntity())
+	}
+	return attrsList
+}
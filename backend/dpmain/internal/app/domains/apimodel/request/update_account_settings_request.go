@@ -0,0 +1,25 @@
+package request
+
+import (
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etaccount"
+)
+
+// UpdateAccountSettingsRequest 更新账号设置请求
+type UpdateAccountSettingsRequest struct {
+	Diagnosers        []string                      `json:"diagnosers" example:"shipping,compliance"` // 默认执行的诊断类型（为空表示执行全部）
+	PreferredCurrency string                        `json:"preferred_currency" example:"EUR"`         // 偏好币种（诊断金额额外换算为该币种，为空表示不换算）
+	Strategy          *model.RecommendationStrategy `json:"strategy"`                                 // 费率推荐策略（为空表示推荐最便宜）
+	Boxes             []model.Box                   `json:"boxes"`                                    // 箱型目录（内径尺寸与承重，用于包装建议）
+}
+
+// ToSettingsEntity 将 Request DTO 转换为领域对象
+func (r *UpdateAccountSettingsRequest) ToSettingsEntity() *etaccount.Settings {
+	r.Strategy.Normalize()
+	return &etaccount.Settings{
+		Diagnosers:        r.Diagnosers,
+		PreferredCurrency: model.NormalizeCurrency(r.PreferredCurrency),
+		Strategy:          r.Strategy,
+		Boxes:             r.Boxes,
+	}
+}
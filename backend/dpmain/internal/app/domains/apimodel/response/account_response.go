@@ -1,11 +1,24 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"oip/common/model"
+)
 
 // AccountResponse 账号响应
 type AccountResponse struct {
-	ID        int64     `json:"id" example:"1"`
-	Name      string    `json:"name" example:"John Doe"`
-	Email     string    `json:"email" example:"john@example.com"`
-	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	ID        int64            `json:"id" example:"1"`
+	Name      string           `json:"name" example:"John Doe"`
+	Email     string           `json:"email" example:"john@example.com"`
+	Settings  *AccountSettings `json:"settings,omitempty"`
+	CreatedAt time.Time        `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// AccountSettings 账号设置
+type AccountSettings struct {
+	Diagnosers        []string                      `json:"diagnosers" example:"shipping,compliance"`
+	PreferredCurrency string                        `json:"preferred_currency,omitempty" example:"EUR"`
+	Strategy          *model.RecommendationStrategy `json:"strategy,omitempty"`
+	Boxes             []model.Box                   `json:"boxes,omitempty"`
 }
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"time"
+
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+)
+
+// ContractResponse 协议价响应
+type ContractResponse struct {
+	ID                   int64        `json:"id" example:"25610"`
+	AccountID            int64        `json:"account_id" example:"1"`
+	Carrier              string       `json:"carrier" example:"FedEx"`
+	Service              string       `json:"service,omitempty" example:"Ground"`
+	DiscountPercent      float64      `json:"discount_percent" example:"15"`
+	FixedFee             *model.Money `json:"fixed_fee,omitempty"`
+	FuelSurchargePercent *float64     `json:"fuel_surcharge_percent,omitempty" example:"8.5"`
+	CreatedAt            time.Time    `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt            time.Time    `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// FromContractEntity 从领域对象转换为响应 DTO
+func FromContractEntity(contract *etcontract.Contract) *ContractResponse {
+	return &ContractResponse{
+		ID:                   contract.ID,
+		AccountID:            contract.AccountID,
+		Carrier:              contract.Carrier,
+		Service:              contract.Service,
+		DiscountPercent:      contract.DiscountPercent,
+		FixedFee:             contract.FixedFee,
+		FuelSurchargePercent: contract.FuelSurchargePercent,
+		CreatedAt:            contract.CreatedAt,
+		UpdatedAt:            contract.UpdatedAt,
+	}
+}
+
+// FromContractEntities 批量转换
+func FromContractEntities(contracts []*etcontract.Contract) []*ContractResponse {
+	resp := make([]*ContractResponse, 0, len(contracts))
+	for _, contract := range contracts {
+		resp = append(resp, FromContractEntity(contract))
+	}
+	return resp
+}
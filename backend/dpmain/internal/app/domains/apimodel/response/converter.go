@@ -35,18 +35,36 @@ func fromDiagnosisEntity(entity *etorder.DiagnoseResult) *DiagnosisResult {
 			Status:   item.Status,
 			DataJSON: item.DataJSON,
 			Error:    item.Error,
+			Version:  item.Version,
 		})
 	}
 
-	return &DiagnosisResult{Items: items}
+	return &DiagnosisResult{
+		EngineVersion: entity.EngineVersion,
+		Items:         items,
+		Partial:       entity.Partial,
+		Traces:        entity.Traces,
+		Enrichments:   entity.Enrichments,
+	}
 }
 
 // FromAccountEntity 从领域对象转换为响应 DTO
 func FromAccountEntity(account *etaccount.Account) *AccountResponse {
-	return &AccountResponse{
+	resp := &AccountResponse{
 		ID:        account.ID,
 		Name:      account.Name,
 		Email:     account.Email,
 		CreatedAt: account.CreatedAt,
 	}
+
+	if account.Settings != nil {
+		resp.Settings = &AccountSettings{
+			Diagnosers:        account.Settings.Diagnosers,
+			PreferredCurrency: account.Settings.PreferredCurrency,
+			Strategy:          account.Settings.Strategy,
+			Boxes:             account.Settings.Boxes,
+		}
+	}
+
+	return resp
 }
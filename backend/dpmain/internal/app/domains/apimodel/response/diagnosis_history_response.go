@@ -0,0 +1,161 @@
+package response
+
+import (
+	"time"
+
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etorder"
+)
+
+// DiagnosisHistoryResponse 订单诊断版本历史响应
+type DiagnosisHistoryResponse struct {
+	OrderID  string                      `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Versions []*DiagnosisVersionResponse `json:"versions"` // 按版本号升序
+}
+
+// DiagnosisVersionResponse 单次诊断记录
+type DiagnosisVersionResponse struct {
+	Version           int               `json:"version" example:"2"`
+	RequestID         string            `json:"request_id" example:"9b2f6c1e-3d4a-4f5b-8c7d-1e2f3a4b5c6d"`
+	Status            string            `json:"status" example:"DIAGNOSED" enums:"DIAGNOSED,FAILED"`
+	EngineVersion     string            `json:"engine_version,omitempty" example:"engine-2026-10"`
+	DiagnoserVersions map[string]string `json:"diagnoser_versions"` // 诊断类型 → 规则/费率卡版本
+	Diagnosis         *DiagnosisResult  `json:"diagnosis,omitempty"`
+	Error             string            `json:"error,omitempty" example:""`
+	CreatedAt         time.Time         `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// DiagnosisDiffResponse 两个诊断版本的差异
+type DiagnosisDiffResponse struct {
+	OrderID          string                 `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	From             int                    `json:"from" example:"1"`
+	To               int                    `json:"to" example:"2"`
+	EngineVersion    *ValueChangeResponse   `json:"engine_version,omitempty"`   // 引擎版本变化（未变化时省略）
+	VersionChanges   []*ItemChangeResponse  `json:"version_changes"`            // 诊断项规则/费率卡版本变化
+	StatusChanges    []*ItemChangeResponse  `json:"status_changes"`             // 诊断项状态变化
+	AnomaliesAdded   []model.AnomalyItem    `json:"anomalies_added"`            // 新出现的异常
+	AnomaliesRemoved []model.AnomalyItem    `json:"anomalies_removed"`          // 消失的异常
+	RecommendedCode  *ValueChangeResponse   `json:"recommended_code,omitempty"` // 推荐服务变化（未变化时省略）
+	RatesAdded       []*RateSummaryResponse `json:"rates_added"`
+	RatesRemoved     []*RateSummaryResponse `json:"rates_removed"`
+	RatesChanged     []*RateChangeResponse  `json:"rates_changed"` // 应付总运费或时效变化的服务
+}
+
+// ValueChangeResponse 取值变化
+type ValueChangeResponse struct {
+	From string `json:"from" example:"USPS_Priority"`
+	To   string `json:"to" example:"UPS_Ground"`
+}
+
+// ItemChangeResponse 按诊断类型的取值变化（仅一侧存在时另一侧为空串）
+type ItemChangeResponse struct {
+	Type string `json:"type" example:"shipping"`
+	From string `json:"from" example:"rates:static-2026-01"`
+	To   string `json:"to" example:"rates:static-2026-07"`
+}
+
+// RateSummaryResponse 费率摘要
+type RateSummaryResponse struct {
+	Code        string  `json:"code" example:"UPS_Ground"`
+	Carrier     string  `json:"carrier" example:"UPS"`
+	Service     string  `json:"service" example:"Ground"`
+	TotalFee    float64 `json:"total_fee" example:"12.5"`
+	Currency    string  `json:"currency" example:"USD"`
+	TransitDays int     `json:"transit_days" example:"5"`
+}
+
+// RateChangeResponse 费率变化
+type RateChangeResponse struct {
+	Code            string  `json:"code" example:"UPS_Ground"`
+	Carrier         string  `json:"carrier" example:"UPS"`
+	Service         string  `json:"service" example:"Ground"`
+	Currency        string  `json:"currency" example:"USD"`
+	FromTotalFee    float64 `json:"from_total_fee" example:"12.5"`
+	ToTotalFee      float64 `json:"to_total_fee" example:"13.1"`
+	TotalFeeDelta   float64 `json:"total_fee_delta" example:"0.6"`
+	FromTransitDays int     `json:"from_transit_days" example:"5"`
+	ToTransitDays   int     `json:"to_transit_days" example:"5"`
+}
+
+// FromDiagnosisVersions 从领域对象转换为诊断历史响应 DTO
+func FromDiagnosisVersions(orderID string, versions []*etorder.DiagnosisVersion) *DiagnosisHistoryResponse {
+	resp := &DiagnosisHistoryResponse{
+		OrderID:  orderID,
+		Versions: make([]*DiagnosisVersionResponse, 0, len(versions)),
+	}
+	for _, version := range versions {
+		resp.Versions = append(resp.Versions, &DiagnosisVersionResponse{
+			Version:           version.Version,
+			RequestID:         version.RequestID,
+			Status:            string(version.Status),
+			EngineVersion:     version.EngineVersion,
+			DiagnoserVersions: version.DiagnoserVersions,
+			Diagnosis:         fromDiagnosisEntity(version.Result),
+			Error:             version.Error,
+			CreatedAt:         version.CreatedAt,
+		})
+	}
+	return resp
+}
+
+// FromDiagnosisDiff 从领域对象转换为诊断差异响应 DTO
+func FromDiagnosisDiff(diff *etorder.DiagnosisDiff) *DiagnosisDiffResponse {
+	resp := &DiagnosisDiffResponse{
+		OrderID:          diff.OrderID,
+		From:             diff.From,
+		To:               diff.To,
+		EngineVersion:    fromValueChange(diff.EngineVersion),
+		VersionChanges:   fromItemChanges(diff.VersionChanges),
+		StatusChanges:    fromItemChanges(diff.StatusChanges),
+		AnomaliesAdded:   diff.AnomaliesAdded,
+		AnomaliesRemoved: diff.AnomaliesRemoved,
+		RecommendedCode:  fromValueChange(diff.RecommendedCode),
+		RatesAdded:       fromRateSummaries(diff.RatesAdded),
+		RatesRemoved:     fromRateSummaries(diff.RatesRemoved),
+		RatesChanged:     make([]*RateChangeResponse, 0, len(diff.RatesChanged)),
+	}
+	for _, change := range diff.RatesChanged {
+		resp.RatesChanged = append(resp.RatesChanged, &RateChangeResponse{
+			Code:            change.Code,
+			Carrier:         change.Carrier,
+			Service:         change.Service,
+			Currency:        change.Currency,
+			FromTotalFee:    change.FromTotalFee,
+			ToTotalFee:      change.ToTotalFee,
+			TotalFeeDelta:   change.TotalFeeDelta,
+			FromTransitDays: change.FromTransitDays,
+			ToTransitDays:   change.ToTransitDays,
+		})
+	}
+	return resp
+}
+
+func fromValueChange(change *etorder.ValueChange) *ValueChangeResponse {
+	if change == nil {
+		return nil
+	}
+	return &ValueChangeResponse{From: change.From, To: change.To}
+}
+
+func fromItemChanges(changes []*etorder.ItemChange) []*ItemChangeResponse {
+	resp := make([]*ItemChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		resp = append(resp, &ItemChangeResponse{Type: change.Type, From: change.From, To: change.To})
+	}
+	return resp
+}
+
+func fromRateSummaries(rates []*etorder.RateSummary) []*RateSummaryResponse {
+	resp := make([]*RateSummaryResponse, 0, len(rates))
+	for _, rate := range rates {
+		resp = append(resp, &RateSummaryResponse{
+			Code:        rate.Code,
+			Carrier:     rate.Carrier,
+			Service:     rate.Service,
+			TotalFee:    rate.TotalFee,
+			Currency:    rate.Currency,
+			TransitDays: rate.TransitDays,
+		})
+	}
+	return resp
+}
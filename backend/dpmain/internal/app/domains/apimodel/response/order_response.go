@@ -1,6 +1,10 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"oip/common/model"
+)
 
 // OrderResponse 订单响应
 type OrderResponse struct {
@@ -15,13 +19,18 @@ type OrderResponse struct {
 
 // DiagnosisResult 诊断结果
 type DiagnosisResult struct {
-	Items []*DiagnosisItem `json:"items"`
+	EngineVersion string                 `json:"engine_version,omitempty" example:"engine-2026-10"` // 诊断引擎版本
+	Items         []*DiagnosisItem       `json:"items"`
+	Partial       bool                   `json:"partial" example:"false"` // 是否部分成功（任一诊断项失败或超时）
+	Traces        []model.DiagnoserTrace `json:"traces,omitempty"`        // 各诊断项的执行轨迹（仅 explain 模式诊断后返回，与 items 一一对应）
+	Enrichments   []model.ItemEnrichment `json:"enrichments,omitempty"`   // 下单时由商品目录补全的商品字段（诊断基于补全后的货件）
 }
 
 // DiagnosisItem 诊断项
 type DiagnosisItem struct {
-	Type     string      `json:"type" example:"shipping" enums:"shipping,anomaly"`
+	Type     string      `json:"type" example:"shipping" enums:"shipping,anomaly,compliance,landed_cost,packaging,screening,risk,carbon,insurance,service_audit"`
 	Status   string      `json:"status" example:"SUCCESS" enums:"SUCCESS,FAILED"`
 	DataJSON interface{} `json:"data_json"`
 	Error    string      `json:"error,omitempty" example:""`
+	Version  string      `json:"version,omitempty" example:"rules:anomaly-2025-12"` // 产生该结果的规则/费率卡版本
 }
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"time"
+
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+)
+
+// ProductResponse 商品响应
+type ProductResponse struct {
+	ID            int64            `json:"id" example:"25610"`
+	AccountID     int64            `json:"account_id" example:"1"`
+	SKU           string           `json:"sku" example:"TSH-001"`
+	Description   string           `json:"description,omitempty" example:"Cotton T-Shirt"`
+	Weight        *model.Weight    `json:"weight,omitempty"`
+	Dimension     *model.Dimension `json:"dimension,omitempty"`
+	HSCode        string           `json:"hs_code,omitempty" example:"6109.10"`
+	OriginCountry string           `json:"origin_country,omitempty" example:"CN"`
+	Battery       *model.Battery   `json:"battery,omitempty"`
+	CreatedAt     time.Time        `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt     time.Time        `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// ProductListResponse 商品分页列表响应
+type ProductListResponse struct {
+	Products []*ProductResponse `json:"products"`
+	Total    int64              `json:"total" example:"120"`
+	Page     int                `json:"page" example:"1"`
+	Limit    int                `json:"limit" example:"50"`
+}
+
+// ImportProductsResponse 批量导入商品响应
+type ImportProductsResponse struct {
+	Created []*ProductResponse `json:"created"` // 新增的商品
+	Updated []*ProductResponse `json:"updated"` // 按 SKU 替换的已有商品
+}
+
+// FromProductEntity 从领域对象转换为响应 DTO
+func FromProductEntity(product *etproduct.Product) *ProductResponse {
+	return &ProductResponse{
+		ID:            product.ID,
+		AccountID:     product.AccountID,
+		SKU:           product.SKU,
+		Description:   product.Description,
+		Weight:        product.Weight,
+		Dimension:     product.Dimension,
+		HSCode:        product.HSCode,
+		OriginCountry: product.OriginCountry,
+		Battery:       product.Battery,
+		CreatedAt:     product.CreatedAt,
+		UpdatedAt:     product.UpdatedAt,
+	}
+}
+
+// FromProductEntities 批量转换
+func FromProductEntities(products []*etproduct.Product) []*ProductResponse {
+	resp := make([]*ProductResponse, 0, len(products))
+	for _, product := range products {
+		resp = append(resp, FromProductEntity(product))
+	}
+	return resp
+}
@@ -0,0 +1,72 @@
+package response
+
+import (
+	"time"
+
+	"oip/dpmain/internal/app/domains/entity/etorder"
+)
+
+// SavingsReportResponse 账号运费节省报告响应
+type SavingsReportResponse struct {
+	AccountID        int64                         `json:"account_id" example:"1"`
+	From             time.Time                     `json:"from" example:"2026-01-01T00:00:00Z"`
+	To               time.Time                     `json:"to" example:"2026-02-01T00:00:00Z"`
+	Currency         string                        `json:"currency,omitempty" example:"USD"`
+	AuditedOrders    int                           `json:"audited_orders" example:"120"`      // 完成选用服务核对的订单数
+	OverpayingOrders int                           `json:"overpaying_orders" example:"37"`    // 存在更便宜可用服务的订单数
+	SelectedSpend    float64                       `json:"selected_spend" example:"3120.50"`  // 选用服务运费合计
+	PotentialSavings float64                       `json:"potential_savings" example:"412.8"` // 改用最便宜服务可节省的运费合计
+	Services         []*ServiceSavingsResponse     `json:"services"`                          // 按选用服务汇总（按节省金额降序）
+	Alternatives     []*AlternativeSavingsResponse `json:"alternatives"`                      // 按建议改用的服务汇总（按节省金额降序）
+}
+
+// ServiceSavingsResponse 按选用服务汇总
+type ServiceSavingsResponse struct {
+	Carrier          string  `json:"carrier" example:"UPS"`
+	Service          string  `json:"service" example:"Ground"`
+	Orders           int     `json:"orders" example:"80"`
+	OverpayingOrders int     `json:"overpaying_orders" example:"30"`
+	SelectedSpend    float64 `json:"selected_spend" example:"2188"`
+	PotentialSavings float64 `json:"potential_savings" example:"365.4"`
+}
+
+// AlternativeSavingsResponse 按建议改用的服务汇总
+type AlternativeSavingsResponse struct {
+	Code             string  `json:"code" example:"USPS_Priority"`
+	Orders           int     `json:"orders" example:"30"`
+	PotentialSavings float64 `json:"potential_savings" example:"365.4"`
+}
+
+// FromSavingsReportEntity 从领域对象转换为响应 DTO
+func FromSavingsReportEntity(report *etorder.SavingsReport) *SavingsReportResponse {
+	resp := &SavingsReportResponse{
+		AccountID:        report.AccountID,
+		From:             report.From,
+		To:               report.To,
+		Currency:         report.Currency,
+		AuditedOrders:    report.AuditedOrders,
+		OverpayingOrders: report.OverpayingOrders,
+		SelectedSpend:    report.SelectedSpend,
+		PotentialSavings: report.PotentialSavings,
+		Services:         make([]*ServiceSavingsResponse, 0, len(report.Services)),
+		Alternatives:     make([]*AlternativeSavingsResponse, 0, len(report.Alternatives)),
+	}
+	for _, service := range report.Services {
+		resp.Services = append(resp.Services, &ServiceSavingsResponse{
+			Carrier:          service.Carrier,
+			Service:          service.Service,
+			Orders:           service.Orders,
+			OverpayingOrders: service.OverpayingOrders,
+			SelectedSpend:    service.SelectedSpend,
+			PotentialSavings: service.PotentialSavings,
+		})
+	}
+	for _, alternative := range report.Alternatives {
+		resp.Alternatives = append(resp.Alternatives, &AlternativeSavingsResponse{
+			Code:             alternative.Code,
+			Orders:           alternative.Orders,
+			PotentialSavings: alternative.PotentialSavings,
+		})
+	}
+	return resp
+}
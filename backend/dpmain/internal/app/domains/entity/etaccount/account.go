@@ -3,6 +3,8 @@ package etaccount
 import (
 	"errors"
 	"time"
+
+	"oip/common/model"
 )
 
 // 错误定义
@@ -10,6 +12,7 @@ var (
 	ErrInvalidAccountID = errors.New("invalid account ID")
 	ErrInvalidName      = errors.New("account name cannot be empty")
 	ErrInvalidEmail     = errors.New("invalid email format")
+	ErrAccountNotFound  = errors.New("account not found")
 )
 
 // Account 账号实体
@@ -17,9 +20,18 @@ type Account struct {
 	ID        int64     // 账号ID
 	Name      string    // 账号名称
 	Email     string    // 邮箱
+	Settings  *Settings // 账号级设置
 	CreatedAt time.Time // 创建时间
 }
 
+// Settings 账号级设置（值对象）
+type Settings struct {
+	Diagnosers        []string                      // 默认执行的诊断类型（为空表示执行全部）
+	PreferredCurrency string                        // 偏好币种（诊断金额额外换算为该币种）
+	Strategy          *model.RecommendationStrategy // 费率推荐策略（为空时推荐最便宜）
+	Boxes             []model.Box                   // 箱型目录（包装诊断据此给出换箱与合箱建议）
+}
+
 // NewAccount 创建账号（工厂方法）
 // id: 账号ID，如果为0表示新创建的账号（ID将由数据库自动生成）
 func NewAccount(id int64, name, email string) (*Account, error) {
@@ -38,6 +50,15 @@ func NewAccount(id int64, name, email string) (*Account, error) {
 		ID:        id,
 		Name:      name,
 		Email:     email,
+		Settings:  &Settings{},
 		CreatedAt: time.Now(),
 	}, nil
 }
+
+// UpdateSettings 更新账号设置（领域行为）
+func (a *Account) UpdateSettings(settings *Settings) {
+	if settings == nil {
+		settings = &Settings{}
+	}
+	a.Settings = settings
+}
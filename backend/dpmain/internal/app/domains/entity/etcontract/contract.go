@@ -0,0 +1,117 @@
+package etcontract
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// 错误定义
+var (
+	ErrInvalidContractID = errors.New("invalid contract ID")
+	ErrInvalidAccountID  = errors.New("invalid account ID")
+	ErrContractNotFound  = errors.New("contract not found")
+)
+
+// Contract 账号协议价实体
+// 每个账号在同一承运商服务上最多一份合同；Service 为空表示对该承运商全部服务生效
+type Contract struct {
+	ID                   int64
+	AccountID            int64
+	Carrier              string
+	Service              string
+	DiscountPercent      float64      // 基础运费折扣百分比
+	FixedFee             *model.Money // 固定基础运费（设置后忽略折扣）
+	FuelSurchargePercent *float64     // 燃油附加费百分比（为空表示不收取）
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// Terms 合同条款（值对象，创建与更新合同时使用）
+type Terms struct {
+	Carrier              string
+	Service              string
+	DiscountPercent      float64
+	FixedFee             *model.Money
+	FuelSurchargePercent *float64
+}
+
+// NewContract 创建合同（工厂方法）
+func NewContract(id, accountID int64, terms *Terms) (*Contract, error) {
+	if id <= 0 {
+		return nil, ErrInvalidContractID
+	}
+	if accountID <= 0 {
+		return nil, ErrInvalidAccountID
+	}
+
+	now := time.Now()
+	contract := &Contract{
+		ID:        id,
+		AccountID: accountID,
+		CreatedAt: now,
+	}
+	if err := contract.UpdateTerms(terms); err != nil {
+		return nil, err
+	}
+	contract.UpdatedAt = now
+
+	return contract, nil
+}
+
+// UpdateTerms 更新合同条款（领域行为）
+func (c *Contract) UpdateTerms(terms *Terms) error {
+	if terms == nil {
+		return errors.New("contract terms are required")
+	}
+
+	candidate := terms.toModel()
+	if err := candidate.Validate(); err != nil {
+		return err
+	}
+
+	c.Carrier = candidate.Carrier
+	c.Service = candidate.Service
+	c.DiscountPercent = candidate.DiscountPercent
+	c.FixedFee = candidate.FixedFee
+	c.FuelSurchargePercent = candidate.FuelSurchargePercent
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ToModel 转换为诊断任务中的合同条款
+func (c *Contract) ToModel() model.RateContract {
+	return model.RateContract{
+		Carrier:              c.Carrier,
+		Service:              c.Service,
+		DiscountPercent:      c.DiscountPercent,
+		FixedFee:             c.FixedFee,
+		FuelSurchargePercent: c.FuelSurchargePercent,
+	}
+}
+
+// Validate 校验合同条款
+func (t *Terms) Validate() error {
+	candidate := t.toModel()
+	return candidate.Validate()
+}
+
+// toModel 归一化条款并转换为 model.RateContract（承运商/服务去空格，币种转大写）
+func (t *Terms) toModel() model.RateContract {
+	contract := model.RateContract{
+		Carrier:              strings.TrimSpace(t.Carrier),
+		Service:              strings.TrimSpace(t.Service),
+		DiscountPercent:      t.DiscountPercent,
+		FuelSurchargePercent: t.FuelSurchargePercent,
+	}
+	if t.FixedFee != nil {
+		contract.FixedFee = &model.Money{
+			Amount:   t.FixedFee.Amount,
+			Currency: model.NormalizeCurrency(t.FixedFee.Currency),
+		}
+	}
+	return contract
+}
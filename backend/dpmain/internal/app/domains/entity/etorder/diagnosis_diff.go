@@ -0,0 +1,212 @@
+package etorder
+
+import (
+	"math"
+	"sort"
+
+	"oip/common/model"
+)
+
+// DiagnosisDiff 两个诊断版本之间的差异（值对象）
+// 只比较商家关心的结论：异常项增减、费率增减与变化、推荐服务，以及产生结果的引擎与规则/费率卡版本
+type DiagnosisDiff struct {
+	OrderID          string
+	From             int           // 基准版本
+	To               int           // 对比版本
+	EngineVersion    *ValueChange  // 引擎版本变化（未变化时为空）
+	VersionChanges   []*ItemChange // 诊断项规则/费率卡版本变化
+	StatusChanges    []*ItemChange // 诊断项状态变化（SUCCESS/FAILED，仅一侧存在时另一侧为空）
+	AnomaliesAdded   []model.AnomalyItem
+	AnomaliesRemoved []model.AnomalyItem
+	RecommendedCode  *ValueChange // 推荐服务变化（未变化时为空）
+	RatesAdded       []*RateSummary
+	RatesRemoved     []*RateSummary
+	RatesChanged     []*RateChange
+}
+
+// ValueChange 单个取值的变化
+type ValueChange struct {
+	From string
+	To   string
+}
+
+// ItemChange 按诊断类型记录的取值变化
+type ItemChange struct {
+	Type string
+	From string
+	To   string
+}
+
+// RateSummary 费率摘要（按 Carrier_Service 标识）
+type RateSummary struct {
+	Code        string
+	Carrier     string
+	Service     string
+	TotalFee    float64
+	Currency    string
+	TransitDays int
+}
+
+// RateChange 同一服务在两个版本间的运费或时效变化
+type RateChange struct {
+	Code            string
+	Carrier         string
+	Service         string
+	Currency        string
+	FromTotalFee    float64
+	ToTotalFee      float64
+	TotalFeeDelta   float64 // ToTotalFee - FromTotalFee
+	FromTransitDays int
+	ToTransitDays   int
+}
+
+// DiffDiagnoses 比较两个诊断版本（失败的诊断版本按无诊断项处理）
+func DiffDiagnoses(from, to *DiagnosisVersion) *DiagnosisDiff {
+	diff := &DiagnosisDiff{
+		OrderID:          to.OrderID,
+		From:             from.Version,
+		To:               to.Version,
+		VersionChanges:   make([]*ItemChange, 0),
+		StatusChanges:    make([]*ItemChange, 0),
+		AnomaliesAdded:   make([]model.AnomalyItem, 0),
+		AnomaliesRemoved: make([]model.AnomalyItem, 0),
+		RatesAdded:       make([]*RateSummary, 0),
+		RatesRemoved:     make([]*RateSummary, 0),
+		RatesChanged:     make([]*RateChange, 0),
+	}
+
+	if from.EngineVersion != to.EngineVersion {
+		diff.EngineVersion = &ValueChange{From: from.EngineVersion, To: to.EngineVersion}
+	}
+	diff.VersionChanges = diffValues(from.DiagnoserVersions, to.DiagnoserVersions)
+	diff.StatusChanges = diffValues(itemStatuses(from.Result), itemStatuses(to.Result))
+
+	diff.diffAnomalies(from.Result, to.Result)
+	diff.diffShipping(from.Result, to.Result)
+
+	return diff
+}
+
+// diffAnomalies 异常项增减（按类型与描述识别同一异常）
+func (d *DiagnosisDiff) diffAnomalies(from, to *DiagnoseResult) {
+	var before, after model.AnomalyResult
+	from.decodeItem(model.DiagnosisTypeAnomaly, &before)
+	to.decodeItem(model.DiagnosisTypeAnomaly, &after)
+
+	key := func(issue model.AnomalyItem) string {
+		return issue.Type + "|" + issue.Message
+	}
+	seen := make(map[string]bool, len(before.Issues))
+	for _, issue := range before.Issues {
+		seen[key(issue)] = true
+	}
+	kept := make(map[string]bool, len(after.Issues))
+	for _, issue := range after.Issues {
+		kept[key(issue)] = true
+		if !seen[key(issue)] {
+			d.AnomaliesAdded = append(d.AnomaliesAdded, issue)
+		}
+	}
+	for _, issue := range before.Issues {
+		if !kept[key(issue)] {
+			d.AnomaliesRemoved = append(d.AnomaliesRemoved, issue)
+		}
+	}
+}
+
+// diffShipping 推荐服务与费率变化（费率按 Carrier_Service 对应，比较应付总运费与时效）
+func (d *DiagnosisDiff) diffShipping(from, to *DiagnoseResult) {
+	var before, after model.ShippingResult
+	from.decodeItem(model.DiagnosisTypeShipping, &before)
+	to.decodeItem(model.DiagnosisTypeShipping, &after)
+
+	if before.RecommendedCode != after.RecommendedCode {
+		d.RecommendedCode = &ValueChange{From: before.RecommendedCode, To: after.RecommendedCode}
+	}
+
+	previous := make(map[string]model.ShippingRate, len(before.Rates))
+	for _, rate := range before.Rates {
+		previous[rateCode(rate)] = rate
+	}
+	current := make(map[string]bool, len(after.Rates))
+	for _, rate := range after.Rates {
+		code := rateCode(rate)
+		current[code] = true
+		old, ok := previous[code]
+		if !ok {
+			d.RatesAdded = append(d.RatesAdded, rateSummary(rate))
+			continue
+		}
+		if old.TotalFee == rate.TotalFee && old.TransitDays == rate.TransitDays && old.Currency == rate.Currency {
+			continue
+		}
+		d.RatesChanged = append(d.RatesChanged, &RateChange{
+			Code:            code,
+			Carrier:         rate.Carrier,
+			Service:         rate.Service,
+			Currency:        rate.Currency,
+			FromTotalFee:    old.TotalFee,
+			ToTotalFee:      rate.TotalFee,
+			TotalFeeDelta:   math.Round((rate.TotalFee-old.TotalFee)*100) / 100, // 差值可能为负，不使用 round2
+			FromTransitDays: old.TransitDays,
+			ToTransitDays:   rate.TransitDays,
+		})
+	}
+	for _, rate := range before.Rates {
+		if !current[rateCode(rate)] {
+			d.RatesRemoved = append(d.RatesRemoved, rateSummary(rate))
+		}
+	}
+}
+
+// itemStatuses 诊断类型 → 诊断项状态
+func itemStatuses(result *DiagnoseResult) map[string]string {
+	statuses := make(map[string]string)
+	if result == nil {
+		return statuses
+	}
+	for _, item := range result.Items {
+		if item != nil {
+			statuses[item.Type] = item.Status
+		}
+	}
+	return statuses
+}
+
+// diffValues 比较两组按诊断类型索引的取值（结果按诊断类型排序）
+func diffValues(from, to map[string]string) []*ItemChange {
+	types := make(map[string]bool, len(from)+len(to))
+	for t := range from {
+		types[t] = true
+	}
+	for t := range to {
+		types[t] = true
+	}
+
+	changes := make([]*ItemChange, 0)
+	for t := range types {
+		if from[t] != to[t] {
+			changes = append(changes, &ItemChange{Type: t, From: from[t], To: to[t]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Type < changes[j].Type
+	})
+	return changes
+}
+
+// rateCode 费率标识（与 RecommendedCode 格式一致）
+func rateCode(rate model.ShippingRate) string {
+	return rate.Carrier + "_" + rate.Service
+}
+
+func rateSummary(rate model.ShippingRate) *RateSummary {
+	return &RateSummary{
+		Code:        rateCode(rate),
+		Carrier:     rate.Carrier,
+		Service:     rate.Service,
+		TotalFee:    rate.TotalFee,
+		Currency:    rate.Currency,
+		TransitDays: rate.TransitDays,
+	}
+}
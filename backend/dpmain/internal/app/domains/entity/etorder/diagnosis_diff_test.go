@@ -0,0 +1,154 @@
+package etorder
+
+import (
+	"reflect"
+	"testing"
+
+	"oip/common/model"
+)
+
+func TestDiffDiagnoses(t *testing.T) {
+	highValue := model.AnomalyItem{Type: "HIGH_VALUE", Level: model.AnomalyLevelWarning, Message: "declared value exceeds 2000 USD"}
+	remoteArea := model.AnomalyItem{Type: "REMOTE_AREA", Level: model.AnomalyLevelInfo, Message: "remote delivery area"}
+	fedex := model.ShippingRate{Carrier: "FedEx", Service: "Ground", TotalFee: 12.3, Currency: "USD", TransitDays: 3}
+	ups := model.ShippingRate{Carrier: "UPS", Service: "Ground", TotalFee: 15.2, Currency: "USD", TransitDays: 3}
+	dhl := model.ShippingRate{Carrier: "DHL", Service: "Express", TotalFee: 30, Currency: "USD", TransitDays: 1}
+
+	cheaperFedex := fedex
+	cheaperFedex.TotalFee = 10.1
+	fasterUPS := ups
+	fasterUPS.TransitDays = 2
+
+	tests := []struct {
+		name     string
+		from, to *DiagnosisVersion
+		want     func(d *DiagnosisDiff)
+	}{
+		{
+			name: "identical versions",
+			from: newVersion(1, "v1", anomalyItem("a1", highValue), shippingItem("r1", "FedEx_Ground", fedex, ups)),
+			to:   newVersion(2, "v1", anomalyItem("a1", highValue), shippingItem("r1", "FedEx_Ground", fedex, ups)),
+			want: func(d *DiagnosisDiff) {},
+		},
+		{
+			name: "anomalies added and removed",
+			from: newVersion(1, "v1", anomalyItem("a1", highValue)),
+			to:   newVersion(2, "v1", anomalyItem("a1", remoteArea)),
+			want: func(d *DiagnosisDiff) {
+				d.AnomaliesAdded = []model.AnomalyItem{remoteArea}
+				d.AnomaliesRemoved = []model.AnomalyItem{highValue}
+			},
+		},
+		{
+			name: "rates added, removed and changed",
+			from: newVersion(1, "v1", shippingItem("r1", "UPS_Ground", fedex, ups, dhl)),
+			to:   newVersion(2, "v1", shippingItem("r2", "FedEx_Ground", cheaperFedex, fasterUPS)),
+			want: func(d *DiagnosisDiff) {
+				d.VersionChanges = []*ItemChange{{Type: model.DiagnosisTypeShipping, From: "r1", To: "r2"}}
+				d.RecommendedCode = &ValueChange{From: "UPS_Ground", To: "FedEx_Ground"}
+				d.RatesRemoved = []*RateSummary{{Code: "DHL_Express", Carrier: "DHL", Service: "Express", TotalFee: 30, Currency: "USD", TransitDays: 1}}
+				d.RatesChanged = []*RateChange{
+					{
+						Code: "FedEx_Ground", Carrier: "FedEx", Service: "Ground", Currency: "USD",
+						FromTotalFee: 12.3, ToTotalFee: 10.1, TotalFeeDelta: -2.2,
+						FromTransitDays: 3, ToTransitDays: 3,
+					},
+					{
+						Code: "UPS_Ground", Carrier: "UPS", Service: "Ground", Currency: "USD",
+						FromTotalFee: 15.2, ToTotalFee: 15.2, TotalFeeDelta: 0,
+						FromTransitDays: 3, ToTransitDays: 2,
+					},
+				}
+			},
+		},
+		{
+			name: "new rate added",
+			from: newVersion(1, "v1", shippingItem("r1", "FedEx_Ground", fedex)),
+			to:   newVersion(2, "v1", shippingItem("r1", "FedEx_Ground", fedex, dhl)),
+			want: func(d *DiagnosisDiff) {
+				d.RatesAdded = []*RateSummary{{Code: "DHL_Express", Carrier: "DHL", Service: "Express", TotalFee: 30, Currency: "USD", TransitDays: 1}}
+			},
+		},
+		{
+			name: "engine upgrade and failed diagnoser",
+			from: newVersion(1, "v1", anomalyItem("a1", highValue)),
+			to:   newVersion(2, "v2", &DiagnoseItem{Type: model.DiagnosisTypeAnomaly, Status: model.DiagnosisStatusFailed, Error: "timeout"}),
+			want: func(d *DiagnosisDiff) {
+				d.EngineVersion = &ValueChange{From: "v1", To: "v2"}
+				d.VersionChanges = []*ItemChange{{Type: model.DiagnosisTypeAnomaly, From: "a1", To: ""}}
+				d.StatusChanges = []*ItemChange{{Type: model.DiagnosisTypeAnomaly, From: model.DiagnosisStatusSuccess, To: model.DiagnosisStatusFailed}}
+				d.AnomaliesRemoved = []model.AnomalyItem{highValue}
+			},
+		},
+		{
+			name: "failed version has no items",
+			from: &DiagnosisVersion{OrderID: "order-1", Version: 1, Status: OrderStatusFailed, Error: "publish failed"},
+			to:   newVersion(2, "v1", anomalyItem("a1", highValue)),
+			want: func(d *DiagnosisDiff) {
+				d.EngineVersion = &ValueChange{From: "", To: "v1"}
+				d.VersionChanges = []*ItemChange{{Type: model.DiagnosisTypeAnomaly, From: "", To: "a1"}}
+				d.StatusChanges = []*ItemChange{{Type: model.DiagnosisTypeAnomaly, From: "", To: model.DiagnosisStatusSuccess}}
+				d.AnomaliesAdded = []model.AnomalyItem{highValue}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := &DiagnosisDiff{
+				OrderID:          "order-1",
+				From:             tt.from.Version,
+				To:               tt.to.Version,
+				VersionChanges:   make([]*ItemChange, 0),
+				StatusChanges:    make([]*ItemChange, 0),
+				AnomaliesAdded:   make([]model.AnomalyItem, 0),
+				AnomaliesRemoved: make([]model.AnomalyItem, 0),
+				RatesAdded:       make([]*RateSummary, 0),
+				RatesRemoved:     make([]*RateSummary, 0),
+				RatesChanged:     make([]*RateChange, 0),
+			}
+			tt.want(want)
+
+			got := DiffDiagnoses(tt.from, tt.to)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("DiffDiagnoses() =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+}
+
+// newVersion 构造成功的诊断版本（诊断项版本取自 DiagnoseItem.Version）
+func newVersion(version int, engineVersion string, items ...*DiagnoseItem) *DiagnosisVersion {
+	versions := make(map[string]string, len(items))
+	for _, item := range items {
+		if item.Version != "" {
+			versions[item.Type] = item.Version
+		}
+	}
+	return &DiagnosisVersion{
+		OrderID:           "order-1",
+		Version:           version,
+		Status:            OrderStatusDiagnosed,
+		EngineVersion:     engineVersion,
+		DiagnoserVersions: versions,
+		Result:            &DiagnoseResult{EngineVersion: engineVersion, Items: items},
+	}
+}
+
+func anomalyItem(version string, issues ...model.AnomalyItem) *DiagnoseItem {
+	return &DiagnoseItem{
+		Type:     model.DiagnosisTypeAnomaly,
+		Status:   model.DiagnosisStatusSuccess,
+		DataJSON: model.AnomalyResult{HasRisk: len(issues) > 0, Issues: issues},
+		Version:  version,
+	}
+}
+
+func shippingItem(version, recommended string, rates ...model.ShippingRate) *DiagnoseItem {
+	return &DiagnoseItem{
+		Type:     model.DiagnosisTypeShipping,
+		Status:   model.DiagnosisStatusSuccess,
+		DataJSON: model.ShippingResult{RecommendedCode: recommended, Rates: rates},
+		Version:  version,
+	}
+}
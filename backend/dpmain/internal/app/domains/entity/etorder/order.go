@@ -1,8 +1,11 @@
 package etorder
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
+
+	"oip/common/model"
 )
 
 // 错误定义
@@ -12,18 +15,23 @@ var (
 	ErrInvalidMerchantOrderNo = errors.New("merchant order number cannot be empty")
 	ErrInvalidShipment        = errors.New("invalid shipment data")
 	ErrNilDiagnoseResult      = errors.New("diagnose result cannot be nil")
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrDiagnosisNotFound      = errors.New("diagnosis version not found")
+	ErrOrderDiagnosing        = errors.New("order is already being diagnosed")
 )
 
 // Order 订单聚合根（领域对象）
 type Order struct {
-	ID              string          // 订单ID (UUID)
-	AccountID       int64           // 账户ID
-	MerchantOrderNo string          // 商户订单号
-	Shipment        *Shipment       // 货件信息
-	Status          OrderStatus     // 订单状态
-	DiagnoseResult  *DiagnoseResult // 诊断结果
-	CreatedAt       time.Time       // 创建时间
-	UpdatedAt       time.Time       // 更新时间
+	ID                string           // 订单ID (UUID)
+	AccountID         int64            // 账户ID
+	MerchantOrderNo   string           // 商户订单号
+	Shipment          *Shipment        // 货件信息
+	DiagnoseOptions   *DiagnoseOptions // 诊断选项
+	Status            OrderStatus      // 订单状态
+	DiagnoseResult    *DiagnoseResult  // 诊断结果
+	DiagnoseRequestID string           // 最近一次下发的诊断任务 request_id
+	CreatedAt         time.Time        // 创建时间
+	UpdatedAt         time.Time        // 更新时间
 }
 
 // OrderStatus 订单状态
@@ -35,6 +43,21 @@ const (
 	OrderStatusFailed     OrderStatus = "FAILED"
 )
 
+// DiagnoseOptions 诊断选项（值对象）
+// 下单时由请求参数与账号设置合并得出，随诊断任务下发给 dpsync
+type DiagnoseOptions struct {
+	Diagnosers           []string                      // 需要执行的诊断类型（为空表示执行全部）
+	PreferredCurrency    string                        // 偏好币种（来自账号设置）
+	Strategy             *model.RecommendationStrategy // 费率推荐策略（请求未指定时取账号设置）
+	Contracts            []model.RateContract          // 账号协议价（下单时快照，重新诊断复用）
+	PromisedDeliveryDate string                        // 承诺送达日（YYYY-MM-DD，可选）
+	Boxes                []model.Box                   // 账号箱型目录（下单时快照）
+	Incoterm             string                        // 贸易术语 DDP/DDU（为空时按 DDU 处理）
+	Account              *model.AccountProfile         // 下单账号概况（下单时快照，用于风险评分）
+	Selected             *model.SelectedService        // 商家选用的承运商服务与报价（可选，用于核对是否多付运费）
+	Enrichments          []model.ItemEnrichment        // 下单时由商品目录补全的商品字段（补全结果已写入货件，重新诊断复用）
+}
+
 // Shipment 货件信息（值对象）
 type Shipment struct {
 	ShipFrom *Address
@@ -79,11 +102,21 @@ type Dimension struct {
 
 // Item 商品（值对象）
 type Item struct {
-	Description string
-	Quantity    int
-	Price       *Money
-	SKU         string
-	Weight      *Weight
+	Description   string
+	Quantity      int
+	Price         *Money
+	SKU           string
+	Weight        *Weight    // 单件重量（可选）
+	Dimension     *Dimension // 单件尺寸（可选）
+	HSCode        string     // 海关编码（可选）
+	OriginCountry string     // 原产国（可选）
+	Battery       *Battery   // 所含电池（可选）
+}
+
+// Battery 商品所含电池（值对象）
+type Battery struct {
+	Chemistry string // LITHIUM_ION/LITHIUM_METAL/OTHER
+	Packing   string // STANDALONE/PACKED_WITH_EQUIPMENT/CONTAINED_IN_EQUIPMENT
 }
 
 // Money 金额（值对象）
@@ -93,16 +126,36 @@ type Money struct {
 }
 
 // DiagnoseResult 诊断结果（值对象）
+// JSON 字段与 dpsync 回调的 model.DiagnosisResultData 一致（回调与 Smart Wait 写入同一列）
+// Traces 仅在 explain 模式诊断时存在，持久化时单独存入 diagnose_trace 列
 type DiagnoseResult struct {
-	Items []*DiagnoseItem
+	EngineVersion string                 `json:"engine_version,omitempty"` // 诊断引擎版本
+	Items         []*DiagnoseItem        `json:"items"`
+	Partial       bool                   `json:"partial"`               // 是否部分成功（任一诊断项失败或超时）
+	Traces        []model.DiagnoserTrace `json:"traces,omitempty"`      // 各诊断项的执行轨迹（与 Items 一一对应）
+	Enrichments   []model.ItemEnrichment `json:"enrichments,omitempty"` // 下单时由商品目录补全的商品字段
 }
 
 // DiagnoseItem 单个诊断项
 type DiagnoseItem struct {
-	Type     string
-	Status   string
-	DataJSON interface{}
-	Error    string
+	Type     string      `json:"type"`
+	Status   string      `json:"status"`
+	DataJSON interface{} `json:"data_json"`
+	Error    string      `json:"error,omitempty"`
+	Version  string      `json:"version,omitempty"` // 产生该结果的规则/费率卡版本
+}
+
+// DiagnosisVersion 订单的一次诊断记录（值对象，按回调顺序编号）
+type DiagnosisVersion struct {
+	OrderID           string
+	Version           int               // 订单内从 1 开始递增
+	RequestID         string            // 诊断回调 request_id
+	Status            OrderStatus       // DIAGNOSED/FAILED
+	EngineVersion     string            // 诊断引擎版本
+	DiagnoserVersions map[string]string // 诊断类型 → 规则/费率卡版本
+	Result            *DiagnoseResult   // 诊断结果（失败时为空）
+	Error             string            // 失败原因
+	CreatedAt         time.Time
 }
 
 // NewOrder 创建订单（工厂方法）
@@ -126,12 +179,21 @@ func NewOrder(id string, accountID int64, merchantOrderNo string, shipment *Ship
 		AccountID:       accountID,
 		MerchantOrderNo: merchantOrderNo,
 		Shipment:        shipment,
+		DiagnoseOptions: &DiagnoseOptions{},
 		Status:          OrderStatusDiagnosing,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}, nil
 }
 
+// SetDiagnoseOptions 设置诊断选项（领域行为）
+func (o *Order) SetDiagnoseOptions(options *DiagnoseOptions) {
+	if options == nil {
+		options = &DiagnoseOptions{}
+	}
+	o.DiagnoseOptions = options
+}
+
 // UpdateDiagnoseResult 更新诊断结果（领域行为）
 func (o *Order) UpdateDiagnoseResult(result *DiagnoseResult) error {
 	if result == nil {
@@ -148,3 +210,22 @@ func (o *Order) MarkAsFailed() {
 	o.Status = OrderStatusFailed
 	o.UpdatedAt = time.Now()
 }
+
+// decodeItem 将指定类型的成功诊断项解析为结果结构（诊断项不存在、失败或解析失败时返回 false）
+// DataJSON 从数据库反序列化后为通用结构，重新编码后按结果结构解析
+func (r *DiagnoseResult) decodeItem(diagnosisType string, v interface{}) bool {
+	if r == nil {
+		return false
+	}
+	for _, item := range r.Items {
+		if item == nil || item.Type != diagnosisType || item.Status != model.DiagnosisStatusSuccess {
+			continue
+		}
+		data, err := json.Marshal(item.DataJSON)
+		if err != nil {
+			return false
+		}
+		return json.Unmarshal(data, v) == nil
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package etorder
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// SavingsReport 账号运费节省报告（值对象）
+// 汇总时间范围内各订单的选用服务核对结果（service_audit 诊断项）
+type SavingsReport struct {
+	AccountID        int64
+	From             time.Time // 起始时间（含）
+	To               time.Time // 截止时间（不含）
+	Currency         string    // 金额币种（与诊断费率币种一致）
+	AuditedOrders    int       // 完成核对的订单数
+	OverpayingOrders int       // 存在更便宜可用服务的订单数
+	SelectedSpend    float64   // 选用服务运费合计（核对基准）
+	PotentialSavings float64   // 改用最便宜服务可节省的运费合计
+	Services         []*ServiceSavings
+	Alternatives     []*AlternativeSavings
+}
+
+// ServiceSavings 按商家选用服务汇总
+type ServiceSavings struct {
+	Carrier          string
+	Service          string
+	Orders           int
+	OverpayingOrders int
+	SelectedSpend    float64
+	PotentialSavings float64
+}
+
+// AlternativeSavings 按建议改用的最便宜服务汇总
+type AlternativeSavings struct {
+	Code             string // Carrier_Service
+	Orders           int
+	PotentialSavings float64
+}
+
+// NewSavingsReport 创建空的节省报告
+func NewSavingsReport(accountID int64, from, to time.Time) *SavingsReport {
+	return &SavingsReport{
+		AccountID:    accountID,
+		From:         from,
+		To:           to,
+		Services:     make([]*ServiceSavings, 0),
+		Alternatives: make([]*AlternativeSavings, 0),
+	}
+}
+
+// ServiceAudit 取出诊断结果中的选用服务核对结果（诊断项不存在、失败或无法核对时 ok=false）
+func (r *DiagnoseResult) ServiceAudit() (*model.ServiceAuditResult, bool) {
+	var audit model.ServiceAuditResult
+	if !r.decodeItem(model.DiagnosisTypeServiceAudit, &audit) {
+		return nil, false
+	}
+	if audit.Selected == nil || audit.Unavailable != "" {
+		return nil, false
+	}
+	return &audit, true
+}
+
+// Add 计入一个订单的核对结果
+func (r *SavingsReport) Add(audit *model.ServiceAuditResult) {
+	if r.Currency == "" {
+		r.Currency = audit.Currency
+	}
+	r.AuditedOrders++
+	r.SelectedSpend += audit.SelectedFee
+	r.PotentialSavings += audit.PotentialSavings
+
+	service := r.service(audit.Selected.Carrier, audit.Selected.Service)
+	service.Orders++
+	service.SelectedSpend += audit.SelectedFee
+	service.PotentialSavings += audit.PotentialSavings
+
+	if !audit.Overpaying {
+		return
+	}
+	r.OverpayingOrders++
+	service.OverpayingOrders++
+
+	alternative := r.alternative(audit.CheapestCode)
+	alternative.Orders++
+	alternative.PotentialSavings += audit.PotentialSavings
+}
+
+// Finalize 金额保留两位小数，明细按节省金额降序排列
+func (r *SavingsReport) Finalize() {
+	r.SelectedSpend = round2(r.SelectedSpend)
+	r.PotentialSavings = round2(r.PotentialSavings)
+	for _, service := range r.Services {
+		service.SelectedSpend = round2(service.SelectedSpend)
+		service.PotentialSavings = round2(service.PotentialSavings)
+	}
+	for _, alternative := range r.Alternatives {
+		alternative.PotentialSavings = round2(alternative.PotentialSavings)
+	}
+
+	sort.SliceStable(r.Services, func(i, j int) bool {
+		return r.Services[i].PotentialSavings > r.Services[j].PotentialSavings
+	})
+	sort.SliceStable(r.Alternatives, func(i, j int) bool {
+		return r.Alternatives[i].PotentialSavings > r.Alternatives[j].PotentialSavings
+	})
+}
+
+// service 查找或创建选用服务汇总项（承运商与服务名称大小写不敏感）
+func (r *SavingsReport) service(carrier, service string) *ServiceSavings {
+	for _, s := range r.Services {
+		if strings.EqualFold(s.Carrier, carrier) && strings.EqualFold(s.Service, service) {
+			return s
+		}
+	}
+	s := &ServiceSavings{Carrier: carrier, Service: service}
+	r.Services = append(r.Services, s)
+	return s
+}
+
+// alternative 查找或创建替代服务汇总项
+func (r *SavingsReport) alternative(code string) *AlternativeSavings {
+	for _, a := range r.Alternatives {
+		if a.Code == code {
+			return a
+		}
+	}
+	a := &AlternativeSavings{Code: code}
+	r.Alternatives = append(r.Alternatives, a)
+	return a
+}
+
+// round2 四舍五入到两位小数
+func round2(f float64) float64 {
+	return float64(int64(f*100+0.5)) / 100
+}
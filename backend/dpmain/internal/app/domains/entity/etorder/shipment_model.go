@@ -0,0 +1,114 @@
+package etorder
+
+import "oip/common/model"
+
+// ToModel 转换为诊断任务使用的标准货件结构（common/model）
+func (s *Shipment) ToModel() *model.Shipment {
+	if s == nil {
+		return nil
+	}
+
+	parcels := make([]*model.Parcel, 0, len(s.Parcels))
+	for _, parcel := range s.Parcels {
+		parcels = append(parcels, parcel.toModel())
+	}
+
+	return &model.Shipment{
+		ShipFrom: s.ShipFrom.toModel(),
+		ShipTo:   s.ShipTo.toModel(),
+		Parcels:  parcels,
+	}
+}
+
+func (a *Address) toModel() *model.Address {
+	if a == nil {
+		return nil
+	}
+	return &model.Address{
+		ContactName: a.ContactName,
+		CompanyName: a.CompanyName,
+		Street1:     a.Street1,
+		Street2:     a.Street2,
+		City:        a.City,
+		State:       a.State,
+		PostalCode:  a.PostalCode,
+		Country:     a.Country,
+		Phone:       a.Phone,
+		Email:       a.Email,
+	}
+}
+
+func (p *Parcel) toModel() *model.Parcel {
+	if p == nil {
+		return nil
+	}
+
+	items := make([]*model.Item, 0, len(p.Items))
+	for _, item := range p.Items {
+		items = append(items, item.toModel())
+	}
+
+	return &model.Parcel{
+		Weight:    p.Weight.toModel(),
+		Dimension: p.Dimension.toModel(),
+		Items:     items,
+	}
+}
+
+func (w *Weight) toModel() *model.Weight {
+	if w == nil {
+		return nil
+	}
+	return &model.Weight{
+		Value: w.Value,
+		Unit:  w.Unit,
+	}
+}
+
+func (d *Dimension) toModel() *model.Dimension {
+	if d == nil {
+		return nil
+	}
+	return &model.Dimension{
+		Width:  d.Width,
+		Height: d.Height,
+		Depth:  d.Depth,
+		Unit:   d.Unit,
+	}
+}
+
+func (i *Item) toModel() *model.Item {
+	if i == nil {
+		return nil
+	}
+
+	var price *model.Money
+	if i.Price != nil {
+		price = &model.Money{
+			Amount:   i.Price.Amount,
+			Currency: i.Price.Currency,
+		}
+	}
+
+	return &model.Item{
+		Description:   i.Description,
+		Quantity:      i.Quantity,
+		Price:         price,
+		SKU:           i.SKU,
+		Weight:        i.Weight.toModel(),
+		Dimension:     i.Dimension.toModel(),
+		HSCode:        i.HSCode,
+		OriginCountry: i.OriginCountry,
+		Battery:       i.Battery.toModel(),
+	}
+}
+
+func (b *Battery) toModel() *model.Battery {
+	if b == nil {
+		return nil
+	}
+	return &model.Battery{
+		Chemistry: b.Chemistry,
+		Packing:   b.Packing,
+	}
+}
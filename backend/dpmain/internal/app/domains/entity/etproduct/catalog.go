@@ -0,0 +1,127 @@
+package etproduct
+
+import (
+	"strings"
+
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etorder"
+)
+
+// Catalog 商品目录视图（下单时按订单商品 SKU 加载，用于补全商品字段）
+type Catalog struct {
+	bySKU map[string]*Product
+}
+
+// NewCatalog 创建商品目录视图
+func NewCatalog(products []*Product) *Catalog {
+	catalog := &Catalog{
+		bySKU: make(map[string]*Product, len(products)),
+	}
+	for _, product := range products {
+		catalog.bySKU[NormalizeSKU(product.SKU)] = product
+	}
+	return catalog
+}
+
+// LookupSKUs 货件中需要查询目录的 SKU（未提供 SKU 的商品不补全）
+func LookupSKUs(shipment *etorder.Shipment) []string {
+	skus := make([]string, 0)
+	if shipment == nil {
+		return skus
+	}
+
+	for _, parcel := range shipment.Parcels {
+		if parcel == nil {
+			continue
+		}
+		for _, item := range parcel.Items {
+			if item == nil {
+				continue
+			}
+			if sku := strings.TrimSpace(item.SKU); sku != "" {
+				skus = append(skus, sku)
+			}
+		}
+	}
+	return skus
+}
+
+// Enrich 用目录补全货件中商品缺失的字段，返回补全记录（按包裹、商品顺序，未补全的商品不出现）
+// 商家提供的字段一律保留，只填充空值；按 SKU 匹配目录商品，未提供 SKU 的商品不补全
+func (c *Catalog) Enrich(shipment *etorder.Shipment) []model.ItemEnrichment {
+	enrichments := make([]model.ItemEnrichment, 0)
+	if shipment == nil {
+		return enrichments
+	}
+
+	for i, parcel := range shipment.Parcels {
+		if parcel == nil {
+			continue
+		}
+		for j, item := range parcel.Items {
+			if item == nil {
+				continue
+			}
+			product := c.match(item)
+			if product == nil {
+				continue
+			}
+			fields := enrichItem(item, product)
+			if len(fields) == 0 {
+				continue
+			}
+			enrichments = append(enrichments, model.ItemEnrichment{
+				ParcelIndex: i,
+				ItemIndex:   j,
+				SKU:         product.SKU,
+				Fields:      fields,
+			})
+		}
+	}
+	return enrichments
+}
+
+// match 按 SKU 查找订单商品对应的目录商品
+func (c *Catalog) match(item *etorder.Item) *Product {
+	if strings.TrimSpace(item.SKU) == "" {
+		return nil
+	}
+	return c.bySKU[NormalizeSKU(item.SKU)]
+}
+
+// enrichItem 用目录商品填充订单商品的空字段，返回填充的字段名
+func enrichItem(item *etorder.Item, product *Product) []string {
+	fields := make([]string, 0)
+
+	if strings.TrimSpace(item.Description) == "" && product.Description != "" {
+		item.Description = product.Description
+		fields = append(fields, model.EnrichedFieldDescription)
+	}
+	if (item.Weight == nil || item.Weight.Value <= 0) && product.Weight != nil {
+		item.Weight = &etorder.Weight{Value: product.Weight.Value, Unit: product.Weight.Unit}
+		fields = append(fields, model.EnrichedFieldWeight)
+	}
+	if item.Dimension == nil && product.Dimension != nil {
+		item.Dimension = &etorder.Dimension{
+			Width:  product.Dimension.Width,
+			Height: product.Dimension.Height,
+			Depth:  product.Dimension.Depth,
+			Unit:   product.Dimension.Unit,
+		}
+		fields = append(fields, model.EnrichedFieldDimension)
+	}
+	if strings.TrimSpace(item.HSCode) == "" && product.HSCode != "" {
+		item.HSCode = product.HSCode
+		fields = append(fields, model.EnrichedFieldHSCode)
+	}
+	if strings.TrimSpace(item.OriginCountry) == "" && product.OriginCountry != "" {
+		item.OriginCountry = product.OriginCountry
+		fields = append(fields, model.EnrichedFieldOriginCountry)
+	}
+	if item.Battery == nil && product.Battery != nil {
+		item.Battery = &etorder.Battery{Chemistry: product.Battery.Chemistry, Packing: product.Battery.Packing}
+		fields = append(fields, model.EnrichedFieldBattery)
+	}
+
+	return fields
+}
@@ -0,0 +1,133 @@
+package etproduct
+
+import (
+	"reflect"
+	"testing"
+
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etorder"
+)
+
+func TestCatalogEnrich(t *testing.T) {
+	catalog := NewCatalog([]*Product{
+		{
+			SKU:           "MUG-01",
+			Description:   "Ceramic mug",
+			Weight:        &model.Weight{Value: 0.4, Unit: "kg"},
+			Dimension:     &model.Dimension{Width: 10, Height: 12, Depth: 10, Unit: "cm"},
+			HSCode:        "691200",
+			OriginCountry: "CN",
+		},
+		{
+			SKU:     "POWERBANK",
+			Weight:  &model.Weight{Value: 0.3, Unit: "kg"},
+			Battery: &model.Battery{Chemistry: "LITHIUM_ION", Packing: "STANDALONE"},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		item   *etorder.Item
+		want   *etorder.Item // 补全后的商品
+		fields []string      // 为空表示不应产生补全记录（目录 SKU 均为规范化形式）
+	}{
+		{
+			name: "fills all missing fields by SKU",
+			item: &etorder.Item{SKU: "MUG-01", Quantity: 1},
+			want: &etorder.Item{
+				SKU:           "MUG-01",
+				Quantity:      1,
+				Description:   "Ceramic mug",
+				Weight:        &etorder.Weight{Value: 0.4, Unit: "kg"},
+				Dimension:     &etorder.Dimension{Width: 10, Height: 12, Depth: 10, Unit: "cm"},
+				HSCode:        "691200",
+				OriginCountry: "CN",
+			},
+			fields: []string{
+				model.EnrichedFieldDescription, model.EnrichedFieldWeight, model.EnrichedFieldDimension,
+				model.EnrichedFieldHSCode, model.EnrichedFieldOriginCountry,
+			},
+		},
+		{
+			name: "keeps merchant-provided values",
+			item: &etorder.Item{SKU: "MUG-01", Description: "Coffee mug", Weight: &etorder.Weight{Value: 0.5, Unit: "kg"}, OriginCountry: "PT"},
+			want: &etorder.Item{
+				SKU:           "MUG-01",
+				Description:   "Coffee mug",
+				Weight:        &etorder.Weight{Value: 0.5, Unit: "kg"},
+				Dimension:     &etorder.Dimension{Width: 10, Height: 12, Depth: 10, Unit: "cm"},
+				HSCode:        "691200",
+				OriginCountry: "PT",
+			},
+			fields: []string{model.EnrichedFieldDimension, model.EnrichedFieldHSCode},
+		},
+		{
+			name: "zero weight is treated as missing",
+			item: &etorder.Item{SKU: "powerbank ", Description: "Power bank", Weight: &etorder.Weight{Value: 0, Unit: "kg"}},
+			want: &etorder.Item{
+				SKU:         "powerbank ",
+				Description: "Power bank",
+				Weight:      &etorder.Weight{Value: 0.3, Unit: "kg"},
+				Battery:     &etorder.Battery{Chemistry: "LITHIUM_ION", Packing: "STANDALONE"},
+			},
+			fields: []string{model.EnrichedFieldWeight, model.EnrichedFieldBattery},
+		},
+		{
+			name: "item without SKU is not enriched",
+			item: &etorder.Item{Description: "Ceramic mug"},
+			want: &etorder.Item{Description: "Ceramic mug"},
+		},
+		{
+			name: "unknown SKU is not enriched",
+			item: &etorder.Item{SKU: "UNKNOWN"},
+			want: &etorder.Item{SKU: "UNKNOWN"},
+		},
+		{
+			name: "fully specified item produces no record",
+			item: &etorder.Item{SKU: "POWERBANK", Description: "Power bank", Weight: &etorder.Weight{Value: 0.3, Unit: "kg"}, Battery: &etorder.Battery{Chemistry: "LITHIUM_ION"}},
+			want: &etorder.Item{SKU: "POWERBANK", Description: "Power bank", Weight: &etorder.Weight{Value: 0.3, Unit: "kg"}, Battery: &etorder.Battery{Chemistry: "LITHIUM_ION"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 第二个包裹放置待测商品，校验补全记录的包裹与商品序号
+			shipment := &etorder.Shipment{Parcels: []*etorder.Parcel{
+				{Items: []*etorder.Item{{Description: "Gift card"}}},
+				{Items: []*etorder.Item{nil, tt.item}},
+			}}
+
+			got := catalog.Enrich(shipment)
+
+			if !reflect.DeepEqual(tt.item, tt.want) {
+				t.Errorf("enriched item = %+v, want %+v", tt.item, tt.want)
+			}
+			if len(tt.fields) == 0 {
+				if len(got) != 0 {
+					t.Errorf("Enrich() = %+v, want no enrichments", got)
+				}
+				return
+			}
+			want := []model.ItemEnrichment{{ParcelIndex: 1, ItemIndex: 1, SKU: NormalizeSKU(tt.item.SKU), Fields: tt.fields}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Enrich() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLookupSKUs(t *testing.T) {
+	shipment := &etorder.Shipment{Parcels: []*etorder.Parcel{
+		{Items: []*etorder.Item{{SKU: " MUG-01 "}, {Description: "no sku"}, nil}},
+		nil,
+		{Items: []*etorder.Item{{SKU: "POWERBANK"}}},
+	}}
+
+	want := []string{"MUG-01", "POWERBANK"}
+	if got := LookupSKUs(shipment); !reflect.DeepEqual(got, want) {
+		t.Errorf("LookupSKUs() = %v, want %v", got, want)
+	}
+	if got := LookupSKUs(nil); len(got) != 0 {
+		t.Errorf("LookupSKUs(nil) = %v, want empty", got)
+	}
+}
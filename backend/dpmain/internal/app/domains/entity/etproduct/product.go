@@ -0,0 +1,205 @@
+package etproduct
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"oip/common/model"
+)
+
+// 错误定义
+var (
+	ErrInvalidProductID = errors.New("invalid product ID")
+	ErrInvalidAccountID = errors.New("invalid account ID")
+	ErrProductNotFound  = errors.New("product not found")
+)
+
+// 字段长度限制（与 products 表一致）
+const (
+	maxSKULength         = 128
+	maxDescriptionLength = 255
+	maxHSCodeLength      = 16
+)
+
+// MaxImportProducts 单次批量导入的商品数上限
+const MaxImportProducts = 1000
+
+// Product 账号商品目录中的商品（SKU 主数据）
+// 下单时用于补全订单商品缺失的重量、尺寸、HS 编码、原产国与电池信息
+type Product struct {
+	ID            int64
+	AccountID     int64
+	SKU           string
+	Description   string           // 品名（订单商品未填品名时补全）
+	Weight        *model.Weight    // 单件重量
+	Dimension     *model.Dimension // 单件尺寸
+	HSCode        string
+	OriginCountry string
+	Battery       *model.Battery // 所含电池（为空表示不含电池）
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Attributes 商品属性（值对象，创建、更新与批量导入商品时使用）
+type Attributes struct {
+	SKU           string
+	Description   string
+	Weight        *model.Weight
+	Dimension     *model.Dimension
+	HSCode        string
+	OriginCountry string
+	Battery       *model.Battery
+}
+
+// NewProduct 创建商品（工厂方法）
+func NewProduct(id, accountID int64, attrs *Attributes) (*Product, error) {
+	if id <= 0 {
+		return nil, ErrInvalidProductID
+	}
+	if accountID <= 0 {
+		return nil, ErrInvalidAccountID
+	}
+
+	now := time.Now()
+	product := &Product{
+		ID:        id,
+		AccountID: accountID,
+		CreatedAt: now,
+	}
+	if err := product.UpdateAttributes(attrs); err != nil {
+		return nil, err
+	}
+	product.UpdatedAt = now
+
+	return product, nil
+}
+
+// UpdateAttributes 整体替换商品属性（领域行为）
+func (p *Product) UpdateAttributes(attrs *Attributes) error {
+	if attrs == nil {
+		return errors.New("product attributes are required")
+	}
+
+	candidate := attrs.normalize()
+	if err := candidate.validate(); err != nil {
+		return err
+	}
+
+	p.SKU = candidate.SKU
+	p.Description = candidate.Description
+	p.Weight = candidate.Weight
+	p.Dimension = candidate.Dimension
+	p.HSCode = candidate.HSCode
+	p.OriginCountry = candidate.OriginCountry
+	p.Battery = candidate.Battery
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Validate 校验商品属性
+func (a *Attributes) Validate() error {
+	candidate := a.normalize()
+	return candidate.validate()
+}
+
+// NormalizedSKU 归一化后的 SKU（批量导入时与已有商品对应）
+func (a *Attributes) NormalizedSKU() string {
+	return NormalizeSKU(a.SKU)
+}
+
+// ValidateImport 校验批量导入的商品（数量上限、逐项校验且 SKU 不重复）
+func ValidateImport(attrsList []*Attributes) error {
+	if len(attrsList) == 0 {
+		return errors.New("products cannot be empty")
+	}
+	if len(attrsList) > MaxImportProducts {
+		return fmt.Errorf("cannot import more than %d products at once", MaxImportProducts)
+	}
+
+	seen := make(map[string]bool, len(attrsList))
+	for i, attrs := range attrsList {
+		if attrs == nil {
+			return fmt.Errorf("products[%d] cannot be null", i)
+		}
+		if err := attrs.Validate(); err != nil {
+			return fmt.Errorf("products[%d]: %w", i, err)
+		}
+		sku := attrs.NormalizedSKU()
+		if seen[sku] {
+			return fmt.Errorf("products[%d]: duplicate sku %s", i, strings.TrimSpace(attrs.SKU))
+		}
+		seen[sku] = true
+	}
+	return nil
+}
+
+// normalize 归一化属性（去空格，单位转小写，原产国与电池枚举转大写）
+func (a *Attributes) normalize() Attributes {
+	attrs := Attributes{
+		SKU:           strings.TrimSpace(a.SKU),
+		Description:   strings.TrimSpace(a.Description),
+		HSCode:        strings.TrimSpace(a.HSCode),
+		OriginCountry: strings.ToUpper(strings.TrimSpace(a.OriginCountry)),
+	}
+	if a.Weight != nil {
+		attrs.Weight = &model.Weight{
+			Value: a.Weight.Value,
+			Unit:  strings.ToLower(strings.TrimSpace(a.Weight.Unit)),
+		}
+	}
+	if a.Dimension != nil {
+		attrs.Dimension = &model.Dimension{
+			Width:  a.Dimension.Width,
+			Height: a.Dimension.Height,
+			Depth:  a.Dimension.Depth,
+			Unit:   strings.ToLower(strings.TrimSpace(a.Dimension.Unit)),
+		}
+	}
+	if a.Battery != nil {
+		attrs.Battery = &model.Battery{
+			Chemistry: strings.ToUpper(strings.TrimSpace(a.Battery.Chemistry)),
+			Packing:   strings.ToUpper(strings.TrimSpace(a.Battery.Packing)),
+		}
+	}
+	return attrs
+}
+
+// validate 校验归一化后的属性（重量、尺寸与电池沿用货件商品的校验规则，且重量、尺寸必须为正数）
+func (a *Attributes) validate() error {
+	if a.SKU == "" {
+		return errors.New("sku is required")
+	}
+	if utf8.RuneCountInString(a.SKU) > maxSKULength {
+		return fmt.Errorf("sku cannot exceed %d characters", maxSKULength)
+	}
+	if utf8.RuneCountInString(a.Description) > maxDescriptionLength {
+		return fmt.Errorf("product %s: description cannot exceed %d characters", a.SKU, maxDescriptionLength)
+	}
+	if len(a.HSCode) > maxHSCodeLength {
+		return fmt.Errorf("product %s: hs_code cannot exceed %d characters", a.SKU, maxHSCodeLength)
+	}
+	if n := len(a.OriginCountry); n != 0 && n != 2 && n != 3 {
+		return fmt.Errorf("product %s: origin_country must be an ISO 3166 country code", a.SKU)
+	}
+
+	item := &model.Item{Weight: a.Weight, Dimension: a.Dimension, Battery: a.Battery}
+	if err := item.ValidateAttributes("product " + a.SKU); err != nil {
+		return err
+	}
+	if a.Weight != nil && a.Weight.Value <= 0 {
+		return fmt.Errorf("product %s: weight must be positive", a.SKU)
+	}
+	if a.Dimension != nil && (a.Dimension.Width <= 0 || a.Dimension.Height <= 0 || a.Dimension.Depth <= 0) {
+		return fmt.Errorf("product %s: dimension must be positive", a.SKU)
+	}
+	return nil
+}
+
+// NormalizeSKU SKU 匹配键（去空格、忽略大小写，与 products 表唯一索引的排序规则一致）
+func NormalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
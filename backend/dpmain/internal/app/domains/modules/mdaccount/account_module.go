@@ -29,6 +29,11 @@ func (m *AccountModule) GetAccount(ctx context.Context, accountID int64) (*etacc
 	return m.accountRepo.GetByID(ctx, accountID)
 }
 
+// UpdateSettings 更新账号设置
+func (m *AccountModule) UpdateSettings(ctx context.Context, accountID int64, settings *etaccount.Settings) error {
+	return m.accountRepo.UpdateSettings(ctx, accountID, settings)
+}
+
 // GetAccountByEmail 根据邮箱查询账号（检查重复）
 func (m *AccountModule) GetAccountByEmail(ctx context.Context, email string) (*etaccount.Account, error) {
 	return m.accountRepo.GetByEmail(ctx, email)
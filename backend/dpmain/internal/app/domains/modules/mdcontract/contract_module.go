@@ -0,0 +1,58 @@
+package mdcontract
+
+import (
+	"context"
+
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+	"oip/dpmain/internal/app/domains/repo/rpaccount"
+	"oip/dpmain/internal/app/domains/repo/rpcontract"
+)
+
+// ContractModule 协议价模块
+type ContractModule struct {
+	contractRepo rpcontract.ContractRepository
+	accountRepo  rpaccount.AccountRepository
+}
+
+// NewContractModule 创建协议价模块
+func NewContractModule(contractRepo rpcontract.ContractRepository, accountRepo rpaccount.AccountRepository) *ContractModule {
+	return &ContractModule{
+		contractRepo: contractRepo,
+		accountRepo:  accountRepo,
+	}
+}
+
+// CreateContract 创建合同（数据操作）
+func (m *ContractModule) CreateContract(ctx context.Context, contract *etcontract.Contract) error {
+	return m.contractRepo.Create(ctx, contract)
+}
+
+// UpdateContract 更新合同
+func (m *ContractModule) UpdateContract(ctx context.Context, contract *etcontract.Contract) error {
+	return m.contractRepo.Update(ctx, contract)
+}
+
+// DeleteContract 删除合同
+func (m *ContractModule) DeleteContract(ctx context.Context, accountID, contractID int64) error {
+	return m.contractRepo.Delete(ctx, accountID, contractID)
+}
+
+// GetContract 查询合同
+func (m *ContractModule) GetContract(ctx context.Context, accountID, contractID int64) (*etcontract.Contract, error) {
+	return m.contractRepo.GetByID(ctx, accountID, contractID)
+}
+
+// GetContractByCarrierService 根据承运商服务查询合同（检查重复）
+func (m *ContractModule) GetContractByCarrierService(ctx context.Context, accountID int64, carrier, service string) (*etcontract.Contract, error) {
+	return m.contractRepo.GetByCarrierService(ctx, accountID, carrier, service)
+}
+
+// ListContracts 查询账号全部合同
+func (m *ContractModule) ListContracts(ctx context.Context, accountID int64) ([]*etcontract.Contract, error) {
+	return m.contractRepo.ListByAccount(ctx, accountID)
+}
+
+// AccountExists 检查账号是否存在
+func (m *ContractModule) AccountExists(ctx context.Context, accountID int64) (bool, error) {
+	return m.accountRepo.Exists(ctx, accountID)
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	"oip/common/model"
 	"oip/dpmain/internal/app/domains/entity/etorder"
 	"oip/dpmain/internal/app/infra/mq/lmstfy"
@@ -35,31 +34,35 @@ func NewDiagnosisModule(lmstfyClient *lmstfy.Client, redisClient *redis.PubSubCl
 // PublishDiagnoseJob 发布订单诊断任务到队列
 // 业务逻辑：
 // 1. 构造标准化消息格式（包含 RequestID, ActionType, OrgID 等）
-// 2. 将 Shipment 转换为 map 格式（避免 dpsync 查询 DB）
-func (m *DiagnosisModule) PublishDiagnoseJob(ctx context.Context, order *etorder.Order) error {
-	// 业务逻辑：将 Shipment 结构体转换为 map
-	var shipmentMap map[string]interface{}
-	shipmentJSON, err := json.Marshal(order.Shipment)
-	if err != nil {
-		return fmt.Errorf("marshal shipment failed: %w", err)
-	}
-	if err := json.Unmarshal(shipmentJSON, &shipmentMap); err != nil {
-		return fmt.Errorf("unmarshal shipment to map failed: %w", err)
-	}
-
+// 2. 将 Shipment 转换为 common/model 标准结构（避免 dpsync 查询 DB）
+// RequestID 取订单记录的诊断任务 ID（下单、重新诊断时生成并先于发布落库）
+// explain 为 true 时 dpsync 在结果中附带各诊断器的执行轨迹（不属于诊断选项，不随订单保存）
+func (m *DiagnosisModule) PublishDiagnoseJob(ctx context.Context, order *etorder.Order, explain bool) error {
 	// 业务逻辑：构造标准化消息格式
 	message := model.OrderDiagnoseJob{
 		Payload: model.OrderDiagnosePayload{
 			Data: model.OrderDiagnoseData{
-				RequestID:  uuid.New().String(), // 生成请求 ID 用于全链路追踪
-				OrgID:      "0",                 // MVP 固定值
+				RequestID:  order.DiagnoseRequestID, // 订单记录的诊断任务 ID（全链路追踪，回调据此忽略被取代的任务）
+				OrgID:      "0",                     // MVP 固定值
 				ActionType: "order_diagnose",
 				ID:         order.ID,
 				Data: model.OrderDiagnoseBusinessData{
-					OrderID:         order.ID,
-					AccountID:       order.AccountID,
-					MerchantOrderNo: order.MerchantOrderNo,
-					Shipment:        shipmentMap, // 传递完整的 shipment 数据（map 格式）
+					OrderID:              order.ID,
+					AccountID:            order.AccountID,
+					MerchantOrderNo:      order.MerchantOrderNo,
+					Shipment:             order.Shipment.ToModel(), // 传递完整的 shipment 数据
+					Diagnosers:           order.DiagnoseOptions.Diagnosers,
+					PreferredCurrency:    order.DiagnoseOptions.PreferredCurrency,
+					Strategy:             order.DiagnoseOptions.Strategy,
+					Contracts:            order.DiagnoseOptions.Contracts,
+					OrderCreatedAt:       order.CreatedAt,
+					PromisedDeliveryDate: order.DiagnoseOptions.PromisedDeliveryDate,
+					Boxes:                order.DiagnoseOptions.Boxes,
+					Incoterm:             order.DiagnoseOptions.Incoterm,
+					Account:              order.DiagnoseOptions.Account,
+					Selected:             order.DiagnoseOptions.Selected,
+					Enrichments:          order.DiagnoseOptions.Enrichments,
+					Explain:              explain,
 				},
 			},
 		},
@@ -2,26 +2,38 @@ package mdorder
 
 import (
 	"context"
+	"time"
 
+	"oip/dpmain/internal/app/domains/entity/etaccount"
+	"oip/dpmain/internal/app/domains/entity/etcontract"
 	"oip/dpmain/internal/app/domains/entity/etorder"
+	"oip/dpmain/internal/app/domains/entity/etproduct"
 	"oip/dpmain/internal/app/domains/repo/rpaccount"
+	"oip/dpmain/internal/app/domains/repo/rpcontract"
 	"oip/dpmain/internal/app/domains/repo/rporder"
+	"oip/dpmain/internal/app/domains/repo/rpproduct"
 )
 
 // OrderModule 订单模块（业务编排层）
 type OrderModule struct {
-	orderRepo   rporder.OrderRepository
-	accountRepo rpaccount.AccountRepository
+	orderRepo    rporder.OrderRepository
+	accountRepo  rpaccount.AccountRepository
+	contractRepo rpcontract.ContractRepository
+	productRepo  rpproduct.ProductRepository
 }
 
 // NewOrderModule 创建订单模块
 func NewOrderModule(
 	orderRepo rporder.OrderRepository,
 	accountRepo rpaccount.AccountRepository,
+	contractRepo rpcontract.ContractRepository,
+	productRepo rpproduct.ProductRepository,
 ) *OrderModule {
 	return &OrderModule{
-		orderRepo:   orderRepo,
-		accountRepo: accountRepo,
+		orderRepo:    orderRepo,
+		accountRepo:  accountRepo,
+		contractRepo: contractRepo,
+		productRepo:  productRepo,
 	}
 }
 
@@ -40,9 +52,29 @@ func (m *OrderModule) GetOrderByAccountAndMerchantNo(ctx context.Context, accoun
 	return m.orderRepo.GetByAccountAndMerchantNo(ctx, accountID, merchantOrderNo)
 }
 
-// UpdateDiagnoseResult 更新诊断结果
-func (m *OrderModule) UpdateDiagnoseResult(ctx context.Context, orderID string, result *etorder.DiagnoseResult) error {
-	return m.orderRepo.UpdateDiagnoseResult(ctx, orderID, result)
+// RevertDiagnosis 诊断任务未能下发时恢复订单原来的状态与诊断任务
+func (m *OrderModule) RevertDiagnosis(ctx context.Context, orderID string, requestID string, previous *etorder.Order) error {
+	return m.orderRepo.RevertDiagnosis(ctx, orderID, requestID, previous)
+}
+
+// UpdateDiagnoseResult 更新诊断结果（订单已重新下发诊断任务时不更新，返回 false）
+func (m *OrderModule) UpdateDiagnoseResult(ctx context.Context, orderID string, requestID string, result *etorder.DiagnoseResult) (bool, error) {
+	return m.orderRepo.UpdateDiagnoseResult(ctx, orderID, requestID, result)
+}
+
+// UpdateStatus 更新订单状态
+func (m *OrderModule) UpdateStatus(ctx context.Context, orderID string, status etorder.OrderStatus) error {
+	return m.orderRepo.UpdateStatus(ctx, orderID, status)
+}
+
+// StartDiagnosis 将订单置为 DIAGNOSING 并记录新的诊断任务（订单正在诊断时返回 false）
+func (m *OrderModule) StartDiagnosis(ctx context.Context, orderID string, requestID string, staleBefore time.Time) (bool, error) {
+	return m.orderRepo.StartDiagnosis(ctx, orderID, requestID, staleBefore)
+}
+
+// ListDiagnoses 查询订单的诊断版本历史（按版本号升序）
+func (m *OrderModule) ListDiagnoses(ctx context.Context, orderID string) ([]*etorder.DiagnosisVersion, error) {
+	return m.orderRepo.ListDiagnoses(ctx, orderID)
 }
 
 // ListOrders 查询订单列表
@@ -50,6 +82,35 @@ func (m *OrderModule) ListOrders(ctx context.Context, accountID int64, page, lim
 	return m.orderRepo.List(ctx, accountID, page, limit)
 }
 
+// CountOrders 统计账号下的订单数（下单前调用即为历史订单数）
+func (m *OrderModule) CountOrders(ctx context.Context, accountID int64) (int64, error) {
+	return m.orderRepo.CountByAccount(ctx, accountID)
+}
+
+// ScanDiagnoseResults 分批读取账号在时间范围内已完成诊断订单的诊断结果（用于账号级报告）
+func (m *OrderModule) ScanDiagnoseResults(ctx context.Context, accountID int64, from, to time.Time, fn func(*etorder.DiagnoseResult) error) error {
+	return m.orderRepo.ScanDiagnoseResults(ctx, accountID, from, to, fn)
+}
+
+// GetAccount 查询账号（读取账号设置）
+func (m *OrderModule) GetAccount(ctx context.Context, accountID int64) (*etaccount.Account, error) {
+	return m.accountRepo.GetByID(ctx, accountID)
+}
+
+// ListContracts 查询账号协议价（下单时快照到诊断选项）
+func (m *OrderModule) ListContracts(ctx context.Context, accountID int64) ([]*etcontract.Contract, error) {
+	return m.contractRepo.ListByAccount(ctx, accountID)
+}
+
+// LoadCatalog 加载货件商品对应的账号目录商品（按 SKU）
+func (m *OrderModule) LoadCatalog(ctx context.Context, accountID int64, shipment *etorder.Shipment) (*etproduct.Catalog, error) {
+	products, err := m.productRepo.ListBySKUs(ctx, accountID, etproduct.LookupSKUs(shipment))
+	if err != nil {
+		return nil, err
+	}
+	return etproduct.NewCatalog(products), nil
+}
+
 // AccountExists 检查账号是否存在
 func (m *OrderModule) AccountExists(ctx context.Context, accountID int64) (bool, error) {
 	return m.accountRepo.Exists(ctx, accountID)
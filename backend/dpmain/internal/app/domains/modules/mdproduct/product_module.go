@@ -0,0 +1,68 @@
+package mdproduct
+
+import (
+	"context"
+
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+	"oip/dpmain/internal/app/domains/repo/rpaccount"
+	"oip/dpmain/internal/app/domains/repo/rpproduct"
+)
+
+// ProductModule 商品目录模块
+type ProductModule struct {
+	productRepo rpproduct.ProductRepository
+	accountRepo rpaccount.AccountRepository
+}
+
+// NewProductModule 创建商品目录模块
+func NewProductModule(productRepo rpproduct.ProductRepository, accountRepo rpaccount.AccountRepository) *ProductModule {
+	return &ProductModule{
+		productRepo: productRepo,
+		accountRepo: accountRepo,
+	}
+}
+
+// CreateProduct 创建商品（数据操作）
+func (m *ProductModule) CreateProduct(ctx context.Context, product *etproduct.Product) error {
+	return m.productRepo.Create(ctx, product)
+}
+
+// UpdateProduct 更新商品
+func (m *ProductModule) UpdateProduct(ctx context.Context, product *etproduct.Product) error {
+	return m.productRepo.Update(ctx, product)
+}
+
+// DeleteProduct 删除商品
+func (m *ProductModule) DeleteProduct(ctx context.Context, accountID, productID int64) error {
+	return m.productRepo.Delete(ctx, accountID, productID)
+}
+
+// GetProduct 查询商品
+func (m *ProductModule) GetProduct(ctx context.Context, accountID, productID int64) (*etproduct.Product, error) {
+	return m.productRepo.GetByID(ctx, accountID, productID)
+}
+
+// GetProductBySKU 根据 SKU 查询商品（检查重复）
+func (m *ProductModule) GetProductBySKU(ctx context.Context, accountID int64, sku string) (*etproduct.Product, error) {
+	return m.productRepo.GetBySKU(ctx, accountID, sku)
+}
+
+// ListProducts 分页查询账号商品
+func (m *ProductModule) ListProducts(ctx context.Context, accountID int64, page, limit int) ([]*etproduct.Product, int64, error) {
+	return m.productRepo.List(ctx, accountID, page, limit)
+}
+
+// ListProductsBySKUs 查询账号内指定 SKU 的商品
+func (m *ProductModule) ListProductsBySKUs(ctx context.Context, accountID int64, skus []string) ([]*etproduct.Product, error) {
+	return m.productRepo.ListBySKUs(ctx, accountID, skus)
+}
+
+// SaveProducts 在同一事务内创建与更新商品
+func (m *ProductModule) SaveProducts(ctx context.Context, created, updated []*etproduct.Product) error {
+	return m.productRepo.SaveAll(ctx, created, updated)
+}
+
+// AccountExists 检查账号是否存在
+func (m *ProductModule) AccountExists(ctx context.Context, accountID int64) (bool, error) {
+	return m.accountRepo.Exists(ctx, accountID)
+}
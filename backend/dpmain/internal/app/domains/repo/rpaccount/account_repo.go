@@ -19,4 +19,7 @@ type AccountRepository interface {
 
 	// Exists 检查账号是否存在
 	Exists(ctx context.Context, accountID int64) (bool, error)
+
+	// UpdateSettings 更新账号设置
+	UpdateSettings(ctx context.Context, accountID int64, settings *etaccount.Settings) error
 }
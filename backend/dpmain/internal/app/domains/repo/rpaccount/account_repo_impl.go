@@ -2,6 +2,7 @@ package rpaccount
 
 import (
 	"context"
+	"encoding/json"
 
 	"gorm.io/gorm"
 	"oip/common/entity"
@@ -20,10 +21,16 @@ func NewAccountRepository(db *gorm.DB) AccountRepository {
 
 // Create 创建账号
 func (r *AccountRepositoryImpl) Create(ctx context.Context, account *etaccount.Account) error {
+	settingsJSON, err := json.Marshal(account.Settings)
+	if err != nil {
+		return err
+	}
+
 	po := &entity.Account{
-		ID:    account.ID,
-		Name:  account.Name,
-		Email: account.Email,
+		ID:       account.ID,
+		Name:     account.Name,
+		Email:    account.Email,
+		Settings: settingsJSON,
 	}
 	if err := r.db.WithContext(ctx).Create(po).Error; err != nil {
 		return err
@@ -42,7 +49,7 @@ func (r *AccountRepositoryImpl) GetByID(ctx context.Context, accountID int64) (*
 	}
 
 	// 转换为领域对象
-	return etaccount.NewAccount(po.ID, po.Name, po.Email)
+	return r.toDomainModel(&po)
 }
 
 // GetByEmail 根据邮箱查询账号（用于检查重复）
@@ -55,7 +62,7 @@ func (r *AccountRepositoryImpl) GetByEmail(ctx context.Context, email string) (*
 		}
 		return nil, err
 	}
-	return etaccount.NewAccount(po.ID, po.Name, po.Email)
+	return r.toDomainModel(&po)
 }
 
 // Exists 检查账号是否存在
@@ -64,3 +71,34 @@ func (r *AccountRepositoryImpl) Exists(ctx context.Context, accountID int64) (bo
 	err := r.db.WithContext(ctx).Model(&entity.Account{}).Where("id = ?", accountID).Count(&count).Error
 	return count > 0, err
 }
+
+// UpdateSettings 更新账号设置
+func (r *AccountRepositoryImpl) UpdateSettings(ctx context.Context, accountID int64, settings *etaccount.Settings) error {
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Model(&entity.Account{}).
+		Where("id = ?", accountID).
+		Update("settings", settingsJSON).Error
+}
+
+// toDomainModel GORM 模型转换为领域对象
+func (r *AccountRepositoryImpl) toDomainModel(po *entity.Account) (*etaccount.Account, error) {
+	account, err := etaccount.NewAccount(po.ID, po.Name, po.Email)
+	if err != nil {
+		return nil, err
+	}
+	account.CreatedAt = po.CreatedAt
+
+	if len(po.Settings) > 0 {
+		var settings etaccount.Settings
+		if err := json.Unmarshal(po.Settings, &settings); err != nil {
+			return nil, err
+		}
+		account.UpdateSettings(&settings)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,28 @@
+package rpcontract
+
+import (
+	"context"
+
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+)
+
+// ContractRepository 协议价仓储接口
+type ContractRepository interface {
+	// Create 创建合同
+	Create(ctx context.Context, contract *etcontract.Contract) error
+
+	// Update 更新合同条款
+	Update(ctx context.Context, contract *etcontract.Contract) error
+
+	// Delete 删除合同
+	Delete(ctx context.Context, accountID, contractID int64) error
+
+	// GetByID 根据ID查询合同（不存在时返回 nil, nil）
+	GetByID(ctx context.Context, accountID, contractID int64) (*etcontract.Contract, error)
+
+	// GetByCarrierService 根据承运商服务查询合同（用于检查重复，不存在时返回 nil, nil）
+	GetByCarrierService(ctx context.Context, accountID int64, carrier, service string) (*etcontract.Contract, error)
+
+	// ListByAccount 查询账号全部合同
+	ListByAccount(ctx context.Context, accountID int64) ([]*etcontract.Contract, error)
+}
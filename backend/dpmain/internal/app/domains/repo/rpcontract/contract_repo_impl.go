@@ -0,0 +1,146 @@
+package rpcontract
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"oip/common/entity"
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+)
+
+// ContractRepositoryImpl 协议价仓储实现（MySQL）
+type ContractRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewContractRepository 创建协议价仓储实例
+func NewContractRepository(db *gorm.DB) ContractRepository {
+	return &ContractRepositoryImpl{db: db}
+}
+
+// Create 创建合同
+func (r *ContractRepositoryImpl) Create(ctx context.Context, contract *etcontract.Contract) error {
+	return r.db.WithContext(ctx).Create(r.toPO(contract)).Error
+}
+
+// Update 更新合同条款
+func (r *ContractRepositoryImpl) Update(ctx context.Context, contract *etcontract.Contract) error {
+	po := r.toPO(contract)
+	result := r.db.WithContext(ctx).
+		Model(&entity.RateContract{}).
+		Where("id = ? AND account_id = ?", contract.ID, contract.AccountID).
+		Select("carrier", "service", "discount_percent", "fixed_fee_amount", "fixed_fee_currency", "fuel_surcharge_percent", "updated_at").
+		Updates(po)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etcontract.ErrContractNotFound
+	}
+	return nil
+}
+
+// Delete 删除合同
+func (r *ContractRepositoryImpl) Delete(ctx context.Context, accountID, contractID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND account_id = ?", contractID, accountID).
+		Delete(&entity.RateContract{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etcontract.ErrContractNotFound
+	}
+	return nil
+}
+
+// GetByID 根据ID查询合同
+func (r *ContractRepositoryImpl) GetByID(ctx context.Context, accountID, contractID int64) (*etcontract.Contract, error) {
+	var po entity.RateContract
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND account_id = ?", contractID, accountID).
+		First(&po).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.toDomainModel(&po), nil
+}
+
+// GetByCarrierService 根据承运商服务查询合同
+func (r *ContractRepositoryImpl) GetByCarrierService(ctx context.Context, accountID int64, carrier, service string) (*etcontract.Contract, error) {
+	var po entity.RateContract
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND carrier = ? AND service = ?", accountID, carrier, service).
+		First(&po).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.toDomainModel(&po), nil
+}
+
+// ListByAccount 查询账号全部合同（按承运商、服务排序）
+func (r *ContractRepositoryImpl) ListByAccount(ctx context.Context, accountID int64) ([]*etcontract.Contract, error) {
+	var pos []entity.RateContract
+	err := r.db.WithContext(ctx).
+		Where("account_id = ?", accountID).
+		Order("carrier, service").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make([]*etcontract.Contract, 0, len(pos))
+	for i := range pos {
+		contracts = append(contracts, r.toDomainModel(&pos[i]))
+	}
+	return contracts, nil
+}
+
+// toPO 领域对象转换为 GORM 模型
+func (r *ContractRepositoryImpl) toPO(contract *etcontract.Contract) *entity.RateContract {
+	po := &entity.RateContract{
+		ID:                   contract.ID,
+		AccountID:            contract.AccountID,
+		Carrier:              contract.Carrier,
+		Service:              contract.Service,
+		DiscountPercent:      contract.DiscountPercent,
+		FuelSurchargePercent: contract.FuelSurchargePercent,
+		CreatedAt:            contract.CreatedAt,
+		UpdatedAt:            contract.UpdatedAt,
+	}
+	if contract.FixedFee != nil {
+		amount := contract.FixedFee.Amount
+		po.FixedFeeAmount = &amount
+		po.FixedFeeCurrency = contract.FixedFee.Currency
+	}
+	return po
+}
+
+// toDomainModel GORM 模型转换为领域对象
+func (r *ContractRepositoryImpl) toDomainModel(po *entity.RateContract) *etcontract.Contract {
+	contract := &etcontract.Contract{
+		ID:                   po.ID,
+		AccountID:            po.AccountID,
+		Carrier:              po.Carrier,
+		Service:              po.Service,
+		DiscountPercent:      po.DiscountPercent,
+		FuelSurchargePercent: po.FuelSurchargePercent,
+		CreatedAt:            po.CreatedAt,
+		UpdatedAt:            po.UpdatedAt,
+	}
+	if po.FixedFeeAmount != nil {
+		contract.FixedFee = &model.Money{
+			Amount:   *po.FixedFeeAmount,
+			Currency: po.FixedFeeCurrency,
+		}
+	}
+	return contract
+}
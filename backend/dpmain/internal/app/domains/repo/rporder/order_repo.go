@@ -2,6 +2,7 @@ package rporder
 
 import (
 	"context"
+	"time"
 
 	"oip/common/model"
 	"oip/dpmain/internal/app/domains/entity/etorder"
@@ -20,17 +21,41 @@ type OrderRepository interface {
 	GetByAccountAndMerchantNo(ctx context.Context, accountID int64, merchantOrderNo string) (*etorder.Order, error)
 
 	// UpdateDiagnoseResult 更新诊断结果（旧方法，保持兼容）
-	UpdateDiagnoseResult(ctx context.Context, orderID string, result *etorder.DiagnoseResult) error
+	// 仅当订单最近一次下发的诊断任务仍为 requestID 时写入，返回是否写入
+	UpdateDiagnoseResult(ctx context.Context, orderID string, requestID string, result *etorder.DiagnoseResult) (bool, error)
 
 	// UpdateStatus 更新订单状态
 	UpdateStatus(ctx context.Context, orderID string, status etorder.OrderStatus) error
 
+	// StartDiagnosis 将订单置为 DIAGNOSING 并记录新的诊断任务 requestID
+	// 订单正在诊断（且 updated_at 不早于 staleBefore）时不更新，返回 false
+	StartDiagnosis(ctx context.Context, orderID string, requestID string, staleBefore time.Time) (bool, error)
+
+	// RevertDiagnosis 撤销 StartDiagnosis：诊断任务 requestID 未能下发时恢复订单原来的状态、诊断任务与更新时间
+	// 订单记录的诊断任务已不是 requestID 时不更新
+	RevertDiagnosis(ctx context.Context, orderID string, requestID string, previous *etorder.Order) error
+
 	// UpdateDiagnosisResult 更新诊断结果（新方法，支持成功/失败两种情况）
+	// requestID: 回调对应的诊断任务（不是订单最近一次下发的任务时不更新，返回 false）
 	// diagnosisResult: 诊断结果（成功时传入，失败时传 nil）
 	// status: 订单状态（DIAGNOSED 或 FAILED）
 	// errorMsg: 错误信息（失败时传入）
-	UpdateDiagnosisResult(ctx context.Context, orderID string, diagnosisResult *model.DiagnosisResultData, status string, errorMsg string) error
+	UpdateDiagnosisResult(ctx context.Context, orderID string, requestID string, diagnosisResult *model.DiagnosisResultData, status string, errorMsg string) (bool, error)
+
+	// AppendDiagnosis 追加一条诊断版本记录（版本号按订单递增）
+	// 同一 requestID 已记录时直接返回（回调重复投递幂等）
+	AppendDiagnosis(ctx context.Context, orderID string, requestID string, diagnosisResult *model.DiagnosisResultData, status string, errorMsg string) error
+
+	// ListDiagnoses 查询订单的全部诊断版本（按版本号升序）
+	ListDiagnoses(ctx context.Context, orderID string) ([]*etorder.DiagnosisVersion, error)
 
 	// List 查询订单列表
 	List(ctx context.Context, accountID int64, page, limit int) ([]*etorder.Order, int64, error)
+
+	// CountByAccount 统计账号下的订单数
+	CountByAccount(ctx context.Context, accountID int64) (int64, error)
+
+	// ScanDiagnoseResults 分批读取账号在时间范围内（created_at ∈ [from, to)）已完成诊断订单的诊断结果
+	// 只读取 diagnose_result 列，逐个交给 fn 处理；fn 返回错误时停止读取并返回该错误
+	ScanDiagnoseResults(ctx context.Context, accountID int64, from, to time.Time, fn func(*etorder.DiagnoseResult) error) error
 }
@@ -13,6 +13,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// latestRequest 限定诊断结果所属的任务是订单最近一次下发的诊断任务
+// diagnose_request_id 为空的存量订单（加列前下发的任务）不做限制
+const latestRequest = "(diagnose_request_id = ? OR diagnose_request_id = '')"
+
+// diagnoseResultBatchSize 分批读取诊断结果时每批的订单数
+const diagnoseResultBatchSize = 500
+
 // OrderRepositoryImpl 订单仓储实现（MySQL）
 type OrderRepositoryImpl struct {
 	db *gorm.DB
@@ -32,11 +39,14 @@ func (r *OrderRepositoryImpl) Create(ctx context.Context, order *etorder.Order)
 	return r.db.WithContext(ctx).Create(po).Error
 }
 
-// GetByID 根据ID查询订单，将 GORM 模型转换为领域对象
+// GetByID 根据ID查询订单，将 GORM 模型转换为领域对象（不存在时返回 etorder.ErrOrderNotFound）
 func (r *OrderRepositoryImpl) GetByID(ctx context.Context, orderID string) (*etorder.Order, error) {
 	var po entity.Order
 	err := r.db.WithContext(ctx).Where("id = ?", orderID).First(&po).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, etorder.ErrOrderNotFound
+		}
 		return nil, err
 	}
 	return r.toDomainModel(&po)
@@ -57,20 +67,29 @@ func (r *OrderRepositoryImpl) GetByAccountAndMerchantNo(ctx context.Context, acc
 	return r.toDomainModel(&po)
 }
 
-// UpdateDiagnoseResult 更新订单的诊断结果
-func (r *OrderRepositoryImpl) UpdateDiagnoseResult(ctx context.Context, orderID string, result *etorder.DiagnoseResult) error {
-	resultJSON, err := json.Marshal(result)
+// UpdateDiagnoseResult 更新订单的诊断结果（执行轨迹单独写入 diagnose_trace 列）
+// 订单已重新下发诊断任务时不更新，避免旧任务的结果覆盖新任务
+func (r *OrderRepositoryImpl) UpdateDiagnoseResult(ctx context.Context, orderID string, requestID string, result *etorder.DiagnoseResult) (bool, error) {
+	stripped := *result
+	stripped.Traces = nil
+	resultJSON, err := json.Marshal(&stripped)
 	if err != nil {
-		return err
+		return false, err
 	}
-	return r.db.WithContext(ctx).
+	traceJSON, err := traceColumn(result.Traces)
+	if err != nil {
+		return false, err
+	}
+	tx := r.db.WithContext(ctx).
 		Model(&entity.Order{}).
-		Where("id = ?", orderID).
+		Where("id = ? AND "+latestRequest, orderID, requestID).
 		Updates(map[string]interface{}{
 			"diagnose_result": resultJSON,
+			"diagnose_trace":  traceJSON,
 			"status":          string(etorder.OrderStatusDiagnosed),
 			"updated_at":      time.Now(),
-		}).Error
+		})
+	return r.latestRequestApplied(ctx, tx, orderID, requestID)
 }
 
 // UpdateStatus 更新订单状态
@@ -84,20 +103,54 @@ func (r *OrderRepositoryImpl) UpdateStatus(ctx context.Context, orderID string,
 		}).Error
 }
 
+// StartDiagnosis 将订单置为 DIAGNOSING 并记录新的诊断任务（条件更新，并发重新诊断只有一个成功）
+func (r *OrderRepositoryImpl) StartDiagnosis(ctx context.Context, orderID string, requestID string, staleBefore time.Time) (bool, error) {
+	tx := r.db.WithContext(ctx).
+		Model(&entity.Order{}).
+		Where("id = ? AND (status <> ? OR updated_at < ?)", orderID, string(etorder.OrderStatusDiagnosing), staleBefore).
+		Updates(map[string]interface{}{
+			"status":              string(etorder.OrderStatusDiagnosing),
+			"diagnose_request_id": requestID,
+			"updated_at":          time.Now(),
+		})
+	return tx.RowsAffected > 0, tx.Error
+}
+
+// RevertDiagnosis 恢复订单在 StartDiagnosis 之前的状态、诊断任务与更新时间
+// 恢复更新时间使任务丢失的订单仍按原时间判断是否超时，不会因一次发布失败被再次锁定
+func (r *OrderRepositoryImpl) RevertDiagnosis(ctx context.Context, orderID string, requestID string, previous *etorder.Order) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Order{}).
+		Where("id = ? AND diagnose_request_id = ?", orderID, requestID).
+		Updates(map[string]interface{}{
+			"status":              string(previous.Status),
+			"diagnose_request_id": previous.DiagnoseRequestID,
+			"updated_at":          previous.UpdatedAt,
+		}).Error
+}
+
 // UpdateDiagnosisResult 更新诊断结果（新方法，支持成功/失败两种情况）
-func (r *OrderRepositoryImpl) UpdateDiagnosisResult(ctx context.Context, orderID string, diagnosisResult *model.DiagnosisResultData, status string, errorMsg string) error {
+// 回调的 requestID 不是订单最近一次下发的诊断任务时不更新（被重新诊断取代的旧任务）
+func (r *OrderRepositoryImpl) UpdateDiagnosisResult(ctx context.Context, orderID string, requestID string, diagnosisResult *model.DiagnosisResultData, status string, errorMsg string) (bool, error) {
 	updates := map[string]interface{}{
 		"status":     status,
 		"updated_at": time.Now(),
 	}
 
-	// 成功时保存诊断结果
+	// 成功时保存诊断结果（执行轨迹单独写入 diagnose_trace 列）
 	if diagnosisResult != nil {
-		resultJSON, err := json.Marshal(diagnosisResult)
+		stripped := *diagnosisResult
+		stripped.Traces = nil
+		resultJSON, err := json.Marshal(&stripped)
 		if err != nil {
-			return err
+			return false, err
+		}
+		traceJSON, err := traceColumn(diagnosisResult.Traces)
+		if err != nil {
+			return false, err
 		}
 		updates["diagnose_result"] = resultJSON
+		updates["diagnose_trace"] = traceJSON
 	}
 
 	// 失败时保存错误信息（TBC: 当前 entity.Order 没有 error_message 字段）
@@ -106,10 +159,110 @@ func (r *OrderRepositoryImpl) UpdateDiagnosisResult(ctx context.Context, orderID
 	//     updates["error_message"] = errorMsg
 	// }
 
-	return r.db.WithContext(ctx).
+	tx := r.db.WithContext(ctx).
 		Model(&entity.Order{}).
-		Where("id = ?", orderID).
-		Updates(updates).Error
+		Where("id = ? AND "+latestRequest, orderID, requestID).
+		Updates(updates)
+	return r.latestRequestApplied(ctx, tx, orderID, requestID)
+}
+
+// latestRequestApplied 按 latestRequest 条件更新后判断是否写入
+// MySQL 的影响行数只统计实际变化的行，同一结果重复写入（回调重试、Smart Wait 写回）时为 0，需再确认条件是否命中
+func (r *OrderRepositoryImpl) latestRequestApplied(ctx context.Context, tx *gorm.DB, orderID string, requestID string) (bool, error) {
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	if tx.RowsAffected > 0 {
+		return true, nil
+	}
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.Order{}).
+		Where("id = ? AND "+latestRequest, orderID, requestID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// AppendDiagnosis 追加一条诊断版本记录
+// 版本号取订单当前最大版本 + 1，并发追加时由 (order_id, version) 唯一索引拒绝后写入者，返回错误由回调重试
+func (r *OrderRepositoryImpl) AppendDiagnosis(ctx context.Context, orderID string, requestID string, diagnosisResult *model.DiagnosisResultData, status string, errorMsg string) error {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.OrderDiagnosis{}).
+		Where("order_id = ? AND request_id = ?", orderID, requestID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var latest int
+	err = r.db.WithContext(ctx).
+		Model(&entity.OrderDiagnosis{}).
+		Where("order_id = ?", orderID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	po := &entity.OrderDiagnosis{
+		OrderID:      orderID,
+		Version:      latest + 1,
+		RequestID:    requestID,
+		Status:       status,
+		ErrorMessage: errorMsg,
+		CreatedAt:    time.Now(),
+	}
+
+	// 诊断结果不含执行轨迹（轨迹只保留在订单上的最近一次 explain 诊断）
+	if diagnosisResult != nil {
+		stripped := *diagnosisResult
+		stripped.Traces = nil
+		resultJSON, err := json.Marshal(&stripped)
+		if err != nil {
+			return err
+		}
+		versions := make(map[string]string, len(diagnosisResult.Items))
+		for _, item := range diagnosisResult.Items {
+			if item.Version != "" {
+				versions[item.Type] = item.Version
+			}
+		}
+		versionsJSON, err := json.Marshal(versions)
+		if err != nil {
+			return err
+		}
+		po.EngineVersion = diagnosisResult.EngineVersion
+		po.DiagnoseResult = resultJSON
+		po.DiagnoserVersions = versionsJSON
+	}
+
+	return r.db.WithContext(ctx).Create(po).Error
+}
+
+// ListDiagnoses 查询订单的全部诊断版本（按版本号升序）
+func (r *OrderRepositoryImpl) ListDiagnoses(ctx context.Context, orderID string) ([]*etorder.DiagnosisVersion, error) {
+	var pos []entity.OrderDiagnosis
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("version ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*etorder.DiagnosisVersion, 0, len(pos))
+	for i := range pos {
+		version, err := toDiagnosisVersion(&pos[i])
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
 }
 
 // List 分页查询订单列表
@@ -143,6 +296,36 @@ func (r *OrderRepositoryImpl) List(ctx context.Context, accountID int64, page, l
 	return orders, total, nil
 }
 
+// CountByAccount 统计账号下的订单数
+func (r *OrderRepositoryImpl) CountByAccount(ctx context.Context, accountID int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Order{}).Where("account_id = ?", accountID).Count(&count).Error
+	return count, err
+}
+
+// ScanDiagnoseResults 分批读取已完成诊断订单的诊断结果（按主键分批，每批 diagnoseResultBatchSize 条）
+func (r *OrderRepositoryImpl) ScanDiagnoseResults(ctx context.Context, accountID int64, from, to time.Time, fn func(*etorder.DiagnoseResult) error) error {
+	var pos []entity.Order
+	return r.db.WithContext(ctx).
+		Select("id", "diagnose_result").
+		Where("account_id = ? AND status = ? AND created_at >= ? AND created_at < ?", accountID, string(etorder.OrderStatusDiagnosed), from, to).
+		FindInBatches(&pos, diagnoseResultBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range pos {
+				if len(pos[i].DiagnoseResult) == 0 {
+					continue
+				}
+				var result etorder.DiagnoseResult
+				if err := json.Unmarshal(pos[i].DiagnoseResult, &result); err != nil {
+					return err
+				}
+				if err := fn(&result); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
 // toGormModel 领域对象转换为 GORM 模型
 func (r *OrderRepositoryImpl) toGormModel(order *etorder.Order) (*entity.Order, error) {
 	shipmentJSON, err := json.Marshal(order.Shipment)
@@ -150,27 +333,83 @@ func (r *OrderRepositoryImpl) toGormModel(order *etorder.Order) (*entity.Order,
 		return nil, err
 	}
 
+	optionsJSON, err := json.Marshal(order.DiagnoseOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	po := &entity.Order{
-		ID:              order.ID,
-		AccountID:       order.AccountID,
-		MerchantOrderNo: order.MerchantOrderNo,
-		RawData:         shipmentJSON,
-		Status:          string(order.Status),
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
+		ID:                order.ID,
+		AccountID:         order.AccountID,
+		MerchantOrderNo:   order.MerchantOrderNo,
+		RawData:           shipmentJSON,
+		DiagnoseOptions:   optionsJSON,
+		Status:            string(order.Status),
+		DiagnoseRequestID: order.DiagnoseRequestID,
+		CreatedAt:         order.CreatedAt,
+		UpdatedAt:         order.UpdatedAt,
 	}
 
 	if order.DiagnoseResult != nil {
-		resultJSON, err := json.Marshal(order.DiagnoseResult)
+		stripped := *order.DiagnoseResult
+		stripped.Traces = nil
+		resultJSON, err := json.Marshal(&stripped)
 		if err != nil {
 			return nil, err
 		}
 		po.DiagnoseResult = resultJSON
+
+		if len(order.DiagnoseResult.Traces) > 0 {
+			traceJSON, err := json.Marshal(order.DiagnoseResult.Traces)
+			if err != nil {
+				return nil, err
+			}
+			po.DiagnoseTrace = traceJSON
+		}
 	}
 
 	return po, nil
 }
 
+// traceColumn diagnose_trace 列的更新值
+// 非 explain 诊断没有轨迹，写入 NULL 清除上一次 explain 诊断留下的轨迹（避免轨迹与结果不对应）
+func traceColumn(traces []model.DiagnoserTrace) (interface{}, error) {
+	if len(traces) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(traces)
+}
+
+// toDiagnosisVersion 诊断版本 GORM 模型转换为领域对象
+func toDiagnosisVersion(po *entity.OrderDiagnosis) (*etorder.DiagnosisVersion, error) {
+	version := &etorder.DiagnosisVersion{
+		OrderID:           po.OrderID,
+		Version:           po.Version,
+		RequestID:         po.RequestID,
+		Status:            etorder.OrderStatus(po.Status),
+		EngineVersion:     po.EngineVersion,
+		DiagnoserVersions: make(map[string]string),
+		Error:             po.ErrorMessage,
+		CreatedAt:         po.CreatedAt,
+	}
+
+	if len(po.DiagnoserVersions) > 0 {
+		if err := json.Unmarshal(po.DiagnoserVersions, &version.DiagnoserVersions); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(po.DiagnoseResult) > 0 {
+		var result etorder.DiagnoseResult
+		if err := json.Unmarshal(po.DiagnoseResult, &result); err != nil {
+			return nil, err
+		}
+		version.Result = &result
+	}
+
+	return version, nil
+}
+
 // toDomainModel GORM 模型转换为领域对象
 func (r *OrderRepositoryImpl) toDomainModel(po *entity.Order) (*etorder.Order, error) {
 	var shipment etorder.Shipment
@@ -179,13 +418,22 @@ func (r *OrderRepositoryImpl) toDomainModel(po *entity.Order) (*etorder.Order, e
 	}
 
 	order := &etorder.Order{
-		ID:              po.ID,
-		AccountID:       po.AccountID,
-		MerchantOrderNo: po.MerchantOrderNo,
-		Shipment:        &shipment,
-		Status:          etorder.OrderStatus(po.Status),
-		CreatedAt:       po.CreatedAt,
-		UpdatedAt:       po.UpdatedAt,
+		ID:                po.ID,
+		AccountID:         po.AccountID,
+		MerchantOrderNo:   po.MerchantOrderNo,
+		Shipment:          &shipment,
+		Status:            etorder.OrderStatus(po.Status),
+		DiagnoseRequestID: po.DiagnoseRequestID,
+		CreatedAt:         po.CreatedAt,
+		UpdatedAt:         po.UpdatedAt,
+	}
+
+	if len(po.DiagnoseOptions) > 0 {
+		var options etorder.DiagnoseOptions
+		if err := json.Unmarshal(po.DiagnoseOptions, &options); err != nil {
+			return nil, err
+		}
+		order.SetDiagnoseOptions(&options)
 	}
 
 	if len(po.DiagnoseResult) > 0 {
@@ -193,6 +441,11 @@ func (r *OrderRepositoryImpl) toDomainModel(po *entity.Order) (*etorder.Order, e
 		if err := json.Unmarshal(po.DiagnoseResult, &result); err != nil {
 			return nil, err
 		}
+		if len(po.DiagnoseTrace) > 0 {
+			if err := json.Unmarshal(po.DiagnoseTrace, &result.Traces); err != nil {
+				return nil, err
+			}
+		}
 		order.DiagnoseResult = &result
 	}
 
@@ -0,0 +1,34 @@
+package rpproduct
+
+import (
+	"context"
+
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+)
+
+// ProductRepository 商品目录仓储接口
+type ProductRepository interface {
+	// Create 创建商品
+	Create(ctx context.Context, product *etproduct.Product) error
+
+	// Update 更新商品属性
+	Update(ctx context.Context, product *etproduct.Product) error
+
+	// Delete 删除商品
+	Delete(ctx context.Context, accountID, productID int64) error
+
+	// GetByID 根据ID查询商品（不存在时返回 nil, nil）
+	GetByID(ctx context.Context, accountID, productID int64) (*etproduct.Product, error)
+
+	// GetBySKU 根据 SKU 查询商品（用于检查重复，不存在时返回 nil, nil）
+	GetBySKU(ctx context.Context, accountID int64, sku string) (*etproduct.Product, error)
+
+	// List 分页查询账号商品（按 SKU 排序）
+	List(ctx context.Context, accountID int64, page, limit int) ([]*etproduct.Product, int64, error)
+
+	// ListBySKUs 查询账号内指定 SKU 的商品
+	ListBySKUs(ctx context.Context, accountID int64, skus []string) ([]*etproduct.Product, error)
+
+	// SaveAll 在同一事务内创建与更新商品（批量导入）
+	SaveAll(ctx context.Context, created, updated []*etproduct.Product) error
+}
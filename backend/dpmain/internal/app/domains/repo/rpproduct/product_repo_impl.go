@@ -0,0 +1,228 @@
+package rpproduct
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"oip/common/entity"
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+)
+
+// productColumns 更新商品时写入的列
+var productColumns = []string{
+	"sku", "description", "weight_value", "weight_unit",
+	"dimension_width", "dimension_height", "dimension_depth", "dimension_unit",
+	"hs_code", "origin_country", "battery_chemistry", "battery_packing", "updated_at",
+}
+
+// ProductRepositoryImpl 商品目录仓储实现（MySQL）
+type ProductRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewProductRepository 创建商品目录仓储实例
+func NewProductRepository(db *gorm.DB) ProductRepository {
+	return &ProductRepositoryImpl{db: db}
+}
+
+// Create 创建商品
+func (r *ProductRepositoryImpl) Create(ctx context.Context, product *etproduct.Product) error {
+	return r.db.WithContext(ctx).Create(r.toPO(product)).Error
+}
+
+// Update 更新商品属性
+func (r *ProductRepositoryImpl) Update(ctx context.Context, product *etproduct.Product) error {
+	return r.update(r.db.WithContext(ctx), product)
+}
+
+// Delete 删除商品
+func (r *ProductRepositoryImpl) Delete(ctx context.Context, accountID, productID int64) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND account_id = ?", productID, accountID).
+		Delete(&entity.Product{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etproduct.ErrProductNotFound
+	}
+	return nil
+}
+
+// GetByID 根据ID查询商品
+func (r *ProductRepositoryImpl) GetByID(ctx context.Context, accountID, productID int64) (*etproduct.Product, error) {
+	var po entity.Product
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND account_id = ?", productID, accountID).
+		First(&po).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.toDomainModel(&po), nil
+}
+
+// GetBySKU 根据 SKU 查询商品
+func (r *ProductRepositoryImpl) GetBySKU(ctx context.Context, accountID int64, sku string) (*etproduct.Product, error) {
+	var po entity.Product
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND sku = ?", accountID, sku).
+		First(&po).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.toDomainModel(&po), nil
+}
+
+// List 分页查询账号商品（按 SKU 排序）
+func (r *ProductRepositoryImpl) List(ctx context.Context, accountID int64, page, limit int) ([]*etproduct.Product, int64, error) {
+	var total int64
+	var pos []entity.Product
+
+	query := r.db.WithContext(ctx).Model(&entity.Product{}).Where("account_id = ?", accountID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("sku").Find(&pos).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return r.toDomainModels(pos), total, nil
+}
+
+// ListBySKUs 查询账号内指定 SKU 的商品
+func (r *ProductRepositoryImpl) ListBySKUs(ctx context.Context, accountID int64, skus []string) ([]*etproduct.Product, error) {
+	if len(skus) == 0 {
+		return []*etproduct.Product{}, nil
+	}
+
+	var pos []entity.Product
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND sku IN ?", accountID, skus).
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomainModels(pos), nil
+}
+
+// SaveAll 在同一事务内创建与更新商品（任一失败时整体回滚）
+func (r *ProductRepositoryImpl) SaveAll(ctx context.Context, created, updated []*etproduct.Product) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(created) > 0 {
+			pos := make([]*entity.Product, 0, len(created))
+			for _, product := range created {
+				pos = append(pos, r.toPO(product))
+			}
+			if err := tx.Create(pos).Error; err != nil {
+				return err
+			}
+		}
+		for _, product := range updated {
+			if err := r.update(tx, product); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// update 按账号与ID更新商品属性
+func (r *ProductRepositoryImpl) update(db *gorm.DB, product *etproduct.Product) error {
+	result := db.
+		Model(&entity.Product{}).
+		Where("id = ? AND account_id = ?", product.ID, product.AccountID).
+		Select(productColumns).
+		Updates(r.toPO(product))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return etproduct.ErrProductNotFound
+	}
+	return nil
+}
+
+// toPO 领域对象转换为 GORM 模型
+func (r *ProductRepositoryImpl) toPO(product *etproduct.Product) *entity.Product {
+	po := &entity.Product{
+		ID:            product.ID,
+		AccountID:     product.AccountID,
+		SKU:           product.SKU,
+		Description:   product.Description,
+		HSCode:        product.HSCode,
+		OriginCountry: product.OriginCountry,
+		CreatedAt:     product.CreatedAt,
+		UpdatedAt:     product.UpdatedAt,
+	}
+	if product.Weight != nil {
+		value := product.Weight.Value
+		po.WeightValue = &value
+		po.WeightUnit = product.Weight.Unit
+	}
+	if product.Dimension != nil {
+		width, height, depth := product.Dimension.Width, product.Dimension.Height, product.Dimension.Depth
+		po.DimensionWidth = &width
+		po.DimensionHeight = &height
+		po.DimensionDepth = &depth
+		po.DimensionUnit = product.Dimension.Unit
+	}
+	if product.Battery != nil {
+		po.BatteryChemistry = product.Battery.Chemistry
+		po.BatteryPacking = product.Battery.Packing
+	}
+	return po
+}
+
+// toDomainModel GORM 模型转换为领域对象
+func (r *ProductRepositoryImpl) toDomainModel(po *entity.Product) *etproduct.Product {
+	product := &etproduct.Product{
+		ID:            po.ID,
+		AccountID:     po.AccountID,
+		SKU:           po.SKU,
+		Description:   po.Description,
+		HSCode:        po.HSCode,
+		OriginCountry: po.OriginCountry,
+		CreatedAt:     po.CreatedAt,
+		UpdatedAt:     po.UpdatedAt,
+	}
+	if po.WeightValue != nil {
+		product.Weight = &model.Weight{
+			Value: *po.WeightValue,
+			Unit:  po.WeightUnit,
+		}
+	}
+	if po.DimensionWidth != nil && po.DimensionHeight != nil && po.DimensionDepth != nil {
+		product.Dimension = &model.Dimension{
+			Width:  *po.DimensionWidth,
+			Height: *po.DimensionHeight,
+			Depth:  *po.DimensionDepth,
+			Unit:   po.DimensionUnit,
+		}
+	}
+	if po.BatteryChemistry != "" {
+		product.Battery = &model.Battery{
+			Chemistry: po.BatteryChemistry,
+			Packing:   po.BatteryPacking,
+		}
+	}
+	return product
+}
+
+// toDomainModels 批量转换
+func (r *ProductRepositoryImpl) toDomainModels(pos []entity.Product) []*etproduct.Product {
+	products := make([]*etproduct.Product, 0, len(pos))
+	for i := range pos {
+		products = append(products, r.toDomainModel(&pos[i]))
+	}
+	return products
+}
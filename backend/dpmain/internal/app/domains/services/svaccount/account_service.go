@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"oip/common/model"
 	"oip/dpmain/internal/app/domains/entity/etaccount"
 	"oip/dpmain/internal/app/domains/modules/mdaccount"
 	"oip/dpmain/internal/app/pkg/idgen"
@@ -54,3 +55,34 @@ func (s *AccountService) CreateAccount(ctx context.Context, name, email string)
 func (s *AccountService) GetAccount(ctx context.Context, accountID int64) (*etaccount.Account, error) {
 	return s.accountModule.GetAccount(ctx, accountID)
 }
+
+// UpdateSettings 更新账号设置
+// 1. 校验诊断类型合法
+// 2. 更新领域对象并落库
+func (s *AccountService) UpdateSettings(ctx context.Context, accountID int64, settings *etaccount.Settings) (*etaccount.Account, error) {
+	if err := model.ValidateDiagnosisTypes(settings.Diagnosers); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateCurrencyCode(settings.PreferredCurrency); err != nil {
+		return nil, err
+	}
+	if err := settings.Strategy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateBoxes(settings.Boxes); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountModule.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get account failed: %w", err)
+	}
+
+	account.UpdateSettings(settings)
+
+	if err := s.accountModule.UpdateSettings(ctx, accountID, account.Settings); err != nil {
+		return nil, fmt.Errorf("save account settings failed: %w", err)
+	}
+
+	return account, nil
+}
@@ -16,7 +16,8 @@ import (
 // 职责：
 // 1. 处理 dpsync 发送的诊断回调
 // 2. 更新 DB 订单状态
-// 3. 发送 Redis PubSub 通知（Smart Wait）
+// 3. 追加诊断版本记录（诊断历史）
+// 4. 发送 Redis PubSub 通知（Smart Wait）
 type CallbackService struct {
 	orderRepo   rporder.OrderRepository
 	redisClient *redis.PubSubClient
@@ -45,16 +46,34 @@ func (s *CallbackService) HandleCallback(ctx context.Context, callback *model.Or
 		"request_id", callback.RequestID,
 	)
 
-	// 1. 根据回调状态更新 DB
-	if err := s.updateOrderStatus(ctx, callback); err != nil {
+	// 1. 根据回调状态更新 DB（只接受订单最近一次下发的诊断任务）
+	applied, err := s.updateOrderStatus(ctx, callback)
+	if err != nil {
 		s.logger.ErrorContext(ctx, "Failed to update order status",
 			"order_id", callback.OrderID,
 			"error", err,
 		)
 		return fmt.Errorf("update order status failed: %w", err)
 	}
+	if !applied {
+		// 订单已重新诊断，本次回调属于被取代的旧任务：不记录诊断版本，也不通知 Smart Wait
+		s.logger.InfoContext(ctx, "Ignoring callback of superseded diagnosis",
+			"order_id", callback.OrderID,
+			"request_id", callback.RequestID,
+		)
+		return nil
+	}
 
-	// 2. 发送 Redis PubSub 通知（用于 Smart Wait）
+	// 2. 追加诊断版本记录（按 request_id 幂等，重试时不会重复记录）
+	if err := s.appendDiagnosis(ctx, callback); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to append diagnosis version",
+			"order_id", callback.OrderID,
+			"error", err,
+		)
+		return fmt.Errorf("append diagnosis version failed: %w", err)
+	}
+
+	// 3. 发送 Redis PubSub 通知（用于 Smart Wait）
 	if err := s.publishNotification(ctx, callback); err != nil {
 		// 通知失败不影响整体流程（DB 已更新成功）
 		// 只记录日志，不返回错误
@@ -72,12 +91,14 @@ func (s *CallbackService) HandleCallback(ctx context.Context, callback *model.Or
 }
 
 // updateOrderStatus 根据回调状态更新订单
-func (s *CallbackService) updateOrderStatus(ctx context.Context, callback *model.OrderDiagnoseCallback) error {
+// 回调的 request_id 不是订单最近一次下发的诊断任务时不更新，返回 false
+func (s *CallbackService) updateOrderStatus(ctx context.Context, callback *model.OrderDiagnoseCallback) (bool, error) {
 	if callback.Status == model.CallbackStatusSuccess {
 		// 诊断成功：更新状态为 DIAGNOSED，保存诊断结果
 		return s.orderRepo.UpdateDiagnosisResult(
 			ctx,
 			callback.OrderID,
+			callback.RequestID,
 			callback.DiagnosisResult,
 			entity.OrderStatusDiagnosed,
 			"",
@@ -87,6 +108,7 @@ func (s *CallbackService) updateOrderStatus(ctx context.Context, callback *model
 		return s.orderRepo.UpdateDiagnosisResult(
 			ctx,
 			callback.OrderID,
+			callback.RequestID,
 			nil,
 			entity.OrderStatusFailed,
 			callback.Error,
@@ -94,6 +116,28 @@ func (s *CallbackService) updateOrderStatus(ctx context.Context, callback *model
 	}
 }
 
+// appendDiagnosis 记录本次诊断版本（失败的诊断同样记录，便于追溯）
+func (s *CallbackService) appendDiagnosis(ctx context.Context, callback *model.OrderDiagnoseCallback) error {
+	if callback.Status == model.CallbackStatusSuccess {
+		return s.orderRepo.AppendDiagnosis(
+			ctx,
+			callback.OrderID,
+			callback.RequestID,
+			callback.DiagnosisResult,
+			entity.OrderStatusDiagnosed,
+			"",
+		)
+	}
+	return s.orderRepo.AppendDiagnosis(
+		ctx,
+		callback.OrderID,
+		callback.RequestID,
+		nil,
+		entity.OrderStatusFailed,
+		callback.Error,
+	)
+}
+
 // publishNotification 发送 Redis PubSub 通知（使用订单独立频道）
 func (s *CallbackService) publishNotification(ctx context.Context, callback *model.OrderDiagnoseCallback) error {
 	// 构造独立频道名称
@@ -102,10 +146,9 @@ func (s *CallbackService) publishNotification(ctx context.Context, callback *mod
 	// 构造通知数据（与 dpmain API 期望格式一致）
 	var notificationData interface{}
 	if callback.Status == model.CallbackStatusSuccess && callback.DiagnosisResult != nil {
-		// 成功：发送诊断结果
-		notificationData = map[string]interface{}{
-			"items": callback.DiagnosisResult.Items,
-		}
+		// 成功：发送完整的诊断结果（Smart Wait 会将其写回 diagnose_result，须与回调保存的结果一致，
+		// 包括 explain 模式的执行轨迹与商品目录补全记录）
+		notificationData = callback.DiagnosisResult
 	} else {
 		// 失败：发送错误信息
 		notificationData = map[string]interface{}{
@@ -0,0 +1,119 @@
+package svcontract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+	"oip/dpmain/internal/app/domains/modules/mdcontract"
+	"oip/dpmain/internal/app/pkg/idgen"
+)
+
+// ContractService 协议价服务，负责合同业务编排
+type ContractService struct {
+	contractModule *mdcontract.ContractModule
+}
+
+// NewContractService 创建协议价服务实例
+func NewContractService(contractModule *mdcontract.ContractModule) *ContractService {
+	return &ContractService{
+		contractModule: contractModule,
+	}
+}
+
+// CreateContract 创建合同（完整业务流程）
+// 1. 验证 account 存在
+// 2. 检查同一承运商服务是否已有合同
+// 3. 生成分布式ID，创建合同并落库
+func (s *ContractService) CreateContract(ctx context.Context, accountID int64, terms *etcontract.Terms) (*etcontract.Contract, error) {
+	if err := s.ensureAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	contract, err := etcontract.NewContract(idgen.GenerateID(), accountID, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDuplicate(ctx, contract); err != nil {
+		return nil, err
+	}
+
+	if err := s.contractModule.CreateContract(ctx, contract); err != nil {
+		return nil, fmt.Errorf("save contract failed: %w", err)
+	}
+
+	return contract, nil
+}
+
+// UpdateContract 更新合同条款
+func (s *ContractService) UpdateContract(ctx context.Context, accountID, contractID int64, terms *etcontract.Terms) (*etcontract.Contract, error) {
+	contract, err := s.GetContract(ctx, accountID, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := contract.UpdateTerms(terms); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDuplicate(ctx, contract); err != nil {
+		return nil, err
+	}
+
+	if err := s.contractModule.UpdateContract(ctx, contract); err != nil {
+		return nil, fmt.Errorf("save contract failed: %w", err)
+	}
+
+	return contract, nil
+}
+
+// DeleteContract 删除合同
+func (s *ContractService) DeleteContract(ctx context.Context, accountID, contractID int64) error {
+	return s.contractModule.DeleteContract(ctx, accountID, contractID)
+}
+
+// GetContract 查询合同
+func (s *ContractService) GetContract(ctx context.Context, accountID, contractID int64) (*etcontract.Contract, error) {
+	contract, err := s.contractModule.GetContract(ctx, accountID, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("get contract failed: %w", err)
+	}
+	if contract == nil {
+		return nil, etcontract.ErrContractNotFound
+	}
+	return contract, nil
+}
+
+// ListContracts 查询账号全部合同
+func (s *ContractService) ListContracts(ctx context.Context, accountID int64) ([]*etcontract.Contract, error) {
+	if err := s.ensureAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return s.contractModule.ListContracts(ctx, accountID)
+}
+
+// ensureAccount 校验账号存在
+func (s *ContractService) ensureAccount(ctx context.Context, accountID int64) error {
+	exists, err := s.contractModule.AccountExists(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("check account exists failed: %w", err)
+	}
+	if !exists {
+		return errors.New("account not found")
+	}
+	return nil
+}
+
+// checkDuplicate 同一账号在同一承运商服务上只允许一份合同
+func (s *ContractService) checkDuplicate(ctx context.Context, contract *etcontract.Contract) error {
+	existing, err := s.contractModule.GetContractByCarrierService(ctx, contract.AccountID, contract.Carrier, contract.Service)
+	if err != nil {
+		return fmt.Errorf("check contract duplicate failed: %w", err)
+	}
+	if existing != nil && existing.ID != contract.ID {
+		return fmt.Errorf("contract already exists: carrier=%s, service=%s", contract.Carrier, contract.Service)
+	}
+	return nil
+}
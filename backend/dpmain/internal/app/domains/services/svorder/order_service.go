@@ -8,11 +8,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/entity/etaccount"
 	"oip/dpmain/internal/app/domains/entity/etorder"
 	"oip/dpmain/internal/app/domains/modules/mddiagnosis"
 	"oip/dpmain/internal/app/domains/modules/mdorder"
 )
 
+// diagnosingTimeout 诊断任务的最长处理时间
+// 订单处于 DIAGNOSING 超过该时间仍未收到回调时视为任务丢失，允许重新诊断
+const diagnosingTimeout = 10 * time.Minute
+
 // OrderService 订单服务，负责订单业务编排
 type OrderService struct {
 	orderModule     *mdorder.OrderModule
@@ -31,10 +37,13 @@ func NewOrderService(orderModule *mdorder.OrderModule, diagnosisModule *mddiagno
 // 1. 验证 account 存在
 // 2. 检查订单重复
 // 3. 验证货件信息
-// 4. 创建订单并落库
-// 5. 发布到诊断队列
-// 6. Smart Wait（等待诊断结果）
-func (s *OrderService) CreateOrder(ctx context.Context, accountID int64, merchantOrderNo string, shipment *etorder.Shipment, waitSeconds int) (*etorder.Order, error) {
+// 4. 用账号商品目录补全商品缺失的字段
+// 5. 合并诊断选项（请求优先，缺省使用账号设置）
+// 6. 创建订单并落库
+// 7. 发布到诊断队列
+// 8. Smart Wait（等待诊断结果）
+// explain 为 true 时诊断结果附带各诊断器的执行轨迹
+func (s *OrderService) CreateOrder(ctx context.Context, accountID int64, merchantOrderNo string, shipment *etorder.Shipment, options *etorder.DiagnoseOptions, waitSeconds int, explain bool) (*etorder.Order, error) {
 	exists, err := s.orderModule.AccountExists(ctx, accountID)
 	if err != nil {
 		return nil, fmt.Errorf("check account exists failed: %w", err)
@@ -55,22 +64,75 @@ func (s *OrderService) CreateOrder(ctx context.Context, accountID int64, merchan
 		return nil, fmt.Errorf("validate shipment failed: %w", err)
 	}
 
+	enrichments, err := s.enrichShipment(ctx, accountID, shipment)
+	if err != nil {
+		return nil, fmt.Errorf("enrich shipment failed: %w", err)
+	}
+
+	options, err = s.resolveDiagnoseOptions(ctx, accountID, options)
+	if err != nil {
+		return nil, fmt.Errorf("resolve diagnose options failed: %w", err)
+	}
+	options.Enrichments = enrichments
+
 	order, err := etorder.NewOrder(uuid.New().String(), accountID, merchantOrderNo, shipment)
 	if err != nil {
 		return nil, fmt.Errorf("create order entity failed: %w", err)
 	}
+	order.SetDiagnoseOptions(options)
+	order.DiagnoseRequestID = uuid.New().String()
 
 	if err := s.orderModule.CreateOrder(ctx, order); err != nil {
 		return nil, fmt.Errorf("save order failed: %w", err)
 	}
 
-	// 5. 发布到诊断队列
-	if err := s.diagnosisModule.PublishDiagnoseJob(ctx, order); err != nil {
+	// 7. 发布到诊断队列
+	if err := s.diagnosisModule.PublishDiagnoseJob(ctx, order, explain); err != nil {
 		// 发布失败只记录日志，不影响订单创建成功
 		log.Printf("[WARN] publish diagnose job failed: order_id=%s, error=%v", order.ID, err)
 	}
 
-	// 6. Smart Wait（等待诊断结果）
+	// 8. Smart Wait（等待诊断结果）
+	return s.waitForDiagnosis(ctx, order, waitSeconds)
+}
+
+// RediagnoseOrder 重新诊断订单
+// 复用下单时确定的诊断选项（协议价、箱型目录等快照不随账号设置变化），订单状态重置为 DIAGNOSING 后重新下发诊断任务
+// 与下单不同，任务发布失败时恢复原状态与原诊断任务并返回错误（订单已有的诊断结果保持不变，原任务的回调仍被接受）
+// 状态先于任务发布更新，避免诊断回调先到达后又被覆盖为 DIAGNOSING
+// 订单正在诊断时返回 etorder.ErrOrderDiagnosing（超过 diagnosingTimeout 仍未完成的视为任务丢失，允许重新诊断）；
+// 新任务的 request_id 与状态一同落库，被取代的旧任务的回调不会覆盖新结果
+func (s *OrderService) RediagnoseOrder(ctx context.Context, orderID string, waitSeconds int, explain bool) (*etorder.Order, error) {
+	order, err := s.orderModule.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("get order failed: %w", err)
+	}
+
+	previous := *order
+	requestID := uuid.New().String()
+	started, err := s.orderModule.StartDiagnosis(ctx, order.ID, requestID, time.Now().Add(-diagnosingTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("update order status failed: %w", err)
+	}
+	if !started {
+		return nil, etorder.ErrOrderDiagnosing
+	}
+	order.Status = etorder.OrderStatusDiagnosing
+	order.DiagnoseRequestID = requestID
+	order.UpdatedAt = time.Now()
+
+	if err := s.diagnosisModule.PublishDiagnoseJob(ctx, order, explain); err != nil {
+		if restoreErr := s.orderModule.RevertDiagnosis(ctx, order.ID, requestID, &previous); restoreErr != nil {
+			log.Printf("[ERROR] restore order status failed: order_id=%s, error=%v", order.ID, restoreErr)
+		}
+		return nil, fmt.Errorf("publish diagnose job failed: %w", err)
+	}
+
+	return s.waitForDiagnosis(ctx, order, waitSeconds)
+}
+
+// waitForDiagnosis Smart Wait：等待诊断结果并写回订单，超时或订阅失败时返回诊断中的订单
+func (s *OrderService) waitForDiagnosis(ctx context.Context, order *etorder.Order, waitSeconds int) (*etorder.Order, error) {
 	if waitSeconds > 0 {
 		timeout := time.Duration(waitSeconds) * time.Second
 		result, err := s.diagnosisModule.WaitForDiagnosisResult(ctx, order.ID, timeout)
@@ -88,12 +150,16 @@ func (s *OrderService) CreateOrder(ctx context.Context, accountID int64, merchan
 				return nil, fmt.Errorf("update order entity failed: %w", err)
 			}
 
-			// 持久化到 DB
-			if err := s.orderModule.UpdateDiagnoseResult(ctx, order.ID, result); err != nil {
+			// 持久化到 DB（订单已重新下发诊断任务时不覆盖新任务，本次请求仍返回自己的诊断结果）
+			applied, err := s.orderModule.UpdateDiagnoseResult(ctx, order.ID, order.DiagnoseRequestID, result)
+			if err != nil {
 				// 严重问题：内存已更新，DB 更新失败
 				log.Printf("[ERROR] persist diagnose result failed: order_id=%s, error=%v", order.ID, err)
 				return nil, fmt.Errorf("persist diagnose result failed: %w", err)
 			}
+			if !applied {
+				log.Printf("[INFO] diagnose result superseded by a newer diagnosis: order_id=%s, request_id=%s", order.ID, order.DiagnoseRequestID)
+			}
 		}
 	}
 
@@ -105,11 +171,155 @@ func (s *OrderService) GetOrder(ctx context.Context, orderID string) (*etorder.O
 	return s.orderModule.GetOrder(ctx, orderID)
 }
 
+// ListDiagnoses 查询订单的诊断版本历史（订单不存在时返回 etorder.ErrOrderNotFound）
+func (s *OrderService) ListDiagnoses(ctx context.Context, orderID string) ([]*etorder.DiagnosisVersion, error) {
+	if _, err := s.orderModule.GetOrder(ctx, orderID); err != nil {
+		return nil, fmt.Errorf("get order failed: %w", err)
+	}
+	versions, err := s.orderModule.ListDiagnoses(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("list diagnoses failed: %w", err)
+	}
+	return versions, nil
+}
+
+// DiffDiagnoses 比较订单的两个诊断版本
+// to 为 0 时取最新版本，from 为 0 时取 to 的上一个版本；版本不存在时返回 etorder.ErrDiagnosisNotFound
+func (s *OrderService) DiffDiagnoses(ctx context.Context, orderID string, from, to int) (*etorder.DiagnosisDiff, error) {
+	versions, err := s.ListDiagnoses(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		to = len(versions)
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	find := func(version int) (*etorder.DiagnosisVersion, error) {
+		for _, v := range versions {
+			if v.Version == version {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: version %d", etorder.ErrDiagnosisNotFound, version)
+	}
+	fromVersion, err := find(from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := find(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return etorder.DiffDiagnoses(fromVersion, toVersion), nil
+}
+
 // ListOrders 查询订单列表
 func (s *OrderService) ListOrders(ctx context.Context, accountID int64, page, limit int) ([]*etorder.Order, int64, error) {
 	return s.orderModule.ListOrders(ctx, accountID, page, limit)
 }
 
+// SavingsReport 账号运费节省报告
+// 汇总 [from, to) 内已诊断订单的选用服务核对结果；未提供选用服务或核对失败的订单不计入
+// 账号不存在时返回 etaccount.ErrAccountNotFound
+func (s *OrderService) SavingsReport(ctx context.Context, accountID int64, from, to time.Time) (*etorder.SavingsReport, error) {
+	exists, err := s.orderModule.AccountExists(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("check account exists failed: %w", err)
+	}
+	if !exists {
+		return nil, etaccount.ErrAccountNotFound
+	}
+
+	report := etorder.NewSavingsReport(accountID, from, to)
+	err = s.orderModule.ScanDiagnoseResults(ctx, accountID, from, to, func(result *etorder.DiagnoseResult) error {
+		if audit, ok := result.ServiceAudit(); ok {
+			report.Add(audit)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan diagnose results failed: %w", err)
+	}
+	report.Finalize()
+
+	return report, nil
+}
+
+// resolveDiagnoseOptions 合并诊断选项
+// 请求中指定了诊断类型、推荐策略时使用请求值，否则使用账号设置中的默认值；偏好币种始终取账号设置
+// 账号协议价、箱型目录与账号概况在下单时快照进诊断选项，之后修改合同或箱型不影响已下单订单
+func (s *OrderService) resolveDiagnoseOptions(ctx context.Context, accountID int64, options *etorder.DiagnoseOptions) (*etorder.DiagnoseOptions, error) {
+	if options == nil {
+		options = &etorder.DiagnoseOptions{}
+	}
+
+	if err := model.ValidateDiagnosisTypes(options.Diagnosers); err != nil {
+		return nil, err
+	}
+	if err := options.Strategy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateDate(options.PromisedDeliveryDate); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateIncoterm(options.Incoterm); err != nil {
+		return nil, err
+	}
+	if err := options.Selected.Validate(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.orderModule.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get account settings failed: %w", err)
+	}
+	if account.Settings != nil {
+		if len(options.Diagnosers) == 0 {
+			options.Diagnosers = account.Settings.Diagnosers
+		}
+		if options.Strategy == nil {
+			options.Strategy = account.Settings.Strategy
+		}
+		options.PreferredCurrency = account.Settings.PreferredCurrency
+		options.Boxes = account.Settings.Boxes
+	}
+
+	priorOrders, err := s.orderModule.CountOrders(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("count account orders failed: %w", err)
+	}
+	options.Account = &model.AccountProfile{
+		CreatedAt:   account.CreatedAt,
+		PriorOrders: priorOrders,
+	}
+
+	contracts, err := s.orderModule.ListContracts(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list account contracts failed: %w", err)
+	}
+	options.Contracts = make([]model.RateContract, 0, len(contracts))
+	for _, contract := range contracts {
+		options.Contracts = append(options.Contracts, contract.ToModel())
+	}
+
+	return options, nil
+}
+
+// enrichShipment 用账号商品目录补全货件中商品缺失的字段（直接修改货件），返回补全记录
+// 补全后的货件随订单保存，重新诊断不再重复补全；目录之后的修改只影响新订单
+func (s *OrderService) enrichShipment(ctx context.Context, accountID int64, shipment *etorder.Shipment) ([]model.ItemEnrichment, error) {
+	catalog, err := s.orderModule.LoadCatalog(ctx, accountID, shipment)
+	if err != nil {
+		return nil, fmt.Errorf("load product catalog failed: %w", err)
+	}
+	return catalog.Enrich(shipment), nil
+}
+
 // validateShipment 验证货件信息
 func (s *OrderService) validateShipment(shipment *etorder.Shipment) error {
 	if shipment == nil {
@@ -121,5 +331,6 @@ func (s *OrderService) validateShipment(shipment *etorder.Shipment) error {
 	if len(shipment.Parcels) == 0 {
 		return errors.New("parcels cannot be empty")
 	}
-	return nil
+	// 与 dpsync 诊断任务使用同一套结构校验，避免任务下发后才被拒绝
+	return shipment.ToModel().Validate()
 }
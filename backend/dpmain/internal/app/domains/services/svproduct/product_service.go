@@ -0,0 +1,167 @@
+package svproduct
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+	"oip/dpmain/internal/app/domains/modules/mdproduct"
+	"oip/dpmain/internal/app/pkg/idgen"
+)
+
+// ProductService 商品目录服务，负责商品业务编排
+type ProductService struct {
+	productModule *mdproduct.ProductModule
+}
+
+// NewProductService 创建商品目录服务实例
+func NewProductService(productModule *mdproduct.ProductModule) *ProductService {
+	return &ProductService{
+		productModule: productModule,
+	}
+}
+
+// CreateProduct 创建商品（完整业务流程）
+// 1. 验证 account 存在
+// 2. 检查 SKU 是否已存在
+// 3. 生成分布式ID，创建商品并落库
+func (s *ProductService) CreateProduct(ctx context.Context, accountID int64, attrs *etproduct.Attributes) (*etproduct.Product, error) {
+	if err := s.ensureAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	product, err := etproduct.NewProduct(idgen.GenerateID(), accountID, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDuplicate(ctx, product); err != nil {
+		return nil, err
+	}
+
+	if err := s.productModule.CreateProduct(ctx, product); err != nil {
+		return nil, fmt.Errorf("save product failed: %w", err)
+	}
+
+	return product, nil
+}
+
+// UpdateProduct 整体替换商品属性
+func (s *ProductService) UpdateProduct(ctx context.Context, accountID, productID int64, attrs *etproduct.Attributes) (*etproduct.Product, error) {
+	product, err := s.GetProduct(ctx, accountID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := product.UpdateAttributes(attrs); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDuplicate(ctx, product); err != nil {
+		return nil, err
+	}
+
+	if err := s.productModule.UpdateProduct(ctx, product); err != nil {
+		return nil, fmt.Errorf("save product failed: %w", err)
+	}
+
+	return product, nil
+}
+
+// ImportProducts 批量导入商品（按 SKU 新增或整体替换已有商品，全部成功或全部失败）
+// 返回新增与更新的商品（各自保持请求顺序）
+func (s *ProductService) ImportProducts(ctx context.Context, accountID int64, attrsList []*etproduct.Attributes) (created, updated []*etproduct.Product, err error) {
+	if err := etproduct.ValidateImport(attrsList); err != nil {
+		return nil, nil, err
+	}
+	if err := s.ensureAccount(ctx, accountID); err != nil {
+		return nil, nil, err
+	}
+
+	skus := make([]string, 0, len(attrsList))
+	for _, attrs := range attrsList {
+		skus = append(skus, attrs.SKU)
+	}
+	existing, err := s.productModule.ListProductsBySKUs(ctx, accountID, skus)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list existing products failed: %w", err)
+	}
+	bySKU := make(map[string]*etproduct.Product, len(existing))
+	for _, product := range existing {
+		bySKU[etproduct.NormalizeSKU(product.SKU)] = product
+	}
+
+	created = make([]*etproduct.Product, 0)
+	updated = make([]*etproduct.Product, 0)
+	for _, attrs := range attrsList {
+		if product, ok := bySKU[attrs.NormalizedSKU()]; ok {
+			if err := product.UpdateAttributes(attrs); err != nil {
+				return nil, nil, err
+			}
+			updated = append(updated, product)
+			continue
+		}
+
+		product, err := etproduct.NewProduct(idgen.GenerateID(), accountID, attrs)
+		if err != nil {
+			return nil, nil, err
+		}
+		created = append(created, product)
+	}
+
+	if err := s.productModule.SaveProducts(ctx, created, updated); err != nil {
+		return nil, nil, fmt.Errorf("save products failed: %w", err)
+	}
+
+	return created, updated, nil
+}
+
+// DeleteProduct 删除商品
+func (s *ProductService) DeleteProduct(ctx context.Context, accountID, productID int64) error {
+	return s.productModule.DeleteProduct(ctx, accountID, productID)
+}
+
+// GetProduct 查询商品
+func (s *ProductService) GetProduct(ctx context.Context, accountID, productID int64) (*etproduct.Product, error) {
+	product, err := s.productModule.GetProduct(ctx, accountID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("get product failed: %w", err)
+	}
+	if product == nil {
+		return nil, etproduct.ErrProductNotFound
+	}
+	return product, nil
+}
+
+// ListProducts 分页查询账号商品
+func (s *ProductService) ListProducts(ctx context.Context, accountID int64, page, limit int) ([]*etproduct.Product, int64, error) {
+	if err := s.ensureAccount(ctx, accountID); err != nil {
+		return nil, 0, err
+	}
+	return s.productModule.ListProducts(ctx, accountID, page, limit)
+}
+
+// ensureAccount 校验账号存在
+func (s *ProductService) ensureAccount(ctx context.Context, accountID int64) error {
+	exists, err := s.productModule.AccountExists(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("check account exists failed: %w", err)
+	}
+	if !exists {
+		return errors.New("account not found")
+	}
+	return nil
+}
+
+// checkDuplicate 同一账号内 SKU 唯一
+func (s *ProductService) checkDuplicate(ctx context.Context, product *etproduct.Product) error {
+	existing, err := s.productModule.GetProductBySKU(ctx, product.AccountID, product.SKU)
+	if err != nil {
+		return fmt.Errorf("check product duplicate failed: %w", err)
+	}
+	if existing != nil && existing.ID != product.ID {
+		return fmt.Errorf("product already exists: sku=%s", product.SKU)
+	}
+	return nil
+}
@@ -105,6 +105,11 @@ func NotFound(c *gin.Context, message string) {
 	Error(c, http.StatusNotFound, message)
 }
 
+// Conflict 409 错误（资源当前状态不允许该操作）
+func Conflict(c *gin.Context, message string) {
+	Error(c, http.StatusConflict, message)
+}
+
 // InternalError 500 错误
 func InternalError(c *gin.Context, message string) {
 	Error(c, http.StatusInternalServerError, message)
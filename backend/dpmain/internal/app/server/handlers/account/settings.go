@@ -0,0 +1,64 @@
+package account
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/apimodel/request"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// UpdateSettings godoc
+// @Summary      更新账号设置
+// @Description  更新账号级设置，例如默认执行的诊断器列表（为空表示执行全部诊断器）
+// @Description  下单请求中携带 diagnosers 时优先使用请求中的列表
+// @Description  boxes 为账号箱型目录，下单时快照进订单，包装诊断据此给出换箱与合箱建议
+// @Tags         accounts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        request body request.UpdateAccountSettingsRequest true "账号设置"
+// @Success      200 {object} ginx.Response{data=response.AccountResponse} "更新成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/settings [put]
+func (h *AccountHandler) UpdateSettings(c *gin.Context) {
+	accountID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		ginx.BadRequest(c, "invalid account_id")
+		return
+	}
+
+	var req request.UpdateAccountSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.BadRequestWithValidation(c, err)
+		return
+	}
+	if err := model.ValidateDiagnosisTypes(req.Diagnosers); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	settings := req.ToSettingsEntity()
+	if err := settings.Strategy.Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+	if err := model.ValidateBoxes(settings.Boxes); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	account, err := h.accountService.UpdateSettings(c.Request.Context(), accountID, settings)
+	if err != nil {
+		log.Printf("[ERROR] update account settings failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromAccountEntity(account))
+}
@@ -0,0 +1,52 @@
+package contract
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/request"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// Create godoc
+// @Summary      创建协议价
+// @Description  为账号新增承运商协议价（折扣、固定运费、燃油附加费），之后下单的费率诊断按协议价计算
+// @Description  同一账号在同一承运商服务上只允许一份合同；service 为空表示对该承运商全部服务生效
+// @Tags         contracts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        request body request.ContractRequest true "协议价条款"
+// @Success      200 {object} ginx.Response{data=response.ContractResponse} "创建成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/contracts [post]
+func (h *ContractHandler) Create(c *gin.Context) {
+	accountID, _, ok := parseIDs(c, false)
+	if !ok {
+		return
+	}
+
+	var req request.ContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.BadRequestWithValidation(c, err)
+		return
+	}
+
+	terms := req.ToTermsEntity()
+	if err := terms.Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	contract, err := h.contractService.CreateContract(c.Request.Context(), accountID, terms)
+	if err != nil {
+		log.Printf("[ERROR] create contract failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromContractEntity(contract))
+}
@@ -0,0 +1,40 @@
+package contract
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/services/svcontract"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// ContractHandler 协议价 HTTP 处理器
+type ContractHandler struct {
+	contractService *svcontract.ContractService
+}
+
+// NewContractHandler 创建协议价处理器实例
+func NewContractHandler(contractService *svcontract.ContractService) *ContractHandler {
+	return &ContractHandler{
+		contractService: contractService,
+	}
+}
+
+// parseIDs 解析路径中的账号ID与合同ID（withContract=false 时只解析账号ID）
+func parseIDs(c *gin.Context, withContract bool) (accountID, contractID int64, ok bool) {
+	accountID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		ginx.BadRequest(c, "invalid account_id")
+		return 0, 0, false
+	}
+	if !withContract {
+		return accountID, 0, true
+	}
+
+	contractID, err = strconv.ParseInt(c.Param("contract_id"), 10, 64)
+	if err != nil {
+		ginx.BadRequest(c, "invalid contract_id")
+		return 0, 0, false
+	}
+	return accountID, contractID, true
+}
@@ -0,0 +1,70 @@
+package contract
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// List godoc
+// @Summary      查询账号协议价列表
+// @Description  返回账号下全部承运商协议价
+// @Tags         contracts
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Success      200 {object} ginx.Response{data=[]response.ContractResponse} "查询成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/contracts [get]
+func (h *ContractHandler) List(c *gin.Context) {
+	accountID, _, ok := parseIDs(c, false)
+	if !ok {
+		return
+	}
+
+	contracts, err := h.contractService.ListContracts(c.Request.Context(), accountID)
+	if err != nil {
+		log.Printf("[ERROR] list contracts failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromContractEntities(contracts))
+}
+
+// Get godoc
+// @Summary      获取协议价详情
+// @Tags         contracts
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        contract_id path int true "合同ID"
+// @Success      200 {object} ginx.Response{data=response.ContractResponse} "查询成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "合同不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/contracts/{contract_id} [get]
+func (h *ContractHandler) Get(c *gin.Context) {
+	accountID, contractID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	contract, err := h.contractService.GetContract(c.Request.Context(), accountID, contractID)
+	if err != nil {
+		if errors.Is(err, etcontract.ErrContractNotFound) {
+			ginx.NotFound(c, err.Error())
+			return
+		}
+		log.Printf("[ERROR] get contract failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromContractEntity(contract))
+}
@@ -0,0 +1,90 @@
+package contract
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/request"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etcontract"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// Update godoc
+// @Summary      更新协议价
+// @Description  整体替换合同条款（已下单的订单保留下单时的协议价快照）
+// @Tags         contracts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        contract_id path int true "合同ID"
+// @Param        request body request.ContractRequest true "协议价条款"
+// @Success      200 {object} ginx.Response{data=response.ContractResponse} "更新成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "合同不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/contracts/{contract_id} [put]
+func (h *ContractHandler) Update(c *gin.Context) {
+	accountID, contractID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	var req request.ContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.BadRequestWithValidation(c, err)
+		return
+	}
+
+	terms := req.ToTermsEntity()
+	if err := terms.Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	contract, err := h.contractService.UpdateContract(c.Request.Context(), accountID, contractID, terms)
+	if err != nil {
+		if errors.Is(err, etcontract.ErrContractNotFound) {
+			ginx.NotFound(c, err.Error())
+			return
+		}
+		log.Printf("[ERROR] update contract failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromContractEntity(contract))
+}
+
+// Delete godoc
+// @Summary      删除协议价
+// @Tags         contracts
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        contract_id path int true "合同ID"
+// @Success      200 {object} ginx.Response "删除成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "合同不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/contracts/{contract_id} [delete]
+func (h *ContractHandler) Delete(c *gin.Context) {
+	accountID, contractID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.contractService.DeleteContract(c.Request.Context(), accountID, contractID); err != nil {
+		if errors.Is(err, etcontract.ErrContractNotFound) {
+			ginx.NotFound(c, err.Error())
+			return
+		}
+		log.Printf("[ERROR] delete contract failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, nil)
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"strconv"
 
+	"oip/common/model"
 	"oip/dpmain/internal/app/domains/apimodel/request"
 	"oip/dpmain/internal/app/domains/apimodel/response"
 	"oip/dpmain/internal/app/domains/entity/etorder"
@@ -15,7 +16,9 @@ import (
 
 // Create godoc
 // @Summary      创建订单
-// @Description  创建订单并触发智能诊断（物流费率计算 + 异常检测）
+// @Description  创建订单并触发智能诊断（物流费率计算 + 异常检测 + 合规检查）
+// @Description
+// @Description  诊断器选择：请求中的 diagnosers 优先，未指定时使用账号设置，账号未设置时执行全部诊断器
 // @Description
 // @Description  Smart Wait 机制说明：
 // @Description  - 接口会 Hold 10s 等待诊断结果
@@ -31,17 +34,19 @@ import (
 // @Accept       json
 // @Produce      json
 // @Param        request body request.CreateOrderRequest true "创建订单请求"
+// @Param        wait query int false "Smart Wait 等待秒数"
+// @Param        explain query bool false "是否记录诊断执行轨迹（各诊断项的输入、规则及中间值、使用的费率卡行）"
 // @Success      200 {object} ginx.Response{data=response.OrderResponse} "创建成功"
 // @Failure      400 {object} ginx.Response "参数错误"
 // @Failure      500 {object} ginx.Response "服务器错误"
 // @Security     ApiKeyAuth
 // @Router       /orders [post]
 func (h *OrderHandler) Create(c *gin.Context) {
-	waitSeconds := 0
-	if waitStr := c.Query("wait"); waitStr != "" {
-		if w, err := strconv.Atoi(waitStr); err == nil && w > 0 {
-			waitSeconds = w
-		}
+	waitSeconds := waitQuery(c)
+	explain, err := explainQuery(c)
+	if err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
 	}
 
 	var req request.CreateOrderRequest
@@ -50,8 +55,36 @@ func (h *OrderHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if err := model.ValidateDiagnosisTypes(req.Diagnosers); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
 	shipment := req.ToShipmentEntity()
-	order, err := h.orderService.CreateOrder(c.Request.Context(), req.AccountID, req.MerchantOrderNo, shipment, waitSeconds)
+	if err := shipment.ToModel().Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	options := req.ToDiagnoseOptionsEntity()
+	if err := options.Strategy.Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+	if err := model.ValidateDate(options.PromisedDeliveryDate); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+	if err := model.ValidateIncoterm(options.Incoterm); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+	if err := options.Selected.Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	order, err := h.orderService.CreateOrder(c.Request.Context(), req.AccountID, req.MerchantOrderNo, shipment, options, waitSeconds, explain)
 	if err != nil {
 		log.Printf("[ERROR] create order failed: %v", err)
 		ginx.InternalError(c, err.Error())
@@ -67,3 +100,26 @@ func (h *OrderHandler) Create(c *gin.Context) {
 		ginx.Success(c, response.FromOrderEntity(order))
 	}
 }
+
+// waitQuery Smart Wait 等待秒数（缺省或非法值视为不等待）
+func waitQuery(c *gin.Context) int {
+	if waitStr := c.Query("wait"); waitStr != "" {
+		if w, err := strconv.Atoi(waitStr); err == nil && w > 0 {
+			return w
+		}
+	}
+	return 0
+}
+
+// explainQuery 是否开启 explain 模式（缺省为 false）
+func explainQuery(c *gin.Context) (bool, error) {
+	value := c.Query("explain")
+	if value == "" {
+		return false, nil
+	}
+	explain, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid explain: %s (expected true or false)", value)
+	}
+	return explain, nil
+}
@@ -0,0 +1,111 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etorder"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// ListDiagnoses godoc
+// @Summary      订单诊断历史
+// @Description  按版本号升序返回订单的全部诊断记录（下单诊断与每次重新诊断各一条，失败的诊断同样记录）
+// @Description  每条记录包含诊断结果以及产生该结果的引擎版本与各诊断项的规则/费率卡版本；执行轨迹只随订单详情返回
+// @Tags         orders
+// @Produce      json
+// @Param        id path string true "订单ID（UUID）"
+// @Success      200 {object} ginx.Response{data=response.DiagnosisHistoryResponse} "查询成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "订单不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /orders/{id}/diagnoses [get]
+func (h *OrderHandler) ListDiagnoses(c *gin.Context) {
+	orderID := c.Param("id")
+	if orderID == "" {
+		ginx.BadRequest(c, "order_id required")
+		return
+	}
+
+	versions, err := h.orderService.ListDiagnoses(c.Request.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, etorder.ErrOrderNotFound) {
+			ginx.NotFound(c, "order not found")
+			return
+		}
+		log.Printf("[ERROR] list diagnoses failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromDiagnosisVersions(orderID, versions))
+}
+
+// DiffDiagnoses godoc
+// @Summary      对比两个诊断版本
+// @Description  返回两个诊断版本之间新增/消失的异常、费率增减与运费/时效变化、推荐服务变化，以及引擎与规则/费率卡版本变化
+// @Description  缺省 to 为最新版本，from 为 to 的上一个版本
+// @Tags         orders
+// @Produce      json
+// @Param        id path string true "订单ID（UUID）"
+// @Param        from query int false "基准版本"
+// @Param        to query int false "对比版本"
+// @Success      200 {object} ginx.Response{data=response.DiagnosisDiffResponse} "查询成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "订单或诊断版本不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /orders/{id}/diagnoses/diff [get]
+func (h *OrderHandler) DiffDiagnoses(c *gin.Context) {
+	orderID := c.Param("id")
+	if orderID == "" {
+		ginx.BadRequest(c, "order_id required")
+		return
+	}
+
+	from, err := versionQuery(c, "from")
+	if err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+	to, err := versionQuery(c, "to")
+	if err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	diff, err := h.orderService.DiffDiagnoses(c.Request.Context(), orderID, from, to)
+	if err != nil {
+		if errors.Is(err, etorder.ErrOrderNotFound) {
+			ginx.NotFound(c, "order not found")
+			return
+		}
+		if errors.Is(err, etorder.ErrDiagnosisNotFound) {
+			ginx.NotFound(c, err.Error())
+			return
+		}
+		log.Printf("[ERROR] diff diagnoses failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromDiagnosisDiff(diff))
+}
+
+// versionQuery 解析诊断版本查询参数（缺省为 0，表示由服务层选择）
+func versionQuery(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s (expected a positive integer)", name, value)
+	}
+	return version, nil
+}
@@ -0,0 +1,70 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etorder"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// Rediagnose godoc
+// @Summary      重新诊断订单
+// @Description  使用下单时确定的诊断选项重新执行诊断，订单状态重置为 DIAGNOSING，完成后覆盖原诊断结果
+// @Description
+// @Description  explain=true 时诊断结果附带各诊断项的执行轨迹（输入、规则及中间值、使用的费率卡行），与诊断结果一同保存；
+// @Description  不带 explain 的重新诊断会清除之前保存的执行轨迹
+// @Description
+// @Description  订单正在诊断时返回 409（同一订单同时只有一个诊断任务；被取代的旧任务回调会被忽略）
+// @Description
+// @Description  Smart Wait 与创建订单一致：wait 秒内完成时返回诊断结果，否则返回 code=3001 与 poll_url
+// @Tags         orders
+// @Produce      json
+// @Param        id path string true "订单ID（UUID）"
+// @Param        wait query int false "Smart Wait 等待秒数"
+// @Param        explain query bool false "是否记录诊断执行轨迹"
+// @Success      200 {object} ginx.Response{data=response.OrderResponse} "诊断完成"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "订单不存在"
+// @Failure      409 {object} ginx.Response "订单正在诊断"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /orders/{id}/diagnose [post]
+func (h *OrderHandler) Rediagnose(c *gin.Context) {
+	orderID := c.Param("id")
+	if orderID == "" {
+		ginx.BadRequest(c, "order_id required")
+		return
+	}
+
+	explain, err := explainQuery(c)
+	if err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	order, err := h.orderService.RediagnoseOrder(c.Request.Context(), orderID, waitQuery(c), explain)
+	if err != nil {
+		if errors.Is(err, etorder.ErrOrderNotFound) {
+			ginx.NotFound(c, "order not found")
+			return
+		}
+		if errors.Is(err, etorder.ErrOrderDiagnosing) {
+			ginx.Conflict(c, "order is already being diagnosed")
+			return
+		}
+		log.Printf("[ERROR] rediagnose order failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	if order.Status == etorder.OrderStatusDiagnosing {
+		pollURL := fmt.Sprintf("/api/v1/orders/%s", order.ID)
+		ginx.Processing(c, order.ID, pollURL)
+		return
+	}
+	ginx.Success(c, response.FromOrderEntity(order))
+}
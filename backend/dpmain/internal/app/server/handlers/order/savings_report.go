@@ -0,0 +1,81 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"oip/common/model"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etaccount"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// defaultSavingsReportDays 未指定起始日期时统计的天数
+const defaultSavingsReportDays = 30
+
+// maxSavingsReportDays 单次报告最多统计的天数（含起止日期）
+const maxSavingsReportDays = 366
+
+// SavingsReport godoc
+// @Summary      账号运费节省报告
+// @Description  汇总时间范围内已诊断订单的选用服务核对结果（service_audit 诊断项）：多付运费的订单数、可节省金额，以及按选用服务与建议改用服务的明细
+// @Description  仅统计下单时提供了 selected_service 且核对成功的订单；日期按 UTC 计算，缺省统计截至今天的最近 30 天，最多统计 366 天
+// @Tags         accounts
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        from query string false "起始日期（含，YYYY-MM-DD）"
+// @Param        to query string false "截止日期（含，YYYY-MM-DD）"
+// @Success      200 {object} ginx.Response{data=response.SavingsReportResponse} "查询成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "账号不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/savings-report [get]
+func (h *OrderHandler) SavingsReport(c *gin.Context) {
+	accountID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || accountID <= 0 {
+		ginx.BadRequest(c, "invalid account_id")
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(model.DateLayout, value); err != nil {
+			ginx.BadRequest(c, model.ValidateDate(value).Error())
+			return
+		}
+	}
+	from := to.AddDate(0, 0, -(defaultSavingsReportDays - 1))
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(model.DateLayout, value); err != nil {
+			ginx.BadRequest(c, model.ValidateDate(value).Error())
+			return
+		}
+	}
+	if from.After(to) {
+		ginx.BadRequest(c, "from cannot be later than to")
+		return
+	}
+	if to.Sub(from) >= maxSavingsReportDays*24*time.Hour {
+		ginx.BadRequest(c, fmt.Sprintf("date range cannot exceed %d days", maxSavingsReportDays))
+		return
+	}
+
+	// 截止日期包含当天
+	report, err := h.orderService.SavingsReport(c.Request.Context(), accountID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		if errors.Is(err, etaccount.ErrAccountNotFound) {
+			ginx.NotFound(c, "account not found")
+			return
+		}
+		log.Printf("[ERROR] build savings report failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromSavingsReportEntity(report))
+}
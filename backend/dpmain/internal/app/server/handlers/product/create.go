@@ -0,0 +1,97 @@
+package product
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/request"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// Create godoc
+// @Summary      创建商品
+// @Description  向账号商品目录新增商品（SKU 主数据），之后下单时按 SKU 补全订单商品缺失的重量、尺寸、HS 编码、原产国与电池信息
+// @Description  同一账号内 SKU 唯一（忽略大小写）
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        request body request.ProductRequest true "商品属性"
+// @Success      200 {object} ginx.Response{data=response.ProductResponse} "创建成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/products [post]
+func (h *ProductHandler) Create(c *gin.Context) {
+	accountID, _, ok := parseIDs(c, false)
+	if !ok {
+		return
+	}
+
+	var req request.ProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.BadRequestWithValidation(c, err)
+		return
+	}
+
+	attrs := req.ToAttributesEntity()
+	if err := attrs.Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	product, err := h.productService.CreateProduct(c.Request.Context(), accountID, attrs)
+	if err != nil {
+		log.Printf("[ERROR] create product failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromProductEntity(product))
+}
+
+// Import godoc
+// @Summary      批量导入商品
+// @Description  按 SKU 批量新增或整体替换账号商品（单次最多 1000 个，全部成功或全部失败）
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        request body request.ImportProductsRequest true "商品列表"
+// @Success      200 {object} ginx.Response{data=response.ImportProductsResponse} "导入成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/products/import [post]
+func (h *ProductHandler) Import(c *gin.Context) {
+	accountID, _, ok := parseIDs(c, false)
+	if !ok {
+		return
+	}
+
+	var req request.ImportProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.BadRequestWithValidation(c, err)
+		return
+	}
+
+	attrsList := req.ToAttributesEntities()
+	if err := etproduct.ValidateImport(attrsList); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	created, updated, err := h.productService.ImportProducts(c.Request.Context(), accountID, attrsList)
+	if err != nil {
+		log.Printf("[ERROR] import products failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, &response.ImportProductsResponse{
+		Created: response.FromProductEntities(created),
+		Updated: response.FromProductEntities(updated),
+	})
+}
@@ -0,0 +1,40 @@
+package product
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/services/svproduct"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// ProductHandler 商品目录 HTTP 处理器
+type ProductHandler struct {
+	productService *svproduct.ProductService
+}
+
+// NewProductHandler 创建商品目录处理器实例
+func NewProductHandler(productService *svproduct.ProductService) *ProductHandler {
+	return &ProductHandler{
+		productService: productService,
+	}
+}
+
+// parseIDs 解析路径中的账号ID与商品ID（withProduct=false 时只解析账号ID）
+func parseIDs(c *gin.Context, withProduct bool) (accountID, productID int64, ok bool) {
+	accountID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		ginx.BadRequest(c, "invalid account_id")
+		return 0, 0, false
+	}
+	if !withProduct {
+		return accountID, 0, true
+	}
+
+	productID, err = strconv.ParseInt(c.Param("product_id"), 10, 64)
+	if err != nil {
+		ginx.BadRequest(c, "invalid product_id")
+		return 0, 0, false
+	}
+	return accountID, productID, true
+}
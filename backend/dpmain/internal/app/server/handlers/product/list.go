@@ -0,0 +1,95 @@
+package product
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// 分页参数
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// List godoc
+// @Summary      查询账号商品目录
+// @Description  分页返回账号商品（按 SKU 排序）
+// @Tags         products
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        page query int false "页码（从 1 开始）" default(1)
+// @Param        limit query int false "每页数量（最大 200）" default(50)
+// @Success      200 {object} ginx.Response{data=response.ProductListResponse} "查询成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/products [get]
+func (h *ProductHandler) List(c *gin.Context) {
+	accountID, _, ok := parseIDs(c, false)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		ginx.BadRequest(c, "invalid page")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		ginx.BadRequest(c, "invalid limit")
+		return
+	}
+
+	products, total, err := h.productService.ListProducts(c.Request.Context(), accountID, page, limit)
+	if err != nil {
+		log.Printf("[ERROR] list products failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, &response.ProductListResponse{
+		Products: response.FromProductEntities(products),
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	})
+}
+
+// Get godoc
+// @Summary      获取商品详情
+// @Tags         products
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        product_id path int true "商品ID"
+// @Success      200 {object} ginx.Response{data=response.ProductResponse} "查询成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "商品不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/products/{product_id} [get]
+func (h *ProductHandler) Get(c *gin.Context) {
+	accountID, productID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.GetProduct(c.Request.Context(), accountID, productID)
+	if err != nil {
+		if errors.Is(err, etproduct.ErrProductNotFound) {
+			ginx.NotFound(c, err.Error())
+			return
+		}
+		log.Printf("[ERROR] get product failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromProductEntity(product))
+}
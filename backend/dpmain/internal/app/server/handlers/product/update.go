@@ -0,0 +1,90 @@
+package product
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"oip/dpmain/internal/app/domains/apimodel/request"
+	"oip/dpmain/internal/app/domains/apimodel/response"
+	"oip/dpmain/internal/app/domains/entity/etproduct"
+	"oip/dpmain/internal/app/pkg/ginx"
+)
+
+// Update godoc
+// @Summary      更新商品
+// @Description  整体替换商品属性（已下单的订单保留下单时补全的字段）
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        product_id path int true "商品ID"
+// @Param        request body request.ProductRequest true "商品属性"
+// @Success      200 {object} ginx.Response{data=response.ProductResponse} "更新成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "商品不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/products/{product_id} [put]
+func (h *ProductHandler) Update(c *gin.Context) {
+	accountID, productID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	var req request.ProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginx.BadRequestWithValidation(c, err)
+		return
+	}
+
+	attrs := req.ToAttributesEntity()
+	if err := attrs.Validate(); err != nil {
+		ginx.BadRequest(c, err.Error())
+		return
+	}
+
+	product, err := h.productService.UpdateProduct(c.Request.Context(), accountID, productID, attrs)
+	if err != nil {
+		if errors.Is(err, etproduct.ErrProductNotFound) {
+			ginx.NotFound(c, err.Error())
+			return
+		}
+		log.Printf("[ERROR] update product failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, response.FromProductEntity(product))
+}
+
+// Delete godoc
+// @Summary      删除商品
+// @Tags         products
+// @Produce      json
+// @Param        id path int true "账号ID"
+// @Param        product_id path int true "商品ID"
+// @Success      200 {object} ginx.Response "删除成功"
+// @Failure      400 {object} ginx.Response "参数错误"
+// @Failure      404 {object} ginx.Response "商品不存在"
+// @Failure      500 {object} ginx.Response "服务器错误"
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/products/{product_id} [delete]
+func (h *ProductHandler) Delete(c *gin.Context) {
+	accountID, productID, ok := parseIDs(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteProduct(c.Request.Context(), accountID, productID); err != nil {
+		if errors.Is(err, etproduct.ErrProductNotFound) {
+			ginx.NotFound(c, err.Error())
+			return
+		}
+		log.Printf("[ERROR] delete product failed: %v", err)
+		ginx.InternalError(c, err.Error())
+		return
+	}
+
+	ginx.Success(c, nil)
+}
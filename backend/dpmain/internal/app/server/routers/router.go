@@ -6,7 +6,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "oip/dpmain/docs"
 	"oip/dpmain/internal/app/server/handlers/account"
+	"oip/dpmain/internal/app/server/handlers/contract"
 	"oip/dpmain/internal/app/server/handlers/order"
+	"oip/dpmain/internal/app/server/handlers/product"
 	"oip/dpmain/internal/app/server/middlewares"
 )
 
@@ -14,6 +16,8 @@ import (
 func SetupRoutes(
 	orderHandler *order.OrderHandler,
 	accountHandler *account.AccountHandler,
+	contractHandler *contract.ContractHandler,
+	productHandler *product.ProductHandler,
 ) *gin.Engine {
 	r := gin.Default()
 
@@ -31,12 +35,28 @@ func SetupRoutes(
 		{
 			accounts.POST("", accountHandler.Create)
 			accounts.GET("/:id", accountHandler.Get)
+			accounts.PUT("/:id/settings", accountHandler.UpdateSettings)
+			accounts.GET("/:id/contracts", contractHandler.List)
+			accounts.POST("/:id/contracts", contractHandler.Create)
+			accounts.GET("/:id/contracts/:contract_id", contractHandler.Get)
+			accounts.PUT("/:id/contracts/:contract_id", contractHandler.Update)
+			accounts.DELETE("/:id/contracts/:contract_id", contractHandler.Delete)
+			accounts.GET("/:id/products", productHandler.List)
+			accounts.POST("/:id/products", productHandler.Create)
+			accounts.POST("/:id/products/import", productHandler.Import)
+			accounts.GET("/:id/products/:product_id", productHandler.Get)
+			accounts.PUT("/:id/products/:product_id", productHandler.Update)
+			accounts.DELETE("/:id/products/:product_id", productHandler.Delete)
+			accounts.GET("/:id/savings-report", orderHandler.SavingsReport)
 		}
 
 		orders := v1.Group("/orders")
 		{
 			orders.POST("", orderHandler.Create)
 			orders.GET("/:id", orderHandler.Get)
+			orders.POST("/:id/diagnose", orderHandler.Rediagnose)
+			orders.GET("/:id/diagnoses", orderHandler.ListDiagnoses)
+			orders.GET("/:id/diagnoses/diff", orderHandler.DiffDiagnoses)
 		}
 	}
 
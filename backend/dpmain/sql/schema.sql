@@ -10,6 +10,7 @@ CREATE TABLE IF NOT EXISTS accounts (
     id BIGINT PRIMARY KEY COMMENT '账号ID（分布式ID）',
     name VARCHAR(255) NOT NULL COMMENT '账号名称',
     email VARCHAR(255) NOT NULL UNIQUE COMMENT '邮箱地址',
+    settings JSON COMMENT '账号级设置（默认诊断器等）',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     INDEX idx_email (email)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='账号表';
@@ -23,8 +24,11 @@ CREATE TABLE IF NOT EXISTS orders (
     account_id BIGINT NOT NULL COMMENT '账号ID',
     merchant_order_no VARCHAR(255) NOT NULL COMMENT '商户订单号',
     shipment JSON NOT NULL COMMENT '货件信息（包含发件地址、收件地址、包裹详情）',
+    diagnose_options JSON COMMENT '诊断选项（诊断器选择等，下单时确定）',
     status VARCHAR(50) NOT NULL COMMENT '订单状态: DIAGNOSING/DIAGNOSED/FAILED',
     diagnose_result JSON COMMENT '诊断结果（包含诊断项列表）',
+    diagnose_trace JSON COMMENT '诊断执行轨迹（仅 explain 模式诊断时写入，与诊断结果一一对应）',
+    diagnose_request_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '最近一次下发的诊断任务 request_id（只接受该任务的回调）',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
 
@@ -34,3 +38,78 @@ CREATE TABLE IF NOT EXISTS orders (
     INDEX idx_created_at (created_at),
     UNIQUE KEY uk_account_merchant (account_id, merchant_order_no) COMMENT '防止重复订单'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='订单表';
+
+-- ============================================
+-- Table: rate_contracts
+-- 说明: 账号协议价表（折扣、固定运费、燃油附加费）
+-- 注意: service 为空串表示对该承运商全部服务生效
+-- ============================================
+CREATE TABLE IF NOT EXISTS rate_contracts (
+    id BIGINT PRIMARY KEY COMMENT '合同ID（分布式ID）',
+    account_id BIGINT NOT NULL COMMENT '账号ID',
+    carrier VARCHAR(64) NOT NULL COMMENT '承运商',
+    service VARCHAR(64) NOT NULL DEFAULT '' COMMENT '服务（空串表示全部服务）',
+    discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0 COMMENT '基础运费折扣百分比',
+    fixed_fee_amount DECIMAL(12,2) NULL COMMENT '固定基础运费（设置后忽略折扣）',
+    fixed_fee_currency VARCHAR(3) NOT NULL DEFAULT '' COMMENT '固定基础运费币种',
+    fuel_surcharge_percent DECIMAL(5,2) NULL COMMENT '燃油附加费百分比（NULL 表示不收取）',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    UNIQUE KEY uk_account_carrier_service (account_id, carrier, service) COMMENT '同一承运商服务只允许一份合同'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='账号协议价表';
+
+-- ============================================
+-- Table: order_diagnoses
+-- 说明: 订单诊断版本表（每次诊断回调追加一条，记录产生结果的引擎与规则/费率卡版本）
+-- 注意: 同一回调重复投递按 request_id 去重
+-- ============================================
+CREATE TABLE IF NOT EXISTS order_diagnoses (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT COMMENT '自增ID',
+    order_id VARCHAR(64) NOT NULL COMMENT '订单ID',
+    version INT NOT NULL COMMENT '诊断版本（订单内从 1 开始递增）',
+    request_id VARCHAR(64) NOT NULL COMMENT '诊断回调 request_id',
+    status VARCHAR(16) NOT NULL COMMENT '诊断状态: DIAGNOSED/FAILED',
+    engine_version VARCHAR(64) NOT NULL DEFAULT '' COMMENT '诊断引擎版本',
+    diagnoser_versions JSON COMMENT '各诊断项的规则/费率卡版本（诊断类型 → 版本）',
+    diagnose_result JSON COMMENT '诊断结果（不含执行轨迹，失败时为空）',
+    error_message VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '失败原因',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+
+    UNIQUE KEY uk_order_version (order_id, version) COMMENT '订单内版本号唯一',
+    UNIQUE KEY uk_order_request (order_id, request_id) COMMENT '回调重复投递幂等'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='订单诊断版本表';
+
+-- ============================================
+-- Table: products
+-- 说明: 账号商品目录（SKU 主数据：重量、尺寸、HS 编码、原产国与电池信息）
+-- 注意: 下单时按 SKU 补全订单商品缺失的字段
+-- ============================================
+CREATE TABLE IF NOT EXISTS products (
+    id BIGINT PRIMARY KEY COMMENT '商品ID（分布式ID）',
+    account_id BIGINT NOT NULL COMMENT '账号ID',
+    sku VARCHAR(128) NOT NULL COMMENT 'SKU',
+    description VARCHAR(255) NOT NULL DEFAULT '' COMMENT '品名',
+    weight_value DECIMAL(10,3) NULL COMMENT '单件重量',
+    weight_unit VARCHAR(8) NOT NULL DEFAULT '' COMMENT '重量单位 kg/g/lb/oz',
+    dimension_width DECIMAL(10,2) NULL COMMENT '单件尺寸-宽',
+    dimension_height DECIMAL(10,2) NULL COMMENT '单件尺寸-高',
+    dimension_depth DECIMAL(10,2) NULL COMMENT '单件尺寸-深',
+    dimension_unit VARCHAR(8) NOT NULL DEFAULT '' COMMENT '尺寸单位 cm/mm/m/in',
+    hs_code VARCHAR(16) NOT NULL DEFAULT '' COMMENT '海关编码',
+    origin_country VARCHAR(3) NOT NULL DEFAULT '' COMMENT '原产国',
+    battery_chemistry VARCHAR(16) NOT NULL DEFAULT '' COMMENT '电池类型 LITHIUM_ION/LITHIUM_METAL/OTHER（空串表示不含电池）',
+    battery_packing VARCHAR(32) NOT NULL DEFAULT '' COMMENT '电池包装方式 STANDALONE/PACKED_WITH_EQUIPMENT/CONTAINED_IN_EQUIPMENT',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    UNIQUE KEY uk_account_sku (account_id, sku) COMMENT '账号内 SKU 唯一'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='账号商品目录表';
+
+-- ============================================
+-- 增量变更（已有库执行）
+-- ============================================
+-- ALTER TABLE accounts ADD COLUMN settings JSON COMMENT '账号级设置（默认诊断器等）' AFTER email;
+-- ALTER TABLE orders ADD COLUMN diagnose_options JSON COMMENT '诊断选项（诊断器选择等，下单时确定）' AFTER shipment;
+-- ALTER TABLE orders ADD COLUMN diagnose_trace JSON COMMENT '诊断执行轨迹（仅 explain 模式诊断时写入，与诊断结果一一对应）' AFTER diagnose_result;
+-- ALTER TABLE orders ADD COLUMN diagnose_request_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '最近一次下发的诊断任务 request_id（只接受该任务的回调）' AFTER diagnose_trace;
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"oip/dpsync/internal/business/rating"
+)
+
+// SandboxConfig 模拟承运商配置
+type SandboxConfig struct {
+	Carriers []CarrierBehavior `json:"carriers"`
+}
+
+// CarrierBehavior 单个模拟承运商的费率卡与行为
+type CarrierBehavior struct {
+	RateCard  *rating.RateCard `json:"rate_card"`
+	LatencyMs int              `json:"latency_ms"` // 固定延迟
+	JitterMs  int              `json:"jitter_ms"`  // 随机附加延迟上限
+	ErrorRate float64          `json:"error_rate"` // 随机返回 503 的概率（0~1）
+}
+
+// defaultSandboxConfig 默认配置：内置费率卡，轻微延迟，无错误
+func defaultSandboxConfig() *SandboxConfig {
+	cfg := &SandboxConfig{}
+	for _, card := range rating.DefaultRateCards() {
+		cfg.Carriers = append(cfg.Carriers, CarrierBehavior{
+			RateCard:  card,
+			LatencyMs: 100,
+			JitterMs:  50,
+		})
+	}
+	return cfg
+}
+
+// loadSandboxConfig 从 JSON 文件加载配置
+func loadSandboxConfig(path string) (*SandboxConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sandbox config failed: %w", err)
+	}
+
+	var cfg SandboxConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal sandbox config failed: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// validate 校验配置
+func (c *SandboxConfig) validate() error {
+	if len(c.Carriers) == 0 {
+		return fmt.Errorf("at least one carrier is required")
+	}
+	for i, carrier := range c.Carriers {
+		if carrier.RateCard == nil {
+			return fmt.Errorf("carriers[%d].rate_card is required", i)
+		}
+		if err := carrier.RateCard.Validate(); err != nil {
+			return err
+		}
+		if carrier.LatencyMs < 0 || carrier.JitterMs < 0 {
+			return fmt.Errorf("carriers[%d]: latency cannot be negative", i)
+		}
+		if carrier.ErrorRate < 0 || carrier.ErrorRate > 1 {
+			return fmt.Errorf("carriers[%d]: error_rate must be between 0 and 1", i)
+		}
+	}
+	return nil
+}
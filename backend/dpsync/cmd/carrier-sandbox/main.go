@@ -0,0 +1,79 @@
+// carrier-sandbox 模拟承运商报价接口，用于离线联调与测试 dpsync 的 HTTP 承运商适配器
+//
+// 用法：
+//
+//	go run ./cmd/carrier-sandbox -addr :8095
+//	go run ./cmd/carrier-sandbox -config ./config/carrier_sandbox.json -error-rate 0.2 -latency 3s
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var (
+	addr       = flag.String("addr", ":8095", "监听地址")
+	configPath = flag.String("config", "", "模拟承运商配置文件（为空时使用内置费率卡）")
+	latency    = flag.Duration("latency", -1, "覆盖所有承运商的固定延迟（如 500ms）")
+	errorRate  = flag.Float64("error-rate", -1, "覆盖所有承运商的故障注入概率（0~1）")
+	seed       = flag.Int64("seed", time.Now().UnixNano(), "随机种子（延迟抖动与故障注入）")
+)
+
+func main() {
+	flag.Parse()
+
+	// 1. 加载配置
+	cfg := defaultSandboxConfig()
+	if *configPath != "" {
+		loaded, err := loadSandboxConfig(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		cfg = loaded
+	}
+
+	// 2. 命令行覆盖
+	for i := range cfg.Carriers {
+		if *latency >= 0 {
+			cfg.Carriers[i].LatencyMs = int(latency.Milliseconds())
+			cfg.Carriers[i].JitterMs = 0
+		}
+		if *errorRate >= 0 {
+			cfg.Carriers[i].ErrorRate = *errorRate
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("Config validation failed: %v", err)
+	}
+
+	// 3. 启动服务
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newSandboxServer(cfg, *seed).routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Carrier sandbox listening on %s (%d carriers)", *addr, len(cfg.Carriers))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Sandbox serve failed: %v", err)
+		}
+	}()
+
+	// 4. 等待退出信号
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+	log.Println("Carrier sandbox stopped")
+}
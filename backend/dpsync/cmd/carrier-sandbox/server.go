@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/rating"
+)
+
+// sandboxServer 模拟承运商报价接口
+type sandboxServer struct {
+	carriers map[string]CarrierBehavior // key: 大写承运商名称
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newSandboxServer 创建模拟服务
+func newSandboxServer(cfg *SandboxConfig, seed int64) *sandboxServer {
+	carriers := make(map[string]CarrierBehavior, len(cfg.Carriers))
+	for _, carrier := range cfg.Carriers {
+		carriers[strings.ToUpper(carrier.RateCard.Carrier)] = carrier
+	}
+	return &sandboxServer{
+		carriers: carriers,
+		rng:      rand.New(rand.NewSource(seed)),
+	}
+}
+
+// routes 注册路由
+//
+//	GET  /healthz
+//	GET  /v1/carriers                          查询模拟承运商及费率卡
+//	POST /v1/carriers/{carrier}/rates          报价
+//	POST /v1/carriers/{carrier}/availability   服务可用性
+func (s *sandboxServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("/v1/carriers", s.handleListCarriers)
+	mux.HandleFunc("/v1/carriers/", s.handleCarrier)
+	return mux
+}
+
+// handleListCarriers 查询模拟承运商
+func (s *sandboxServer) handleListCarriers(w http.ResponseWriter, r *http.Request) {
+	cards := make([]*rating.RateCard, 0, len(s.carriers))
+	for _, carrier := range s.carriers {
+		cards = append(cards, carrier.RateCard)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		return cards[i].Carrier < cards[j].Carrier
+	})
+	writeJSON(w, http.StatusOK, cards)
+}
+
+// handleCarrier 报价 / 可用性
+func (s *sandboxServer) handleCarrier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// /v1/carriers/{carrier}/{action}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/carriers/"), "/")
+	if len(parts) != 2 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	carrierName, action := parts[0], parts[1]
+
+	behavior, ok := s.carriers[strings.ToUpper(carrierName)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown carrier: "+carrierName)
+		return
+	}
+
+	var req struct {
+		Shipment   json.RawMessage `json:"shipment"`
+		DistanceKm *float64        `json:"distance_km"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	shipment, err := model.DecodeShipment(req.Shipment)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 模拟延迟（客户端断开时提前结束）
+	select {
+	case <-time.After(s.latency(behavior)):
+	case <-r.Context().Done():
+		return
+	}
+
+	// 模拟故障
+	if s.shouldFail(behavior) {
+		log.Printf("[Sandbox] %s %s: injected failure", behavior.RateCard.Carrier, action)
+		writeError(w, http.StatusServiceUnavailable, "sandbox injected failure")
+		return
+	}
+
+	card := behavior.RateCard
+	switch action {
+	case "rates":
+		writeJSON(w, http.StatusOK, rating.QuoteResponse{
+			Carrier: card.Carrier,
+			Quotes:  card.Quote(shipment, req.DistanceKm),
+		})
+	case "availability":
+		writeJSON(w, http.StatusOK, rating.AvailabilityResponse{
+			Carrier:  card.Carrier,
+			Services: card.Availability(shipment),
+		})
+	default:
+		writeError(w, http.StatusNotFound, "unknown action: "+action)
+	}
+}
+
+// latency 本次请求的模拟延迟
+func (s *sandboxServer) latency(behavior CarrierBehavior) time.Duration {
+	latency := time.Duration(behavior.LatencyMs) * time.Millisecond
+	if behavior.JitterMs > 0 {
+		s.mu.Lock()
+		latency += time.Duration(s.rng.Intn(behavior.JitterMs+1)) * time.Millisecond
+		s.mu.Unlock()
+	}
+	return latency
+}
+
+// shouldFail 是否注入故障
+func (s *sandboxServer) shouldFail(behavior CarrierBehavior) bool {
+	if behavior.ErrorRate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < behavior.ErrorRate
+}
+
+// writeJSON 输出 JSON
+func writeJSON(w http.ResponseWriter, httpCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError 错误响应（与 rating.HTTPAdapter 约定的错误格式一致）
+func writeError(w http.ResponseWriter, httpCode int, message string) {
+	writeJSON(w, httpCode, rating.ErrorResponse{Error: message})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/rating"
+)
+
+func TestQuoterAgainstSandbox(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	// 沙箱与降级费率卡使用不同价格，用于区分报价来源
+	sandboxCard := &rating.RateCard{
+		Carrier:  "FedEx",
+		Version:  "sandbox",
+		Currency: "USD",
+		Services: []rating.ServiceRate{{Service: "Ground", BaseRate: 20, TransitDays: 3}},
+	}
+	fallbackCard := &rating.RateCard{
+		Carrier:  "FedEx",
+		Version:  "fallback",
+		Currency: "USD",
+		Services: []rating.ServiceRate{{Service: "Ground", BaseRate: 10, TransitDays: 3}},
+	}
+
+	tests := []struct {
+		name         string
+		carrier      string // 报价器请求的承运商
+		behavior     CarrierBehavior
+		withFallback bool
+		wantAmount   float64 // 0 表示不应有报价
+		wantFallback bool
+		wantError    string // 期望错误包含的内容（为空表示无错误）
+	}{
+		{
+			name:       "healthy carrier",
+			carrier:    "FedEx",
+			behavior:   CarrierBehavior{RateCard: sandboxCard},
+			wantAmount: 20,
+		},
+		{
+			name:         "injected failure falls back",
+			carrier:      "FedEx",
+			behavior:     CarrierBehavior{RateCard: sandboxCard, ErrorRate: 1},
+			withFallback: true,
+			wantAmount:   10,
+			wantFallback: true,
+			wantError:    "returned 503: sandbox injected failure",
+		},
+		{
+			name:         "timeout falls back",
+			carrier:      "FedEx",
+			behavior:     CarrierBehavior{RateCard: sandboxCard, LatencyMs: 1000},
+			withFallback: true,
+			wantAmount:   10,
+			wantFallback: true,
+			wantError:    "quote timeout after 100ms",
+		},
+		{
+			name:      "injected failure without fallback",
+			carrier:   "FedEx",
+			behavior:  CarrierBehavior{RateCard: sandboxCard, ErrorRate: 1},
+			wantError: "returned 503: sandbox injected failure",
+		},
+		{
+			name:      "unknown carrier",
+			carrier:   "DHL",
+			behavior:  CarrierBehavior{RateCard: sandboxCard},
+			wantError: "returned 404: unknown carrier: DHL",
+		},
+	}
+
+	req := &rating.QuoteRequest{Shipment: mustDecodeShipment(t)}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(newSandboxServer(&SandboxConfig{Carriers: []CarrierBehavior{tt.behavior}}, 1).routes())
+			defer server.Close()
+
+			quoter := rating.NewQuoter(timeout)
+			var fallback rating.CarrierAdapter
+			if tt.withFallback {
+				fallback = rating.NewStaticAdapter(fallbackCard)
+			}
+			quoter.Add(rating.NewHTTPAdapter(tt.carrier, server.URL, server.Client()), fallback)
+
+			results := quoter.QuoteAll(context.Background(), req)
+			if len(results) != 1 {
+				t.Fatalf("QuoteAll() returned %d results, want 1", len(results))
+			}
+			got := results[0]
+
+			if got.Fallback != tt.wantFallback {
+				t.Errorf("Fallback = %v, want %v", got.Fallback, tt.wantFallback)
+			}
+			if tt.wantError == "" && got.Error != "" {
+				t.Errorf("Error = %q, want empty", got.Error)
+			}
+			if tt.wantError != "" && !strings.Contains(got.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", got.Error, tt.wantError)
+			}
+
+			if tt.wantAmount == 0 {
+				if len(got.Quotes) != 0 {
+					t.Errorf("Quotes = %+v, want none", got.Quotes)
+				}
+				return
+			}
+			if len(got.Quotes) != 1 || got.Quotes[0].Amount != tt.wantAmount {
+				t.Errorf("Quotes = %+v, want one quote of %v", got.Quotes, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func mustDecodeShipment(t *testing.T) *model.Shipment {
+	t.Helper()
+	shipment, err := model.DecodeShipment([]byte(`{"ship_from":{"country":"US","postal_code":"10001"},"ship_to":{"country":"US","postal_code":"94105"},"parcels":[{"weight":{"value":1.5,"unit":"kg"}}]}`))
+	if err != nil {
+		t.Fatalf("DecodeShipment: %v", err)
+	}
+	return shipment
+}
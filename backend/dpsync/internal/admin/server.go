@@ -0,0 +1,292 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/order/diagnose/services"
+	"oip/dpsync/internal/business/shadow"
+	"oip/dpsync/pkg/logger"
+)
+
+// defaultShadowResultsLimit 影子诊断结果查询默认返回条数
+const defaultShadowResultsLimit = 50
+
+// maxBodyBytes 替换参考数据的请求体上限
+const maxBodyBytes = 8 << 20
+
+// tokenHeader 写操作携带共享密钥的请求头
+const tokenHeader = "X-Admin-Token"
+
+// Server 管理接口 HTTP 服务（汇率表与拒绝往来方名单热更新、影子诊断统计等运维操作）
+// 查询接口不鉴权（依赖只监听本机地址）；替换参考数据等写操作须携带与配置一致的共享密钥
+type Server struct {
+	httpServer *http.Server
+	token      string // 写操作共享密钥（为空时拒绝全部写操作）
+	deps       *services.Dependencies
+	shadows    *services.ShadowRunner // 可选，未配置影子诊断器时为 nil
+	logger     logger.Logger
+}
+
+// NewServer 创建管理接口服务
+func NewServer(addr, token string, deps *services.Dependencies, shadows *services.ShadowRunner, log logger.Logger) *Server {
+	s := &Server{
+		token:   token,
+		deps:    deps,
+		shadows: shadows,
+		logger:  log,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/fx/rates", s.handleFXRates)
+	mux.HandleFunc("/admin/screening/list", s.handleScreeningList)
+	mux.HandleFunc("/admin/screening/reload", s.handleScreeningReload)
+	mux.HandleFunc("/admin/shadow/stats", s.handleShadowStats)
+	mux.HandleFunc("/admin/shadow/results", s.handleShadowResults)
+
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.authorize(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	return s
+}
+
+// Start 启动监听（非阻塞）
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("admin server listen failed: %w", err)
+	}
+
+	s.logger.Infof(ctx, "[Admin] Listening on %s", listener.Addr().String())
+	if s.token == "" {
+		s.logger.Warnf(ctx, "[Admin] admin.token is not configured, write operations are disabled")
+	}
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Errorf(ctx, "[Admin] Serve error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown 优雅关闭
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// authorize 写操作鉴权：GET/HEAD 直接放行，其余方法须携带与配置一致的 X-Admin-Token
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if s.token == "" {
+			writeError(w, http.StatusForbidden, model.ResponseTypeUnauthorized, "write operations are disabled: admin token not configured")
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(tokenHeader)), []byte(s.token)) != 1 {
+			s.logger.Warnf(r.Context(), "[Admin] Rejected unauthorized %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			writeError(w, http.StatusUnauthorized, model.ResponseTypeUnauthorized, "missing or invalid "+tokenHeader)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleFXRates 汇率表查询与更新
+// GET  /admin/fx/rates  查询当前汇率表
+// PUT  /admin/fx/rates  整表替换（body 为 fx.RateTable JSON）
+func (s *Server) handleFXRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeOK(w, s.deps.FX.Table())
+
+	case http.MethodPut:
+		if !replaceData(w, r, "rate table", s.deps.FX.Update) {
+			return
+		}
+
+		current := s.deps.FX.Table()
+		s.logger.Infof(r.Context(), "[Admin] FX rate table updated: version=%s, currencies=%d", current.Version, len(current.Rates))
+		writeOK(w, current)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, model.ResponseTypeValidationError, "method not allowed")
+	}
+}
+
+// handleScreeningList 拒绝往来方名单查询与更新
+// GET  /admin/screening/list  查询当前名单
+// PUT  /admin/screening/list  整体替换（body 为 screening.List JSON）
+func (s *Server) handleScreeningList(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeOK(w, s.deps.Screening.List())
+
+	case http.MethodPut:
+		if !replaceData(w, r, "denied-party list", s.deps.Screening.Update) {
+			return
+		}
+
+		current := s.deps.Screening.List()
+		s.logger.Infof(r.Context(), "[Admin] Denied-party list updated: version=%s, entries=%d", current.Version, len(current.Entries))
+		writeOK(w, current)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, model.ResponseTypeValidationError, "method not allowed")
+	}
+}
+
+// handleScreeningReload 从 screening_file 重新加载拒绝往来方名单
+// POST /admin/screening/reload  名单文件更新后调用（加载失败时保留当前名单）
+func (s *Server) handleScreeningReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, model.ResponseTypeValidationError, "method not allowed")
+		return
+	}
+
+	current, err := s.deps.Screening.Reload()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, model.ResponseTypeValidationError, err.Error())
+		return
+	}
+
+	s.logger.Infof(r.Context(), "[Admin] Denied-party list reloaded: version=%s, entries=%d", current.Version, len(current.Entries))
+	writeOK(w, current)
+}
+
+// handleShadowStats 影子诊断器一致性统计
+// GET /admin/shadow/stats  各影子诊断器的线上/候选版本、一致与不一致次数、一致率以及各结果字段的不一致次数
+func (s *Server) handleShadowStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, model.ResponseTypeValidationError, "method not allowed")
+		return
+	}
+	if s.shadows == nil {
+		writeOK(w, []*services.ShadowStats{})
+		return
+	}
+
+	stats, err := s.shadows.Stats(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ResponseTypeInternalError, err.Error())
+		return
+	}
+	writeOK(w, stats)
+}
+
+// handleShadowResults 影子诊断最近结果
+// GET /admin/shadow/results?name=risk-2026-11&limit=50&outcome=DISAGREED
+// outcome 为空时返回全部结论；按 outcome 过滤时在最近 limit 条内过滤
+func (s *Server) handleShadowResults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, model.ResponseTypeValidationError, "method not allowed")
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if s.shadows == nil || !s.shadows.Has(name) {
+		writeError(w, http.StatusNotFound, model.ResponseTypeValidationError, "shadow diagnoser not found: "+name)
+		return
+	}
+
+	limit := defaultShadowResultsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, model.ResponseTypeValidationError, "invalid limit: "+value)
+			return
+		}
+		limit = parsed
+	}
+
+	outcome := r.URL.Query().Get("outcome")
+	switch outcome {
+	case "", shadow.OutcomeAgreed, shadow.OutcomeDisagreed, shadow.OutcomeFailed:
+	default:
+		writeError(w, http.StatusBadRequest, model.ResponseTypeValidationError, "invalid outcome: "+outcome)
+		return
+	}
+
+	results, err := s.shadows.Recent(r.Context(), name, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, model.ResponseTypeInternalError, err.Error())
+		return
+	}
+	if outcome != "" {
+		filtered := make([]*shadow.Result, 0, len(results))
+		for _, result := range results {
+			if result.Outcome == outcome {
+				filtered = append(filtered, result)
+			}
+		}
+		results = filtered
+	}
+	writeOK(w, results)
+}
+
+// replaceData 整体替换参考数据：解码请求体（不超过 maxBodyBytes）后交给 update 校验并替换
+// 解码或校验失败时已写出错误响应并返回 false，当前数据保持不变
+func replaceData[T any](w http.ResponseWriter, r *http.Request, what string, update func(*T) error) bool {
+	var data T
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&data); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, model.ResponseTypeValidationError, fmt.Sprintf("%s exceeds %d bytes", what, maxBodyBytes))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, model.ResponseTypeValidationError, "invalid "+what+": "+err.Error())
+		return false
+	}
+	if err := update(&data); err != nil {
+		writeError(w, http.StatusBadRequest, model.ResponseTypeValidationError, err.Error())
+		return false
+	}
+	return true
+}
+
+// writeOK 成功响应
+func writeOK(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, model.Response{
+		Meta: model.MetaInfo{
+			Code:    http.StatusOK,
+			Type:    model.ResponseTypeOK,
+			Message: "OK",
+		},
+		Data: data,
+	})
+}
+
+// writeError 错误响应
+func writeError(w http.ResponseWriter, httpCode int, errType string, message string) {
+	writeJSON(w, httpCode, model.Response{
+		Meta: model.MetaInfo{
+			Code:    httpCode,
+			Type:    errType,
+			Message: message,
+		},
+	})
+}
+
+// writeJSON 输出 JSON
+func writeJSON(w http.ResponseWriter, httpCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,175 @@
+package carbon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// Factors 碳排放估算参考数据：各运输方式排放因子与承运商服务的运输方式
+type Factors struct {
+	Version     string                 `json:"version"`      // 数据版本（写入诊断结果用于审计）
+	Modes       map[string]*ModeFactor `json:"modes"`        // key: 运输方式（model.TransportMode*）
+	Services    []ServiceMode          `json:"services"`     // 承运商服务的运输方式（未登记的服务按名称关键词判定）
+	AirKeywords []string               `json:"air_keywords"` // 服务名称包含任一关键词时按航空计算（忽略大小写），否则按陆运计算
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// ModeFactor 运输方式排放因子
+type ModeFactor struct {
+	GramsPerTonneKm float64 `json:"grams_per_tonne_km"` // 每吨公里 CO2e 排放（克，油井到车轮口径）
+	DistanceFactor  float64 `json:"distance_factor"`    // 实际运输距离相对大圆距离的修正系数（>= 1，为 0 时按 1 计）
+}
+
+// ServiceMode 承运商服务对应的运输方式
+type ServiceMode struct {
+	Carrier string `json:"carrier"` // 承运商（大小写不敏感）
+	Service string `json:"service"` // 服务名称（大小写不敏感）
+	Mode    string `json:"mode"`    // AIR/GROUND
+}
+
+// modes 支持的运输方式
+var modes = []string{model.TransportModeAir, model.TransportModeGround}
+
+// DefaultFactors 内置排放因子（未配置数据文件时使用，参考 GLEC 框架包裹运输默认值）
+func DefaultFactors() *Factors {
+	return &Factors{
+		Version: "builtin-2025-12",
+		Modes: map[string]*ModeFactor{
+			model.TransportModeAir:    {GramsPerTonneKm: 1130, DistanceFactor: 1.1},
+			model.TransportModeGround: {GramsPerTonneKm: 110, DistanceFactor: 1.2},
+		},
+		Services: []ServiceMode{
+			{Carrier: "FedEx", Service: "Ground", Mode: model.TransportModeGround},
+			{Carrier: "FedEx", Service: "2Day", Mode: model.TransportModeAir},
+			{Carrier: "UPS", Service: "Ground", Mode: model.TransportModeGround},
+			{Carrier: "USPS", Service: "Priority", Mode: model.TransportModeAir},
+			{Carrier: "DHL", Service: "Express", Mode: model.TransportModeAir},
+		},
+		AirKeywords: []string{"air", "express", "overnight", "next day", "2day", "priority"},
+		UpdatedAt:   time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// LoadFactors 从 JSON 文件加载排放因子
+func LoadFactors(path string) (*Factors, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read carbon factors failed: %w", err)
+	}
+
+	var factors Factors
+	if err := json.Unmarshal(data, &factors); err != nil {
+		return nil, fmt.Errorf("unmarshal carbon factors failed: %w", err)
+	}
+
+	if err := factors.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &factors, nil
+}
+
+// Normalize 校验并归一化排放因子（所有运输方式均需配置，服务映射只能引用已知运输方式）
+func (f *Factors) Normalize() error {
+	if f.Version == "" {
+		return fmt.Errorf("carbon factors version is required")
+	}
+
+	normalized := make(map[string]*ModeFactor, len(f.Modes))
+	for mode, factor := range f.Modes {
+		mode = strings.ToUpper(strings.TrimSpace(mode))
+		if !isMode(mode) {
+			return fmt.Errorf("unknown transport mode: %s (available: %v)", mode, modes)
+		}
+		if factor == nil || factor.GramsPerTonneKm <= 0 {
+			return fmt.Errorf("modes.%s: grams_per_tonne_km must be positive", mode)
+		}
+		if factor.DistanceFactor == 0 {
+			factor.DistanceFactor = 1
+		}
+		if factor.DistanceFactor < 1 {
+			return fmt.Errorf("modes.%s: distance_factor cannot be less than 1", mode)
+		}
+		normalized[mode] = factor
+	}
+	for _, mode := range modes {
+		if normalized[mode] == nil {
+			return fmt.Errorf("emission factor for mode %s is required", mode)
+		}
+	}
+	f.Modes = normalized
+
+	for i := range f.Services {
+		service := &f.Services[i]
+		service.Carrier = strings.TrimSpace(service.Carrier)
+		service.Service = strings.TrimSpace(service.Service)
+		service.Mode = strings.ToUpper(strings.TrimSpace(service.Mode))
+		if service.Carrier == "" || service.Service == "" {
+			return fmt.Errorf("services[%d]: carrier and service are required", i)
+		}
+		if !isMode(service.Mode) {
+			return fmt.Errorf("services[%d]: unknown transport mode %s", i, service.Mode)
+		}
+	}
+
+	keywords := make([]string, 0, len(f.AirKeywords))
+	for _, keyword := range f.AirKeywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	f.AirKeywords = keywords
+
+	if f.UpdatedAt.IsZero() {
+		f.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝
+func (f *Factors) clone() *Factors {
+	copied := *f
+	copied.Modes = make(map[string]*ModeFactor, len(f.Modes))
+	for mode, factor := range f.Modes {
+		if factor != nil {
+			factorCopy := *factor
+			copied.Modes[mode] = &factorCopy
+		}
+	}
+	copied.Services = append([]ServiceMode(nil), f.Services...)
+	copied.AirKeywords = append([]string(nil), f.AirKeywords...)
+	return &copied
+}
+
+// Mode 承运商服务的运输方式（先查服务映射，未登记时按名称关键词判定）
+func (f *Factors) Mode(carrier, service string) string {
+	for _, mapping := range f.Services {
+		if strings.EqualFold(mapping.Carrier, carrier) && strings.EqualFold(mapping.Service, service) {
+			return mapping.Mode
+		}
+	}
+
+	name := strings.ToLower(service)
+	for _, keyword := range f.AirKeywords {
+		if strings.Contains(name, keyword) {
+			return model.TransportModeAir
+		}
+	}
+	return model.TransportModeGround
+}
+
+// isMode 判断是否为支持的运输方式
+func isMode(mode string) bool {
+	for _, m := range modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
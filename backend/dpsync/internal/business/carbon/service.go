@@ -0,0 +1,79 @@
+package carbon
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Estimate 单个承运商服务的排放估算
+type Estimate struct {
+	Mode       string  // AIR/GROUND
+	DistanceKm float64 // 修正后的运输距离
+	Co2eKg     float64 // CO2e 排放（千克，保留三位小数）
+}
+
+// Service 碳排放估算服务（并发安全，支持整体替换排放因子）
+type Service struct {
+	mu      sync.RWMutex
+	factors *Factors
+}
+
+// NewService 创建碳排放估算服务
+func NewService(factors *Factors) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(factors); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前排放因子
+func (s *Service) Update(factors *Factors) error {
+	if factors == nil {
+		return fmt.Errorf("carbon factors cannot be nil")
+	}
+
+	normalized := factors.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.factors = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Factors 返回当前排放因子副本
+func (s *Service) Factors() *Factors {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.factors.clone()
+}
+
+// Version 排放因子版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.factors.Version
+}
+
+// Estimate 按计费重与大圆距离估算承运商服务的 CO2e 排放
+// 排放 = 计费重（吨）× 大圆距离 × 距离修正系数 × 运输方式排放因子
+func (s *Service) Estimate(carrier, service string, weightKg, distanceKm float64) Estimate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mode := s.factors.Mode(carrier, service)
+	factor := s.factors.Modes[mode]
+	distance := distanceKm * factor.DistanceFactor
+	grams := weightKg / 1000 * distance * factor.GramsPerTonneKm
+
+	return Estimate{
+		Mode:       mode,
+		DistanceKm: math.Round(distance),
+		Co2eKg:     math.Round(grams) / 1000,
+	}
+}
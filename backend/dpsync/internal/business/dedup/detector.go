@@ -0,0 +1,137 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Match 疑似重复/拆单的近期订单
+type Match struct {
+	OrderID         string
+	MerchantOrderNo string
+	CreatedAt       time.Time
+	SharedSKUs      []string // 与当前订单共有的 SKU
+	Identical       bool     // SKU 集合完全相同（疑似重复下单），否则为部分重叠（疑似拆单）
+}
+
+// Detector 重复订单检测：时间窗口内同一账号、同一收件地址且 SKU 有交集的订单
+type Detector struct {
+	index  Index
+	window time.Duration
+}
+
+// NewDetector 创建重复订单检测器
+func NewDetector(index Index, window time.Duration) (*Detector, error) {
+	if index == nil {
+		return nil, fmt.Errorf("recent order index cannot be nil")
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("duplicate window must be positive")
+	}
+	return &Detector{index: index, window: window}, nil
+}
+
+// Close 释放近期订单索引占用的连接（索引不需要释放资源时不做任何事）
+func (d *Detector) Close() error {
+	if closer, ok := d.index.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Window 检测时间窗口
+func (d *Detector) Window() time.Duration {
+	return d.window
+}
+
+// Check 查找与当前订单疑似重复的近期订单，并将当前订单登记进索引
+// 窗口按下单时间前后双向计算（重新诊断较早的订单时也能关联到之后的重复单）；同一订单重复诊断不视为重复
+func (d *Detector) Check(ctx context.Context, entry Entry) ([]Match, error) {
+	entry.SKUs = normalizeSKUs(entry.SKUs)
+
+	recent, err := d.RecentOrders(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0)
+	if len(entry.SKUs) > 0 {
+		for _, other := range recent {
+			shared := intersect(entry.SKUs, other.SKUs)
+			if len(shared) == 0 {
+				continue
+			}
+			matches = append(matches, Match{
+				OrderID:         other.OrderID,
+				MerchantOrderNo: other.MerchantOrderNo,
+				CreatedAt:       other.CreatedAt,
+				SharedSKUs:      shared,
+				Identical:       len(shared) == len(entry.SKUs) && len(shared) == len(other.SKUs),
+			})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.Before(matches[j].CreatedAt)
+		}
+		return matches[i].OrderID < matches[j].OrderID
+	})
+
+	return matches, nil
+}
+
+// RecentOrders 返回时间窗口内同一账号、同一收件地址的其他订单（不要求 SKU 交集），并将当前订单登记进索引
+// 登记与查询是一个原子操作，同时重复提交的订单至少有一方能关联到另一方；同一订单被多个诊断器重复登记时覆盖写入，不影响结果
+func (d *Detector) RecentOrders(ctx context.Context, entry Entry) ([]Entry, error) {
+	entry.SKUs = normalizeSKUs(entry.SKUs)
+
+	recent, err := d.index.RecordAndList(ctx, entry, entry.CreatedAt.Add(-d.window), d.window)
+	if err != nil {
+		return nil, err
+	}
+
+	others := make([]Entry, 0, len(recent))
+	for _, other := range recent {
+		if other.OrderID == entry.OrderID || other.CreatedAt.Sub(entry.CreatedAt) > d.window {
+			continue
+		}
+		others = append(others, other)
+	}
+
+	return others, nil
+}
+
+// normalizeSKUs SKU 去空白、转大写、去重并排序
+func normalizeSKUs(skus []string) []string {
+	seen := make(map[string]bool, len(skus))
+	normalized := make([]string, 0, len(skus))
+	for _, sku := range skus {
+		s := strings.ToUpper(strings.TrimSpace(sku))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		normalized = append(normalized, s)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// intersect 两个已排序去重的 SKU 集合的交集
+func intersect(a, b []string) []string {
+	shared := make([]string, 0)
+	set := make(map[string]bool, len(b))
+	for _, sku := range b {
+		set[sku] = true
+	}
+	for _, sku := range a {
+		if set[sku] {
+			shared = append(shared, sku)
+		}
+	}
+	return shared
+}
@@ -0,0 +1,91 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Entry 近期订单索引条目
+type Entry struct {
+	OrderID         string    `json:"order_id"`
+	AccountID       int64     `json:"account_id"`
+	MerchantOrderNo string    `json:"merchant_order_no"`
+	AddressKey      string    `json:"address_key"` // 收件地址指纹（见 AddressKey）
+	SKUs            []string  `json:"skus"`        // 去重后的 SKU 集合
+	CreatedAt       time.Time `json:"created_at"`  // 下单时间
+}
+
+// Index 近期订单索引（按账号 + 收件地址指纹分组）
+type Index interface {
+	// RecordAndList 登记订单（同一订单重复登记时覆盖，ttl 后过期），并返回同一账号、同一地址指纹下 since 之后下单的订单（含刚登记的订单）
+	// 登记与读取须为一个原子操作：同时提交的两个订单中，后完成的一方一定能看到先完成的一方
+	RecordAndList(ctx context.Context, entry Entry, since time.Time, ttl time.Duration) ([]Entry, error)
+}
+
+// AddressKey 收件地址指纹：各字段去除空白与标点并转小写后拼接哈希
+// 只用于判断「同一收件人、同一地址」，大小写与标点写法差异不影响结果
+func AddressKey(fields ...string) string {
+	normalized := make([]string, 0, len(fields))
+	for _, field := range fields {
+		normalized = append(normalized, strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, field))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(normalized, "|")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// MemoryIndex 进程内近期订单索引（并发安全，仅适用于单实例部署或本地调试）
+type MemoryIndex struct {
+	mu      sync.Mutex
+	entries map[string]map[string]memoryEntry // key: 账号 + 地址指纹 → 订单 ID → 条目
+}
+
+// memoryEntry 带过期时间的索引条目
+type memoryEntry struct {
+	entry     Entry
+	expiresAt time.Time
+}
+
+// NewMemoryIndex 创建进程内近期订单索引
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		entries: make(map[string]map[string]memoryEntry),
+	}
+}
+
+// RecordAndList 登记订单并返回 since 之后下单的订单（全程持锁，顺带清理过期条目）
+func (m *MemoryIndex) RecordAndList(ctx context.Context, entry Entry, since time.Time, ttl time.Duration) ([]Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	key := groupKey(entry.AccountID, entry.AddressKey)
+	group, ok := m.entries[key]
+	if !ok {
+		group = make(map[string]memoryEntry)
+		m.entries[key] = group
+	}
+	group[entry.OrderID] = memoryEntry{entry: entry, expiresAt: now.Add(ttl)}
+
+	entries := make([]Entry, 0, len(group))
+	for orderID, item := range group {
+		if now.After(item.expiresAt) {
+			delete(group, orderID)
+			continue
+		}
+		if !item.entry.CreatedAt.Before(since) {
+			entries = append(entries, item.entry)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,85 @@
+package dedup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix 近期订单索引键前缀：{prefix}{account_id}:{address_key}，Hash 字段为订单 ID
+const redisKeyPrefix = "oip:recent_orders:"
+
+// RedisIndex 基于 Redis Hash 的近期订单索引（多 Worker 实例共享）
+// 同一地址的订单数量很少，按组整体读取后在进程内按时间过滤
+type RedisIndex struct {
+	client *redis.Client
+}
+
+// NewRedisIndex 创建 Redis 近期订单索引
+func NewRedisIndex(addr, password string, db int) (*RedisIndex, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisIndex{client: client}, nil
+}
+
+// RecordAndList 登记订单并返回 since 之后下单的订单
+// 登记与读取在同一个 MULTI/EXEC 事务内完成（HSET 后 HGETALL），并发登记的订单不会互相漏看；
+// 整组的过期时间随最新登记刷新，地址长期无新订单时由 Redis 自动回收；组内超出 ttl 的旧订单在事务后清理
+func (r *RedisIndex) RecordAndList(ctx context.Context, entry Entry, since time.Time, ttl time.Duration) ([]Entry, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshal recent order failed: %w", err)
+	}
+
+	key := redisKeyPrefix + groupKey(entry.AccountID, entry.AddressKey)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, entry.OrderID, data)
+	pipe.Expire(ctx, key, ttl)
+	all := pipe.HGetAll(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("record recent order failed: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(all.Val()))
+	expired := make([]string, 0)
+	cutoff := time.Now().Add(-ttl)
+	for orderID, value := range all.Val() {
+		var existing Entry
+		if err := json.Unmarshal([]byte(value), &existing); err != nil || existing.CreatedAt.Before(cutoff) {
+			expired = append(expired, orderID)
+			continue
+		}
+		if !existing.CreatedAt.Before(since) {
+			entries = append(entries, existing)
+		}
+	}
+
+	// 清理失败只影响索引大小，不影响本次结果
+	if len(expired) > 0 {
+		r.client.HDel(ctx, key, expired...)
+	}
+
+	return entries, nil
+}
+
+// Close 关闭 Redis 连接
+func (r *RedisIndex) Close() error {
+	return r.client.Close()
+}
+
+// groupKey 索引分组键
+func groupKey(accountID int64, addressKey string) string {
+	return strconv.FormatInt(accountID, 10) + ":" + addressKey
+}
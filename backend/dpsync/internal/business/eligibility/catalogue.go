@@ -0,0 +1,168 @@
+package eligibility
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Catalogue 承运商服务约束目录
+type Catalogue struct {
+	Version   string              `json:"version"` // 目录版本（写入诊断结果用于审计）
+	Services  []ServiceConstraint `json:"services"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// ServiceConstraint 单个承运商服务的承运约束（数值为 0 表示不限制）
+type ServiceConstraint struct {
+	Carrier              string   `json:"carrier"`
+	Service              string   `json:"service"`
+	MaxParcelWeightKg    float64  `json:"max_parcel_weight_kg,omitempty"`     // 单包裹最大重量
+	MaxLengthCm          float64  `json:"max_length_cm,omitempty"`            // 最长边
+	MaxLengthPlusGirthCm float64  `json:"max_length_plus_girth_cm,omitempty"` // 最长边 + 周长（2×(宽+高)）
+	OriginCountries      []string `json:"origin_countries,omitempty"`         // 允许的始发国，为空表示不限
+	DestinationCountries []string `json:"destination_countries,omitempty"`    // 允许的目的国，为空表示不限
+	AllowPOBox           bool     `json:"allow_po_box"`                       // 是否派送 PO Box
+	AllowDangerousGoods  bool     `json:"allow_dangerous_goods"`              // 是否承运危险品
+}
+
+// DefaultCatalogue 内置服务约束目录（与内置费率卡对应）
+func DefaultCatalogue() *Catalogue {
+	northAmerica := []string{"US", "CA"}
+	usDomestic := []string{"US", "PR", "GU", "VI", "AS", "MP"}
+
+	return &Catalogue{
+		Version: "builtin-2025-12",
+		Services: []ServiceConstraint{
+			{
+				Carrier:              "FedEx",
+				Service:              "Ground",
+				MaxParcelWeightKg:    68,
+				MaxLengthCm:          274,
+				MaxLengthPlusGirthCm: 419,
+				OriginCountries:      northAmerica,
+				DestinationCountries: northAmerica,
+				AllowPOBox:           false,
+				AllowDangerousGoods:  true, // 陆运可承运有限数量危险品
+			},
+			{
+				Carrier:              "UPS",
+				Service:              "Ground",
+				MaxParcelWeightKg:    70,
+				MaxLengthCm:          274,
+				MaxLengthPlusGirthCm: 419,
+				OriginCountries:      northAmerica,
+				DestinationCountries: northAmerica,
+				AllowPOBox:           false,
+				AllowDangerousGoods:  true,
+			},
+			{
+				Carrier:              "USPS",
+				Service:              "Priority",
+				MaxParcelWeightKg:    31.5,
+				MaxLengthPlusGirthCm: 274,
+				OriginCountries:      usDomestic,
+				DestinationCountries: usDomestic,
+				AllowPOBox:           true,
+				AllowDangerousGoods:  false,
+			},
+			{
+				Carrier:             "DHL",
+				Service:             "Express",
+				MaxParcelWeightKg:   70,
+				MaxLengthCm:         120,
+				AllowPOBox:          false,
+				AllowDangerousGoods: false,
+			},
+		},
+		UpdatedAt: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// LoadCatalogue 从 JSON 文件加载服务约束目录
+func LoadCatalogue(path string) (*Catalogue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read constraint catalogue failed: %w", err)
+	}
+
+	var catalogue Catalogue
+	if err := json.Unmarshal(data, &catalogue); err != nil {
+		return nil, fmt.Errorf("unmarshal constraint catalogue failed: %w", err)
+	}
+
+	if err := catalogue.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &catalogue, nil
+}
+
+// Normalize 校验并归一化目录（国家代码统一大写）
+func (c *Catalogue) Normalize() error {
+	if c.Version == "" {
+		return fmt.Errorf("constraint catalogue version is required")
+	}
+
+	seen := make(map[string]bool, len(c.Services))
+	for i := range c.Services {
+		s := &c.Services[i]
+		s.Carrier = strings.TrimSpace(s.Carrier)
+		s.Service = strings.TrimSpace(s.Service)
+		if s.Carrier == "" || s.Service == "" {
+			return fmt.Errorf("services[%d]: carrier and service are required", i)
+		}
+		if s.MaxParcelWeightKg < 0 || s.MaxLengthCm < 0 || s.MaxLengthPlusGirthCm < 0 {
+			return fmt.Errorf("services[%d]: limits cannot be negative", i)
+		}
+
+		key := serviceKey(s.Carrier, s.Service)
+		if seen[key] {
+			return fmt.Errorf("services[%d]: duplicate constraint for %s %s", i, s.Carrier, s.Service)
+		}
+		seen[key] = true
+
+		s.OriginCountries = upperAll(s.OriginCountries)
+		s.DestinationCountries = upperAll(s.DestinationCountries)
+	}
+
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝（对外返回副本，避免调用方修改内部状态）
+func (c *Catalogue) clone() *Catalogue {
+	services := make([]ServiceConstraint, len(c.Services))
+	for i, s := range c.Services {
+		s.OriginCountries = append([]string(nil), s.OriginCountries...)
+		s.DestinationCountries = append([]string(nil), s.DestinationCountries...)
+		services[i] = s
+	}
+	return &Catalogue{
+		Version:   c.Version,
+		Services:  services,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// serviceKey 服务查找键（大小写不敏感）
+func serviceKey(carrier, service string) string {
+	return strings.ToUpper(strings.TrimSpace(carrier)) + "|" + strings.ToUpper(strings.TrimSpace(service))
+}
+
+// upperAll 国家代码列表统一大写
+func upperAll(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		out = append(out, strings.ToUpper(strings.TrimSpace(item)))
+	}
+	return out
+}
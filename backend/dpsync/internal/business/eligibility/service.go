@@ -0,0 +1,150 @@
+package eligibility
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"oip/common/model"
+)
+
+// Facts 货件的派生事实（由调用方基于地址归一化、危险品识别等规则得出）
+type Facts struct {
+	Origin         string // 始发国（ISO 两位代码）
+	Destination    string // 目的国（ISO 两位代码）
+	POBox          bool   // 收件地址为 PO Box
+	DangerousGoods bool   // 商品含危险品
+}
+
+// Service 服务约束查询服务（并发安全，支持整体替换目录）
+type Service struct {
+	mu        sync.RWMutex
+	catalogue *Catalogue
+	index     map[string]*ServiceConstraint
+}
+
+// NewService 创建服务约束查询服务
+func NewService(catalogue *Catalogue) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(catalogue); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前目录
+func (s *Service) Update(catalogue *Catalogue) error {
+	if catalogue == nil {
+		return fmt.Errorf("constraint catalogue cannot be nil")
+	}
+
+	normalized := catalogue.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	index := make(map[string]*ServiceConstraint, len(normalized.Services))
+	for i := range normalized.Services {
+		constraint := &normalized.Services[i]
+		index[serviceKey(constraint.Carrier, constraint.Service)] = constraint
+	}
+
+	s.mu.Lock()
+	s.catalogue = normalized
+	s.index = index
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Catalogue 返回当前目录副本
+func (s *Service) Catalogue() *Catalogue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalogue.clone()
+}
+
+// Version 目录版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalogue.Version
+}
+
+// Check 检查承运商服务能否承运该货件，返回不满足的约束说明（为空表示可承运）
+// 目录中未登记的服务视为不受限制
+func (s *Service) Check(carrier, service string, shipment *model.Shipment, facts Facts) []string {
+	s.mu.RLock()
+	constraint, ok := s.index[serviceKey(carrier, service)]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	reasons := make([]string, 0)
+
+	if len(constraint.OriginCountries) > 0 && !contains(constraint.OriginCountries, facts.Origin) {
+		reasons = append(reasons, fmt.Sprintf("origin %s is not served", displayCountry(facts.Origin)))
+	}
+	if len(constraint.DestinationCountries) > 0 && !contains(constraint.DestinationCountries, facts.Destination) {
+		reasons = append(reasons, fmt.Sprintf("destination %s is not served", displayCountry(facts.Destination)))
+	}
+	if facts.POBox && !constraint.AllowPOBox {
+		reasons = append(reasons, "service does not deliver to PO Boxes")
+	}
+	if facts.DangerousGoods && !constraint.AllowDangerousGoods {
+		reasons = append(reasons, "service does not accept dangerous goods")
+	}
+
+	if shipment != nil {
+		for i, parcel := range shipment.Parcels {
+			reasons = append(reasons, checkParcel(constraint, i, parcel)...)
+		}
+	}
+
+	return reasons
+}
+
+// checkParcel 检查单个包裹的重量与尺寸
+func checkParcel(constraint *ServiceConstraint, index int, parcel *model.Parcel) []string {
+	reasons := make([]string, 0)
+
+	if weight := parcel.Weight.Kilograms(); constraint.MaxParcelWeightKg > 0 && weight > constraint.MaxParcelWeightKg {
+		reasons = append(reasons, fmt.Sprintf("parcel #%d weight %.2f kg exceeds limit %.2f kg", index+1, weight, constraint.MaxParcelWeightKg))
+	}
+
+	if parcel.Dimension == nil {
+		return reasons
+	}
+
+	width, height, depth := parcel.Dimension.Centimeters()
+	length := math.Max(width, math.Max(height, depth))
+	girth := 2 * (width + height + depth - length)
+
+	if constraint.MaxLengthCm > 0 && length > constraint.MaxLengthCm {
+		reasons = append(reasons, fmt.Sprintf("parcel #%d length %.1f cm exceeds limit %.1f cm", index+1, length, constraint.MaxLengthCm))
+	}
+	if constraint.MaxLengthPlusGirthCm > 0 && length+girth > constraint.MaxLengthPlusGirthCm {
+		reasons = append(reasons, fmt.Sprintf("parcel #%d length + girth %.1f cm exceeds limit %.1f cm", index+1, length+girth, constraint.MaxLengthPlusGirthCm))
+	}
+
+	return reasons
+}
+
+// contains 列表是否包含指定值
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// displayCountry 国家代码为空时的展示值
+func displayCountry(country string) string {
+	if country == "" {
+		return "(unknown)"
+	}
+	return country
+}
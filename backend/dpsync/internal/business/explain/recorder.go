@@ -0,0 +1,95 @@
+package explain
+
+import (
+	"context"
+	"sync"
+
+	"oip/common/model"
+)
+
+// Recorder 诊断器执行轨迹记录器（explain 模式下按诊断器创建，通过 Context 传递）
+// 所有方法对 nil 接收者安全：未开启 explain 时 FromContext 返回 nil，记录调用直接忽略
+// 诊断器超时后其 goroutine 仍可能继续写入，因此读写均加锁
+type Recorder struct {
+	mu    sync.Mutex
+	trace model.DiagnoserTrace
+}
+
+// NewRecorder 创建记录器
+func NewRecorder(diagnosisType string) *Recorder {
+	return &Recorder{
+		trace: model.DiagnoserTrace{
+			Type:   diagnosisType,
+			Inputs: make(map[string]interface{}),
+			Rules:  make([]model.TraceRule, 0),
+		},
+	}
+}
+
+// recorderKey Context 键
+type recorderKey struct{}
+
+// WithRecorder 将记录器放入 Context
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext 取出 Context 中的记录器（未开启 explain 时返回 nil）
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Enabled 是否正在记录（用于跳过只为轨迹准备的计算）
+func (r *Recorder) Enabled() bool {
+	return r != nil
+}
+
+// Input 记录诊断器使用的输入（同名输入以最后一次为准）
+func (r *Recorder) Input(name string, value interface{}) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trace.Inputs[name] = value
+}
+
+// Rule 记录一条规则的评估结果及中间值
+func (r *Recorder) Rule(rule string, matched bool, values map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trace.Rules = append(r.trace.Rules, model.TraceRule{
+		Rule:    rule,
+		Matched: matched,
+		Values:  values,
+	})
+}
+
+// RateRow 记录报价使用的费率卡行
+func (r *Recorder) RateRow(row model.TraceRateRow) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trace.RateRows = append(r.trace.RateRows, row)
+}
+
+// Trace 当前轨迹快照
+func (r *Recorder) Trace() model.DiagnoserTrace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trace := r.trace
+	trace.Inputs = make(map[string]interface{}, len(r.trace.Inputs))
+	for name, value := range r.trace.Inputs {
+		trace.Inputs[name] = value
+	}
+	trace.Rules = append(make([]model.TraceRule, 0, len(r.trace.Rules)), r.trace.Rules...)
+	trace.RateRows = append([]model.TraceRateRow(nil), r.trace.RateRows...)
+	return trace
+}
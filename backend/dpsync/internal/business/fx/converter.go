@@ -0,0 +1,121 @@
+package fx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"oip/common/model"
+)
+
+// Converter 汇率转换服务（并发安全，支持通过管理接口热更新汇率表）
+type Converter struct {
+	mu    sync.RWMutex
+	table *RateTable
+}
+
+// NewConverter 创建汇率转换服务
+func NewConverter(table *RateTable) (*Converter, error) {
+	c := &Converter{}
+	if err := c.Update(table); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Update 替换当前汇率表
+func (c *Converter) Update(table *RateTable) error {
+	if table == nil {
+		return fmt.Errorf("rate table cannot be nil")
+	}
+
+	normalized := table.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.table = normalized
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Table 返回当前汇率表副本
+func (c *Converter) Table() *RateTable {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.table.clone()
+}
+
+// Version 当前汇率表版本
+func (c *Converter) Version() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.table.Version
+}
+
+// Base 基准货币（未指定币种的金额按基准货币处理）
+func (c *Converter) Base() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.table.Base
+}
+
+// Rate 查询汇率：1 from = rate to
+func (c *Converter) Rate(from, to string) (*model.ExchangeRate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	from = c.normalize(from)
+	to = c.normalize(to)
+
+	fromRate, ok := c.table.Rates[from]
+	if !ok {
+		return nil, fmt.Errorf("unsupported currency: %s", from)
+	}
+	toRate, ok := c.table.Rates[to]
+	if !ok {
+		return nil, fmt.Errorf("unsupported currency: %s", to)
+	}
+
+	return &model.ExchangeRate{
+		From:    from,
+		To:      to,
+		Rate:    roundRate(toRate / fromRate),
+		Version: c.table.Version,
+	}, nil
+}
+
+// Convert 金额换算，返回换算结果及所用汇率
+func (c *Converter) Convert(amount float64, from, to string) (*model.Money, *model.ExchangeRate, error) {
+	rate, err := c.Rate(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &model.Money{
+		Amount:   roundAmount(amount * rate.Rate),
+		Currency: rate.To,
+	}, rate, nil
+}
+
+// normalize 币种归一化（调用方需持有读锁）
+func (c *Converter) normalize(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return c.table.Base
+	}
+	return currency
+}
+
+// roundAmount 金额保留两位小数
+func roundAmount(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// roundRate 汇率保留六位小数
+func roundRate(f float64) float64 {
+	return math.Round(f*1e6) / 1e6
+}
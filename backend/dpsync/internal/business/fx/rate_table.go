@@ -0,0 +1,107 @@
+package fx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// RateTable 汇率表
+// 以 Base 货币为基准：1 Base = Rates[currency] currency
+type RateTable struct {
+	Base      string             `json:"base"`
+	Version   string             `json:"version"` // 汇率表版本（写入诊断结果用于审计）
+	Rates     map[string]float64 `json:"rates"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// DefaultRateTable 内置汇率表（未配置汇率文件时使用）
+func DefaultRateTable() *RateTable {
+	return &RateTable{
+		Base:    "USD",
+		Version: "builtin-2025-12",
+		Rates: map[string]float64{
+			"USD": 1,
+			"EUR": 0.92,
+			"GBP": 0.79,
+			"CNY": 7.25,
+			"JPY": 151.5,
+			"CAD": 1.36,
+			"AUD": 1.52,
+			"HKD": 7.82,
+			"SGD": 1.34,
+			"MXN": 17.1,
+			"CHF": 0.88,
+			"KRW": 1340,
+		},
+		UpdatedAt: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// LoadRateTable 从 JSON 文件加载汇率表
+func LoadRateTable(path string) (*RateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rate table failed: %w", err)
+	}
+
+	var table RateTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("unmarshal rate table failed: %w", err)
+	}
+
+	if err := table.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+// Normalize 校验并归一化汇率表（币种代码统一大写，补齐基准货币）
+func (t *RateTable) Normalize() error {
+	t.Base = strings.ToUpper(strings.TrimSpace(t.Base))
+	if t.Base == "" {
+		return fmt.Errorf("rate table base currency is required")
+	}
+	if t.Version == "" {
+		return fmt.Errorf("rate table version is required")
+	}
+	if len(t.Rates) == 0 {
+		return fmt.Errorf("rate table rates cannot be empty")
+	}
+
+	rates := make(map[string]float64, len(t.Rates)+1)
+	for currency, rate := range t.Rates {
+		if rate <= 0 {
+			return fmt.Errorf("invalid rate for %s: %v", currency, rate)
+		}
+		rates[strings.ToUpper(strings.TrimSpace(currency))] = rate
+	}
+	if rate, ok := rates[t.Base]; ok && rate != 1 {
+		return fmt.Errorf("base currency %s must have rate 1, got %v", t.Base, rate)
+	}
+	rates[t.Base] = 1
+	t.Rates = rates
+
+	if t.UpdatedAt.IsZero() {
+		t.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝（对外返回副本，避免调用方修改内部状态）
+func (t *RateTable) clone() *RateTable {
+	rates := make(map[string]float64, len(t.Rates))
+	for currency, rate := range t.Rates {
+		rates[currency] = rate
+	}
+	return &RateTable{
+		Base:      t.Base,
+		Version:   t.Version,
+		Rates:     rates,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
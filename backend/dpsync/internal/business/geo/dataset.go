@@ -0,0 +1,361 @@
+package geo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Point 经纬度坐标（十进制度）
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Dataset 邮编质心参考数据：按国家登记国家质心与邮编前缀质心
+// 邮编前缀按最长前缀匹配（如美国三位 ZIP 前缀、英国邮编区、德国两位前缀），未命中时退化为国家质心
+type Dataset struct {
+	Version   string              `json:"version"`   // 数据版本（写入诊断结果用于审计）
+	Countries map[string]*Country `json:"countries"` // key: ISO 两位国家代码
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// Country 单个国家的质心数据
+type Country struct {
+	Centroid Point            `json:"centroid"`                  // 国家质心（邮编未命中时使用）
+	Postal   map[string]Point `json:"postal_prefixes,omitempty"` // key: 邮编前缀（大写，去除空格与连字符）
+}
+
+// csvHeader CSV 数据的列（postal_prefix 为空表示国家质心）
+var csvHeader = []string{"country", "postal_prefix", "lat", "lon"}
+
+// DefaultDataset 内置邮编质心数据（主要城市的近似坐标，仅用于本地调试与分区估算；生产环境通过 geo_file 加载完整数据）
+func DefaultDataset() *Dataset {
+	return &Dataset{
+		Version: "builtin-2025-12",
+		Countries: map[string]*Country{
+			"US": {
+				Centroid: Point{39.83, -98.58},
+				Postal: map[string]Point{
+					"021": {42.36, -71.06},  // Boston
+					"100": {40.75, -73.99},  // New York
+					"112": {40.65, -73.95},  // Brooklyn
+					"191": {39.95, -75.17},  // Philadelphia
+					"197": {39.68, -75.65},  // New Castle, DE
+					"200": {38.90, -77.04},  // Washington
+					"303": {33.75, -84.39},  // Atlanta
+					"331": {25.77, -80.19},  // Miami
+					"482": {42.33, -83.05},  // Detroit
+					"554": {44.98, -93.27},  // Minneapolis
+					"606": {41.88, -87.63},  // Chicago
+					"631": {38.63, -90.20},  // St. Louis
+					"641": {39.10, -94.58},  // Kansas City
+					"752": {32.78, -96.80},  // Dallas
+					"770": {29.76, -95.37},  // Houston
+					"787": {30.27, -97.74},  // Austin
+					"802": {39.74, -104.99}, // Denver
+					"850": {33.45, -112.07}, // Phoenix
+					"891": {36.17, -115.14}, // Las Vegas
+					"900": {34.05, -118.24}, // Los Angeles
+					"921": {32.72, -117.16}, // San Diego
+					"941": {37.77, -122.42}, // San Francisco
+					"968": {21.31, -157.86}, // Honolulu
+					"972": {45.52, -122.68}, // Portland
+					"981": {47.61, -122.33}, // Seattle
+					"995": {61.22, -149.90}, // Anchorage
+				},
+			},
+			"CA": {
+				Centroid: Point{56.13, -106.35},
+				Postal: map[string]Point{
+					"H":  {45.50, -73.57},  // Montreal
+					"K1": {45.42, -75.70},  // Ottawa
+					"M":  {43.65, -79.38},  // Toronto
+					"T2": {51.05, -114.07}, // Calgary
+					"T5": {53.55, -113.49}, // Edmonton
+					"V":  {49.28, -123.12}, // Vancouver
+				},
+			},
+			"GB": {
+				Centroid: Point{54.00, -2.00},
+				Postal: map[string]Point{
+					"B":  {52.49, -1.89}, // Birmingham
+					"BS": {51.45, -2.59}, // Bristol
+					"E":  {51.52, -0.05}, // London East
+					"EC": {51.52, -0.09}, // London City
+					"EH": {55.95, -3.19}, // Edinburgh
+					"G":  {55.86, -4.25}, // Glasgow
+					"M":  {53.48, -2.24}, // Manchester
+					"SW": {51.46, -0.16}, // London South West
+					"W":  {51.51, -0.20}, // London West
+				},
+			},
+			"DE": {
+				Centroid: Point{51.17, 10.45},
+				Postal: map[string]Point{
+					"10": {52.52, 13.40}, // Berlin
+					"20": {53.55, 9.99},  // Hamburg
+					"50": {50.94, 6.96},  // Köln
+					"60": {50.11, 8.68},  // Frankfurt
+					"70": {48.78, 9.18},  // Stuttgart
+					"80": {48.14, 11.58}, // München
+				},
+			},
+			"FR": {
+				Centroid: Point{46.60, 2.21},
+				Postal: map[string]Point{
+					"13": {43.30, 5.37},  // Marseille
+					"31": {43.60, 1.44},  // Toulouse
+					"33": {44.84, -0.58}, // Bordeaux
+					"69": {45.76, 4.84},  // Lyon
+					"75": {48.86, 2.35},  // Paris
+				},
+			},
+			"AU": {
+				Centroid: Point{-25.27, 133.78},
+				Postal: map[string]Point{
+					"20": {-33.87, 151.21}, // Sydney
+					"30": {-37.81, 144.96}, // Melbourne
+					"40": {-27.47, 153.03}, // Brisbane
+					"50": {-34.93, 138.60}, // Adelaide
+					"60": {-31.95, 115.86}, // Perth
+				},
+			},
+			"JP": {
+				Centroid: Point{36.20, 138.25},
+				Postal: map[string]Point{
+					"060": {43.06, 141.35}, // 札幌
+					"100": {35.68, 139.77}, // 東京
+					"460": {35.18, 136.91}, // 名古屋
+					"530": {34.69, 135.50}, // 大阪
+					"810": {33.59, 130.40}, // 福岡
+				},
+			},
+			"CN": {
+				Centroid: Point{35.86, 104.20},
+				Postal: map[string]Point{
+					"100": {39.90, 116.41}, // 北京
+					"200": {31.23, 121.47}, // 上海
+					"510": {23.13, 113.26}, // 广州
+					"518": {22.54, 114.06}, // 深圳
+				},
+			},
+			"AE": {Centroid: Point{24.00, 54.00}},
+			"BR": {Centroid: Point{-14.24, -51.93}},
+			"ES": {Centroid: Point{40.46, -3.75}},
+			"HK": {Centroid: Point{22.32, 114.17}},
+			"IE": {Centroid: Point{53.41, -8.24}},
+			"IN": {Centroid: Point{20.59, 78.96}},
+			"IT": {Centroid: Point{41.87, 12.57}},
+			"KR": {Centroid: Point{35.91, 127.77}},
+			"MX": {Centroid: Point{23.63, -102.55}},
+			"NL": {Centroid: Point{52.13, 5.29}},
+			"NZ": {Centroid: Point{-40.90, 174.89}},
+			"SG": {Centroid: Point{1.35, 103.82}},
+		},
+	}
+}
+
+// LoadDataset 从文件加载邮编质心数据（按扩展名识别 .csv 或 .json）
+func LoadDataset(path string) (*Dataset, error) {
+	var dataset *Dataset
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		dataset, err = loadCSV(path)
+	case ".json":
+		dataset, err = loadJSON(path)
+	default:
+		return nil, fmt.Errorf("unsupported geo dataset format: %s (expected .csv or .json)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dataset.Normalize(); err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
+
+// loadJSON 加载 JSON 数据
+func loadJSON(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read geo dataset failed: %w", err)
+	}
+
+	var dataset Dataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, fmt.Errorf("unmarshal geo dataset failed: %w", err)
+	}
+	return &dataset, nil
+}
+
+// loadCSV 加载 CSV 数据（首行为表头；版本取文件修改时间；国家未登记质心行时取其邮编前缀质心的平均值）
+func loadCSV(path string) (*Dataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read geo dataset failed: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("read geo dataset failed: %w", err)
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(csvHeader)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read geo dataset header failed: %w", err)
+	}
+	for i, name := range csvHeader {
+		if !strings.EqualFold(strings.TrimSpace(header[i]), name) {
+			return nil, fmt.Errorf("geo dataset columns must be: %s", strings.Join(csvHeader, ","))
+		}
+	}
+
+	dataset := &Dataset{
+		Version:   "csv-" + info.ModTime().UTC().Format("20060102T150405Z"),
+		Countries: make(map[string]*Country),
+		UpdatedAt: info.ModTime(),
+	}
+	hasCentroid := make(map[string]bool)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read geo dataset line %d failed: %w", line, err)
+		}
+
+		code := strings.ToUpper(strings.TrimSpace(record[0]))
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if latErr != nil || lonErr != nil {
+			return nil, fmt.Errorf("geo dataset line %d: invalid coordinates", line)
+		}
+
+		country, ok := dataset.Countries[code]
+		if !ok {
+			country = &Country{Postal: make(map[string]Point)}
+			dataset.Countries[code] = country
+		}
+		if prefix := strings.TrimSpace(record[1]); prefix != "" {
+			country.Postal[prefix] = Point{Lat: lat, Lon: lon}
+		} else {
+			country.Centroid = Point{Lat: lat, Lon: lon}
+			hasCentroid[code] = true
+		}
+	}
+
+	for code, country := range dataset.Countries {
+		if !hasCentroid[code] && len(country.Postal) > 0 {
+			country.Centroid = meanPoint(country.Postal)
+		}
+	}
+
+	return dataset, nil
+}
+
+// Normalize 校验并归一化数据（国家代码大写，邮编前缀大写并去除空格与连字符，坐标在合法范围内）
+func (d *Dataset) Normalize() error {
+	if d.Version == "" {
+		return fmt.Errorf("geo dataset version is required")
+	}
+
+	countries := make(map[string]*Country, len(d.Countries))
+	for code, country := range d.Countries {
+		if country == nil {
+			return fmt.Errorf("country %s: centroid is required", code)
+		}
+		if err := country.Centroid.validate(); err != nil {
+			return fmt.Errorf("country %s: %w", code, err)
+		}
+
+		postal := make(map[string]Point, len(country.Postal))
+		for prefix, point := range country.Postal {
+			normalized := NormalizePostalCode(prefix)
+			if normalized == "" {
+				return fmt.Errorf("country %s: empty postal prefix", code)
+			}
+			if err := point.validate(); err != nil {
+				return fmt.Errorf("country %s postal prefix %s: %w", code, prefix, err)
+			}
+			postal[normalized] = point
+		}
+		country.Postal = postal
+
+		countries[strings.ToUpper(strings.TrimSpace(code))] = country
+	}
+	d.Countries = countries
+
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝
+func (d *Dataset) clone() *Dataset {
+	countries := make(map[string]*Country, len(d.Countries))
+	for code, country := range d.Countries {
+		if country == nil {
+			countries[code] = nil
+			continue
+		}
+		postal := make(map[string]Point, len(country.Postal))
+		for prefix, point := range country.Postal {
+			postal[prefix] = point
+		}
+		countries[code] = &Country{Centroid: country.Centroid, Postal: postal}
+	}
+
+	return &Dataset{
+		Version:   d.Version,
+		Countries: countries,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+// validate 校验坐标范围
+func (p Point) validate() error {
+	if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+		return fmt.Errorf("invalid coordinates (%g, %g)", p.Lat, p.Lon)
+	}
+	return nil
+}
+
+// NormalizePostalCode 邮编归一化：转大写，只保留字母与数字
+func NormalizePostalCode(postalCode string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, postalCode)
+}
+
+// meanPoint 坐标算术平均（仅用于 CSV 缺少国家质心时的近似）
+func meanPoint(points map[string]Point) Point {
+	var lat, lon float64
+	for _, p := range points {
+		lat += p.Lat
+		lon += p.Lon
+	}
+	n := float64(len(points))
+	return Point{Lat: lat / n, Lon: lon / n}
+}
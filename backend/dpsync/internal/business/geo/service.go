@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// 定位精度
+const (
+	PrecisionPostal  = "POSTAL"  // 命中邮编前缀质心
+	PrecisionCountry = "COUNTRY" // 邮编未命中，使用国家质心
+)
+
+// Location 地理编码结果
+type Location struct {
+	Point
+	Precision    string // POSTAL/COUNTRY
+	PostalPrefix string // 命中的邮编前缀（国家质心时为空）
+}
+
+// Service 离线地理编码服务（并发安全，支持整体替换质心数据）
+type Service struct {
+	mu      sync.RWMutex
+	dataset *Dataset
+}
+
+// NewService 创建地理编码服务
+func NewService(dataset *Dataset) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(dataset); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前质心数据
+func (s *Service) Update(dataset *Dataset) error {
+	if dataset == nil {
+		return fmt.Errorf("geo dataset cannot be nil")
+	}
+
+	normalized := dataset.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.dataset = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Dataset 返回当前质心数据副本
+func (s *Service) Dataset() *Dataset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataset.clone()
+}
+
+// Version 质心数据版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataset.Version
+}
+
+// Resolve 将国家 + 邮编解析为坐标（邮编按最长前缀匹配，未命中时使用国家质心；国家未登记时 ok=false）
+// 以字母结尾的前缀只匹配完整的字母段（英国邮编区 "B" 不匹配 "BS1"，避免伯明翰误配为布里斯托）
+func (s *Service) Resolve(country, postalCode string) (*Location, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.dataset.Countries[strings.ToUpper(strings.TrimSpace(country))]
+	if !ok || data == nil {
+		return nil, false
+	}
+
+	code := NormalizePostalCode(postalCode)
+	for n := len(code); n > 0; n-- {
+		prefix := code[:n]
+		point, ok := data.Postal[prefix]
+		if !ok {
+			continue
+		}
+		if n < len(code) && isLetter(prefix[n-1]) && isLetter(code[n]) {
+			continue
+		}
+		return &Location{Point: point, Precision: PrecisionPostal, PostalPrefix: prefix}, true
+	}
+
+	return &Location{Point: data.Centroid, Precision: PrecisionCountry}, true
+}
+
+// isLetter 判断 ASCII 字符是否为字母（邮编已归一化为大写字母与数字）
+func isLetter(c byte) bool {
+	return unicode.IsLetter(rune(c))
+}
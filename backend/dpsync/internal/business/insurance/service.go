@@ -0,0 +1,53 @@
+package insurance
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Service 运输保险参考数据服务（并发安全，支持整体替换）
+type Service struct {
+	mu    sync.RWMutex
+	table *Table
+}
+
+// NewService 创建运输保险参考数据服务
+func NewService(table *Table) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(table); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前参考数据
+func (s *Service) Update(table *Table) error {
+	if table == nil {
+		return fmt.Errorf("insurance table cannot be nil")
+	}
+
+	normalized := table.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.table = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Table 返回当前参考数据副本（单次诊断内使用同一份快照）
+func (s *Service) Table() *Table {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.clone()
+}
+
+// Version 参考数据版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Version
+}
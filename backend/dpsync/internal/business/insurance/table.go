@@ -0,0 +1,220 @@
+package insurance
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// Table 运输保险参考数据：承运商默认赔付责任、保费费率与线路丢损率
+type Table struct {
+	Version                string        `json:"version"`                   // 数据版本（写入诊断结果用于审计）
+	Currency               string        `json:"currency"`                  // 表内金额币种
+	DefaultLiability       float64       `json:"default_liability"`         // 未登记承运商服务的默认赔付责任
+	Liabilities            []Liability   `json:"liabilities"`               // 承运商默认赔付责任（服务级优先于承运商级）
+	Premiums               []PremiumRate `json:"premiums"`                  // 保费费率（承运商级优先于通用费率）
+	Lanes                  []LaneRisk    `json:"lanes"`                     // 线路丢损率（始发国 + 目的国最具体的一条生效）
+	DefaultLossRatePercent float64       `json:"default_loss_rate_percent"` // 未登记线路的丢损率（%）
+	HighLossRatePercent    float64       `json:"high_loss_rate_percent"`    // 丢损率达到该值视为高丢损线路
+	HighValueThreshold     float64       `json:"high_value_threshold"`      // 申报货值达到该值视为高货值
+	UpdatedAt              time.Time     `json:"updated_at"`
+}
+
+// Liability 承运商默认赔付责任（未投保时承运商对丢失/损坏的最高赔付）
+type Liability struct {
+	Carrier string  `json:"carrier"`           // 承运商（大小写不敏感）
+	Service string  `json:"service,omitempty"` // 服务名称（为空表示承运商全部服务）
+	Amount  float64 `json:"amount"`
+}
+
+// PremiumRate 保费费率（按申报货值超出承运商赔付责任的部分计费）
+type PremiumRate struct {
+	Carrier     string  `json:"carrier,omitempty"` // 承运商（为空表示通用费率）
+	RatePercent float64 `json:"rate_percent"`      // 费率（%）
+	MinPremium  float64 `json:"min_premium"`       // 最低保费
+}
+
+// LaneRisk 线路丢损率
+type LaneRisk struct {
+	Origin          string  `json:"origin,omitempty"`      // 始发国（为空表示任意始发国）
+	Destination     string  `json:"destination,omitempty"` // 目的国（为空表示任意目的国）
+	LossRatePercent float64 `json:"loss_rate_percent"`     // 丢失/损坏率（%）
+}
+
+// DefaultTable 内置保险参考数据（未配置数据文件时使用）
+func DefaultTable() *Table {
+	return &Table{
+		Version:          "builtin-2025-12",
+		Currency:         "USD",
+		DefaultLiability: 100,
+		Liabilities: []Liability{
+			{Carrier: "FedEx", Amount: 100},
+			{Carrier: "UPS", Amount: 100},
+			{Carrier: "USPS", Service: "Priority", Amount: 100},
+			{Carrier: "USPS", Amount: 50},
+			{Carrier: "DHL", Service: "Express", Amount: 100},
+		},
+		Premiums: []PremiumRate{
+			{RatePercent: 1.0, MinPremium: 2.50},
+			{Carrier: "FedEx", RatePercent: 1.35, MinPremium: 4.05},
+			{Carrier: "UPS", RatePercent: 1.35, MinPremium: 4.05},
+			{Carrier: "DHL", RatePercent: 1.0, MinPremium: 14.00},
+		},
+		Lanes: []LaneRisk{
+			{Destination: "BR", LossRatePercent: 3.0},
+			{Destination: "MX", LossRatePercent: 2.0},
+			{Destination: "NG", LossRatePercent: 4.0},
+			{Destination: "ZA", LossRatePercent: 2.5},
+			{Destination: "IN", LossRatePercent: 1.5},
+			{Destination: "RU", LossRatePercent: 2.5},
+			{Origin: "US", Destination: "US", LossRatePercent: 0.3},
+			{Origin: "CN", LossRatePercent: 0.8},
+		},
+		DefaultLossRatePercent: 0.5,
+		HighLossRatePercent:    1.5,
+		HighValueThreshold:     500,
+		UpdatedAt:              time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// LoadTable 从 JSON 文件加载保险参考数据
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read insurance table failed: %w", err)
+	}
+
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("unmarshal insurance table failed: %w", err)
+	}
+
+	if err := table.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+// Normalize 校验并归一化保险参考数据（币种与国家代码转大写，金额与费率不能为负）
+func (t *Table) Normalize() error {
+	if t.Version == "" {
+		return fmt.Errorf("insurance table version is required")
+	}
+	if err := model.ValidateCurrencyCode(t.Currency); err != nil || strings.TrimSpace(t.Currency) == "" {
+		return fmt.Errorf("insurance table currency must be an ISO 4217 code")
+	}
+	t.Currency = model.NormalizeCurrency(t.Currency)
+
+	if t.DefaultLiability < 0 || t.DefaultLossRatePercent < 0 || t.HighLossRatePercent < 0 || t.HighValueThreshold < 0 {
+		return fmt.Errorf("default_liability, loss rates and high_value_threshold cannot be negative")
+	}
+
+	for i := range t.Liabilities {
+		liability := &t.Liabilities[i]
+		liability.Carrier = strings.TrimSpace(liability.Carrier)
+		liability.Service = strings.TrimSpace(liability.Service)
+		if liability.Carrier == "" {
+			return fmt.Errorf("liabilities[%d]: carrier is required", i)
+		}
+		if liability.Amount < 0 {
+			return fmt.Errorf("liabilities[%d]: amount cannot be negative", i)
+		}
+	}
+
+	for i := range t.Premiums {
+		premium := &t.Premiums[i]
+		premium.Carrier = strings.TrimSpace(premium.Carrier)
+		if premium.RatePercent < 0 || premium.MinPremium < 0 {
+			return fmt.Errorf("premiums[%d]: rate_percent and min_premium cannot be negative", i)
+		}
+	}
+
+	for i := range t.Lanes {
+		lane := &t.Lanes[i]
+		lane.Origin = strings.ToUpper(strings.TrimSpace(lane.Origin))
+		lane.Destination = strings.ToUpper(strings.TrimSpace(lane.Destination))
+		if lane.LossRatePercent < 0 || lane.LossRatePercent > 100 {
+			return fmt.Errorf("lanes[%d]: loss_rate_percent must be between 0 and 100", i)
+		}
+	}
+
+	if t.UpdatedAt.IsZero() {
+		t.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝
+func (t *Table) clone() *Table {
+	copied := *t
+	copied.Liabilities = append([]Liability(nil), t.Liabilities...)
+	copied.Premiums = append([]PremiumRate(nil), t.Premiums...)
+	copied.Lanes = append([]LaneRisk(nil), t.Lanes...)
+	return &copied
+}
+
+// Liability 承运商服务的默认赔付责任（服务级 > 承运商级 > 默认值）
+func (t *Table) Liability(carrier, service string) float64 {
+	amount, matched := t.DefaultLiability, false
+	for _, liability := range t.Liabilities {
+		if !strings.EqualFold(liability.Carrier, carrier) {
+			continue
+		}
+		if strings.EqualFold(liability.Service, service) {
+			return liability.Amount
+		}
+		if liability.Service == "" && !matched {
+			amount, matched = liability.Amount, true
+		}
+	}
+	return amount
+}
+
+// Premium 为超出承运商赔付责任的货值投保的保费（承运商费率优先于通用费率；未配置费率时 ok=false）
+func (t *Table) Premium(carrier string, uncovered float64) (float64, bool) {
+	var rate *PremiumRate
+	for i := range t.Premiums {
+		premium := &t.Premiums[i]
+		if strings.EqualFold(premium.Carrier, carrier) {
+			rate = premium
+			break
+		}
+		if premium.Carrier == "" && rate == nil {
+			rate = premium
+		}
+	}
+	if rate == nil {
+		return 0, false
+	}
+
+	premium := math.Max(uncovered*rate.RatePercent/100, rate.MinPremium)
+	return math.Round(premium*100) / 100, true
+}
+
+// LossRate 线路丢损率（始发国与目的国均匹配 > 仅目的国匹配 > 仅始发国匹配 > 默认值）
+func (t *Table) LossRate(origin, destination string) float64 {
+	best, bestScore := t.DefaultLossRatePercent, 0
+	for _, lane := range t.Lanes {
+		if (lane.Origin != "" && lane.Origin != origin) || (lane.Destination != "" && lane.Destination != destination) {
+			continue
+		}
+		score := 0
+		if lane.Destination != "" {
+			score += 2
+		}
+		if lane.Origin != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = lane.LossRatePercent, score
+		}
+	}
+	return best
+}
@@ -3,8 +3,10 @@ package diagnose
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"oip/common/model"
 	"oip/dpsync/internal/business/order/diagnose/services"
 )
 
@@ -18,22 +20,72 @@ func (h *DiagnoseHandler) PreProcess(ctx context.Context) error {
 		return errors.New("account_id is invalid")
 	}
 
+	shipment, err := model.DecodeShipment(h.payload.Shipment)
+	if err != nil {
+		return err
+	}
+	h.shipment = shipment
+
+	h.payload.Strategy.Normalize()
+	if err := h.payload.Strategy.Validate(); err != nil {
+		return err
+	}
+
+	if err := model.ValidateDate(h.payload.PromisedDeliveryDate); err != nil {
+		return fmt.Errorf("promised_delivery_date: %w", err)
+	}
+
+	if err := model.ValidateBoxes(h.payload.Boxes); err != nil {
+		return fmt.Errorf("boxes: %w", err)
+	}
+
+	h.payload.Incoterm = model.NormalizeIncoterm(h.payload.Incoterm)
+	if err := model.ValidateIncoterm(h.payload.Incoterm); err != nil {
+		return err
+	}
+
+	for i := range h.payload.Contracts {
+		if err := h.payload.Contracts[i].Validate(); err != nil {
+			return fmt.Errorf("contracts[%d]: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
 // Process 核心处理
+// 诊断只执行一次，结果同时用于 Resulter 输出与回调
 func (h *DiagnoseHandler) Process(ctx context.Context) error {
-	input := &services.DiagnoseInput{
-		RequestID:       h.GetMeta().RequestID,
-		OrderID:         h.payload.OrderID,
-		AccountID:       h.payload.AccountID,
-		MerchantOrderNo: h.payload.MerchantOrderNo,
-		Shipment:        h.payload.Shipment,
+	h.input = &services.DiagnoseInput{
+		RequestID:            h.GetMeta().RequestID,
+		OrderID:              h.payload.OrderID,
+		AccountID:            h.payload.AccountID,
+		MerchantOrderNo:      h.payload.MerchantOrderNo,
+		Shipment:             h.shipment,
+		Diagnosers:           h.payload.Diagnosers,
+		PreferredCurrency:    h.payload.PreferredCurrency,
+		Strategy:             h.payload.Strategy,
+		Contracts:            h.payload.Contracts,
+		OrderCreatedAt:       h.payload.OrderCreatedAt,
+		PromisedDeliveryDate: h.payload.PromisedDeliveryDate,
+		Boxes:                h.payload.Boxes,
+		Incoterm:             h.payload.Incoterm,
+		Account:              h.payload.Account,
+		Selected:             h.payload.Selected,
+		Enrichments:          h.payload.Enrichments,
+		Explain:              h.payload.Explain,
+	}
+	if h.input.OrderCreatedAt.IsZero() {
+		// 旧版本 dpmain 未下发下单时间，以任务处理时间为准
+		h.input.OrderCreatedAt = time.Now()
 	}
 
-	compositeHandler := services.NewCompositeHandler()
-	result, err := compositeHandler.Diagnose(ctx, input)
+	result, err := h.compositeHandler.Diagnose(ctx, h.input)
 	if err != nil {
+		// 诊断失败同样回调，避免订单停留在诊断中状态
+		if cbErr := h.diagnosisService.SendCallback(ctx, h.input, nil, err); cbErr != nil {
+			return fmt.Errorf("%v (callback failed: %w)", err, cbErr)
+		}
 		return err
 	}
 
@@ -45,9 +97,12 @@ func (h *DiagnoseHandler) Process(ctx context.Context) error {
 // PostProcess 后处理
 func (h *DiagnoseHandler) PostProcess(ctx context.Context) error {
 	err := h.GetResulter().Set(ctx, &DiagnosisResultData{
-		Items:       h.diagnosisResult.Items,
-		OrderID:     h.payload.OrderID,
-		ProcessedAt: time.Now().Unix(),
+		EngineVersion: h.diagnosisResult.EngineVersion,
+		Items:         h.diagnosisResult.Items,
+		Traces:        h.diagnosisResult.Traces,
+		Enrichments:   h.diagnosisResult.Enrichments,
+		OrderID:       h.payload.OrderID,
+		ProcessedAt:   time.Now().Unix(),
 	})
 	if err != nil {
 		return err
@@ -56,18 +111,17 @@ func (h *DiagnoseHandler) PostProcess(ctx context.Context) error {
 	output := h.GetResulter().Get(ctx)
 	h.SetOutput(output)
 
-	return h.sendCallback(ctx)
+	if err := h.sendCallback(ctx); err != nil {
+		return err
+	}
+
+	// 回调发出后执行影子诊断（结果单独保存，不回传；回调失败重试时不重复执行）
+	h.compositeHandler.RunShadows(ctx, h.input, h.diagnosisResult)
+
+	return nil
 }
 
-// sendCallback 发送回调
+// sendCallback 发送回调（复用 Process 阶段的诊断结果）
 func (h *DiagnoseHandler) sendCallback(ctx context.Context) error {
-	input := &services.DiagnoseInput{
-		RequestID:       h.GetMeta().RequestID,
-		OrderID:         h.payload.OrderID,
-		AccountID:       h.payload.AccountID,
-		MerchantOrderNo: h.payload.MerchantOrderNo,
-		Shipment:        h.payload.Shipment,
-	}
-
-	return h.diagnosisService.ExecuteDiagnosis(ctx, input)
+	return h.diagnosisService.SendCallback(ctx, h.input, h.diagnosisResult, nil)
 }
@@ -15,6 +15,9 @@ type DiagnoseHandler struct {
 	framework.BaseHandler
 
 	payload          *DiagnosePayload
+	shipment         *model.Shipment
+	input            *services.DiagnoseInput
+	compositeHandler *services.CompositeHandler
 	diagnosisService *services.DiagnosisService
 	diagnosisResult  *model.DiagnosisResultData
 }
@@ -25,6 +28,7 @@ func NewDiagnoseHandler(
 	baseHandler *framework.BaseHandler,
 	lmstfyClient *lmstfy.Client,
 	callbackQueue string,
+	compositeHandler *services.CompositeHandler,
 ) (framework.BusinessHandler, error) {
 	bizPayload := baseHandler.GetBizPayload()
 
@@ -41,6 +45,7 @@ func NewDiagnoseHandler(
 	handler := &DiagnoseHandler{
 		BaseHandler:      *baseHandler,
 		payload:          &payload,
+		compositeHandler: compositeHandler,
 		diagnosisService: services.NewDiagnosisService(lmstfyClient, callbackQueue),
 	}
 
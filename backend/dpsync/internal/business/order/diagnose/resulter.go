@@ -20,9 +20,12 @@ func (r *DiagnosisResulter) Set(ctx context.Context, data interface{}) error {
 	resultData := data.(*DiagnosisResultData)
 
 	r.dstData = &DiagnosisOutput{
-		Items:       resultData.Items,
-		OrderID:     resultData.OrderID,
-		ProcessedAt: resultData.ProcessedAt,
+		EngineVersion: resultData.EngineVersion,
+		Items:         resultData.Items,
+		Traces:        resultData.Traces,
+		Enrichments:   resultData.Enrichments,
+		OrderID:       resultData.OrderID,
+		ProcessedAt:   resultData.ProcessedAt,
 	}
 
 	return nil
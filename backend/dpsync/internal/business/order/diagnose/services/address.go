@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strings"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/dedup"
+	"oip/dpsync/internal/business/surcharge"
+)
+
+// shipToAddress 从 shipment 中提取收件地址（ship_to 缺失时返回 nil）
+func shipToAddress(shipment *model.Shipment) *surcharge.Address {
+	if shipment == nil || shipment.ShipTo == nil {
+		return nil
+	}
+
+	shipTo := shipment.ShipTo
+	return &surcharge.Address{
+		CompanyName: strings.TrimSpace(shipTo.CompanyName),
+		Street1:     strings.TrimSpace(shipTo.Street1),
+		Street2:     strings.TrimSpace(shipTo.Street2),
+		PostalCode:  strings.TrimSpace(shipTo.PostalCode),
+		Country:     normalizeCountry(shipTo.Country),
+	}
+}
+
+// destinationCountry 目的国（ISO 两位代码，ship_to 缺失时返回空串）
+func destinationCountry(shipment *model.Shipment) string {
+	if shipment == nil || shipment.ShipTo == nil {
+		return ""
+	}
+	return normalizeCountry(shipment.ShipTo.Country)
+}
+
+// originCountry 始发国（ISO 两位代码，ship_from 缺失时返回空串）
+func originCountry(shipment *model.Shipment) string {
+	if shipment == nil || shipment.ShipFrom == nil {
+		return ""
+	}
+	return normalizeCountry(shipment.ShipFrom.Country)
+}
+
+// recipientKey 收件人地址指纹（同一收件人、同一地址的订单指纹相同，用于近期订单索引）
+func recipientKey(shipTo *model.Address) string {
+	return dedup.AddressKey(normalizeCountry(shipTo.Country), shipTo.PostalCode, shipTo.City,
+		shipTo.Street1, shipTo.Street2, shipTo.ContactName)
+}
@@ -3,26 +3,95 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"oip/common/model"
+	"oip/dpsync/internal/business/dedup"
+	"oip/dpsync/internal/business/explain"
+	"oip/dpsync/internal/business/fx"
+	"oip/dpsync/internal/business/surcharge"
 )
 
+// anomalyRulesVersion 异常规则版本（调整规则或阈值时需同步更新）
+const anomalyRulesVersion = "anomaly-2025-12"
+
+// highValueThreshold 高货值阈值（以汇率表基准货币计）
+const highValueThreshold = 1000.0
+
+// heavyPackageThresholdKg 超重提示阈值（货件总重，千克）
+const heavyPackageThresholdKg = 10.0
+
 // AnomalyChecker 异常检测器（规则引擎）
-type AnomalyChecker struct{}
+type AnomalyChecker struct {
+	converter  *fx.Converter
+	surcharges *surcharge.Service
+	duplicates *dedup.Detector // 为 nil 时不检测重复订单
+}
 
 // NewAnomalyChecker 创建异常检测器实例
-func NewAnomalyChecker() *AnomalyChecker {
-	return &AnomalyChecker{}
+func NewAnomalyChecker(converter *fx.Converter, surcharges *surcharge.Service, duplicates *dedup.Detector) *AnomalyChecker {
+	return &AnomalyChecker{
+		converter:  converter,
+		surcharges: surcharges,
+		duplicates: duplicates,
+	}
+}
+
+// Type 诊断类型
+func (c *AnomalyChecker) Type() string {
+	return model.DiagnosisTypeAnomaly
+}
+
+// Diagnose 执行异常检测诊断（实现 Diagnoser 接口）
+// 启用重复订单检测后结果依赖近期订单索引，相同货件的结果不能复用，因此不写入缓存
+func (c *AnomalyChecker) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	result, err := c.Check(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if c.duplicates != nil {
+		return noCache{data: result}, nil
+	}
+	return result, nil
+}
+
+// ResultSchema 诊断结果结构
+func (c *AnomalyChecker) ResultSchema() interface{} {
+	return &model.AnomalyResult{}
+}
+
+// Version 异常规则、汇率表与附加费数据集的组合版本（实现 VersionedDiagnoser 接口）
+func (c *AnomalyChecker) Version() string {
+	version := "rules:" + anomalyRulesVersion
+	if c.converter != nil {
+		version += ";fx:" + c.converter.Version()
+	}
+	if c.surcharges != nil {
+		version += ";surcharge:" + c.surcharges.Version()
+	}
+	return version
+}
+
+// OptionsKey 偏好币种（实现 OptionsKeyer 接口，申报货值按偏好币种换算）
+func (c *AnomalyChecker) OptionsKey(input *DiagnoseInput) string {
+	return input.PreferredCurrency
 }
 
 // Check 执行异常检测（基于固定规则）
-// 参数 shipment 为物流信息，包含 ship_from、ship_to、parcels 等
-func (c *AnomalyChecker) Check(ctx context.Context, shipment map[string]interface{}) (*model.AnomalyResult, error) {
+// input.Shipment 为物流信息（已在 PreProcess 中完成结构校验）
+func (c *AnomalyChecker) Check(ctx context.Context, input *DiagnoseInput) (*model.AnomalyResult, error) {
+	rec := explain.FromContext(ctx)
+	shipment := input.Shipment
 	issues := make([]model.AnomalyItem, 0)
 
 	// 规则 1：检查 parcels 是否存在
-	parcels, ok := shipment["parcels"].([]interface{})
-	if !ok || len(parcels) == 0 {
+	parcelCount := 0
+	if shipment != nil {
+		parcelCount = len(shipment.Parcels)
+	}
+	rec.Input("parcel_count", parcelCount)
+	rec.Rule("MISSING_PARCELS", parcelCount == 0, nil)
+	if parcelCount == 0 {
 		issues = append(issues, model.AnomalyItem{
 			Type:    "MISSING_PARCELS",
 			Level:   "CRITICAL",
@@ -35,20 +104,15 @@ func (c *AnomalyChecker) Check(ctx context.Context, shipment map[string]interfac
 		}, nil
 	}
 
-	// 规则 2：检查重量异常（TBC: 简化版本,仅作示例）
-	// 实际应该解析 parcel.weight 字段
-	totalWeight := 0.0
-	for _, parcel := range parcels {
-		if parcelMap, ok := parcel.(map[string]interface{}); ok {
-			if weight, ok := parcelMap["weight"].(map[string]interface{}); ok {
-				if value, ok := weight["value"].(float64); ok {
-					totalWeight += value
-				}
-			}
-		}
-	}
-
-	if totalWeight > 10.0 {
+	// 规则 2：检查重量异常（按千克汇总包裹重量）
+	totalWeight := shipment.TotalWeightKg()
+	rec.Input("total_weight_kg", totalWeight)
+	rec.Rule(model.AnomalyTypeHeavyPackage, totalWeight > heavyPackageThresholdKg, map[string]interface{}{
+		"total_weight_kg": totalWeight,
+		"threshold_kg":    heavyPackageThresholdKg,
+		"parcel_weights":  parcelWeights(shipment),
+	})
+	if totalWeight > heavyPackageThresholdKg {
 		issues = append(issues, model.AnomalyItem{
 			Type:    "HEAVY_PACKAGE",
 			Level:   "WARNING",
@@ -56,28 +120,268 @@ func (c *AnomalyChecker) Check(ctx context.Context, shipment map[string]interfac
 		})
 	}
 
-	// 规则 3：检查 SKU 缺失（TBC: 简化版本）
-	for i, parcel := range parcels {
-		if parcelMap, ok := parcel.(map[string]interface{}); ok {
-			if items, ok := parcelMap["items"].([]interface{}); ok {
-				for j, item := range items {
-					if itemMap, ok := item.(map[string]interface{}); ok {
-						sku, hasSKU := itemMap["sku"].(string)
-						if !hasSKU || sku == "" {
-							issues = append(issues, model.AnomalyItem{
-								Type:    "SKU_MISSING",
-								Level:   "CRITICAL",
-								Message: fmt.Sprintf("Item #%d in parcel #%d missing SKU", j+1, i+1),
-							})
-						}
-					}
-				}
+	// 规则 3：检查 SKU 缺失
+	for i, parcel := range shipment.Parcels {
+		for j, item := range parcel.Items {
+			rec.Rule(model.AnomalyTypeSKUMissing, strings.TrimSpace(item.SKU) == "", map[string]interface{}{
+				"parcel_index": i,
+				"item_index":   j,
+				"sku":          item.SKU,
+			})
+			if strings.TrimSpace(item.SKU) == "" {
+				issues = append(issues, model.AnomalyItem{
+					Type:    "SKU_MISSING",
+					Level:   "CRITICAL",
+					Message: fmt.Sprintf("Item #%d in parcel #%d missing SKU", j+1, i+1),
+				})
 			}
 		}
 	}
 
+	// 规则 4：高货值（各商品金额按汇率换算后汇总，不同币种不可直接相加）
+	declaredValue, fxIssues := declaredValue(c.converter, shipment.Parcels, input.PreferredCurrency)
+	issues = append(issues, fxIssues...)
+	if declaredValue != nil {
+		rec.Input("declared_value", declaredValue)
+		rec.Rule(model.AnomalyTypeHighValue, declaredValue.Amount > highValueThreshold, map[string]interface{}{
+			"declared_value": declaredValue.Amount,
+			"currency":       declaredValue.Currency,
+			"threshold":      highValueThreshold,
+		})
+	}
+	if declaredValue != nil && declaredValue.Amount > highValueThreshold {
+		message := fmt.Sprintf("Declared value %.2f %s exceeds %.2f %s", declaredValue.Amount, declaredValue.Currency, highValueThreshold, declaredValue.Currency)
+		if declaredValue.Preferred != nil {
+			message += fmt.Sprintf(" (%.2f %s)", declaredValue.Preferred.Amount, declaredValue.Preferred.Currency)
+		}
+		issues = append(issues, model.AnomalyItem{
+			Type:    model.AnomalyTypeHighValue,
+			Level:   model.AnomalyLevelWarning,
+			Message: message,
+		})
+	}
+
+	// 规则 5：偏远/扩展派送区域
+	if issue := c.remoteArea(rec, input.Shipment); issue != nil {
+		issues = append(issues, *issue)
+	}
+
+	// 规则 6：疑似重复下单/拆单
+	issues = append(issues, c.duplicateOrders(ctx, rec, input)...)
+
 	return &model.AnomalyResult{
-		HasRisk: len(issues) > 0,
-		Issues:  issues,
+		HasRisk:       len(issues) > 0,
+		Issues:        issues,
+		DeclaredValue: declaredValue,
 	}, nil
 }
+
+// declaredValue 汇总申报货值：price.amount × quantity 换算为汇率表基准货币后求和
+// 无法换算的币种跳过并生成 UNKNOWN_CURRENCY 异常；preferredCurrency 非空时同时给出偏好币种金额
+func declaredValue(converter *fx.Converter, parcels []*model.Parcel, preferredCurrency string) (*model.DeclaredValue, []model.AnomalyItem) {
+	if converter == nil {
+		return nil, nil
+	}
+
+	base := converter.Base()
+	issues := make([]model.AnomalyItem, 0)
+	rates := make(map[string]model.ExchangeRate)
+	rateOrder := make([]string, 0)
+	unknown := make(map[string]bool)
+	total := 0.0
+
+	for _, parcel := range parcels {
+		for _, item := range parcel.Items {
+			if item.Price == nil {
+				continue
+			}
+			quantity := item.Quantity
+			if quantity <= 0 {
+				quantity = 1
+			}
+			currency := strings.TrimSpace(item.Price.Currency)
+
+			converted, rate, err := converter.Convert(item.Price.Amount*float64(quantity), currency, base)
+			if err != nil {
+				if !unknown[currency] {
+					unknown[currency] = true
+					issues = append(issues, model.AnomalyItem{
+						Type:    model.AnomalyTypeUnknownCurrency,
+						Level:   model.AnomalyLevelWarning,
+						Message: fmt.Sprintf("Currency %q cannot be converted, item value excluded from declared value", currency),
+					})
+				}
+				continue
+			}
+
+			total += converted.Amount
+			if _, seen := rates[rate.From]; !seen && rate.From != rate.To {
+				rates[rate.From] = *rate
+				rateOrder = append(rateOrder, rate.From)
+			}
+		}
+	}
+
+	result := &model.DeclaredValue{
+		Amount:   roundTo2Decimals(total),
+		Currency: base,
+	}
+
+	if preferredCurrency != "" {
+		preferred, rate, err := converter.Convert(result.Amount, base, preferredCurrency)
+		if err != nil {
+			issues = append(issues, model.AnomalyItem{
+				Type:    model.AnomalyTypeUnknownCurrency,
+				Level:   model.AnomalyLevelInfo,
+				Message: fmt.Sprintf("Preferred currency %q is not supported", preferredCurrency),
+			})
+		} else {
+			result.Preferred = preferred
+			if rate.From != rate.To {
+				rates[rate.From+"->"+rate.To] = *rate
+				rateOrder = append(rateOrder, rate.From+"->"+rate.To)
+			}
+		}
+	}
+
+	for _, key := range rateOrder {
+		result.ExchangeRates = append(result.ExchangeRates, rates[key])
+	}
+
+	return result, issues
+}
+
+// remoteArea 收件邮编命中任一承运商的偏远/扩展区域时生成 REMOTE_AREA 异常
+func (c *AnomalyChecker) remoteArea(rec *explain.Recorder, shipment *model.Shipment) *model.AnomalyItem {
+	if c.surcharges == nil {
+		return nil
+	}
+
+	addr := shipToAddress(shipment)
+	if addr == nil || addr.PostalCode == "" {
+		return nil
+	}
+
+	matches := c.surcharges.MatchAreas(addr.Country, addr.PostalCode)
+	carriers := make([]string, 0, len(matches))
+	for _, match := range matches {
+		carriers = append(carriers, fmt.Sprintf("%s(%s)", match.Carrier, match.Area.Type))
+	}
+	rec.Rule(model.AnomalyTypeRemoteArea, len(matches) > 0, map[string]interface{}{
+		"country":           addr.Country,
+		"postal_code":       addr.PostalCode,
+		"matched_areas":     carriers,
+		"surcharge_version": c.surcharges.Version(),
+	})
+	if len(matches) == 0 {
+		return nil
+	}
+
+	return &model.AnomalyItem{
+		Type:    model.AnomalyTypeRemoteArea,
+		Level:   model.AnomalyLevelWarning,
+		Message: fmt.Sprintf("Destination %s %s is in a remote delivery area, surcharges apply: %s", addr.Country, addr.PostalCode, strings.Join(carriers, ", ")),
+	}
+}
+
+// duplicateOrders 时间窗口内同一收件人、同一地址且 SKU 有交集的近期订单
+// SKU 集合完全相同视为重复下单（换了商家订单号重新提交），部分重叠视为拆单
+func (c *AnomalyChecker) duplicateOrders(ctx context.Context, rec *explain.Recorder, input *DiagnoseInput) []model.AnomalyItem {
+	if c.duplicates == nil || input.Shipment.ShipTo == nil {
+		return nil
+	}
+
+	matches, err := c.duplicates.Check(ctx, dedup.Entry{
+		OrderID:         input.OrderID,
+		AccountID:       input.AccountID,
+		MerchantOrderNo: input.MerchantOrderNo,
+		AddressKey:      recipientKey(input.Shipment.ShipTo),
+		SKUs:            shipmentSKUs(input.Shipment),
+		CreatedAt:       input.OrderCreatedAt,
+	})
+	if err != nil {
+		// 索引不可用时不影响其他规则
+		return []model.AnomalyItem{{
+			Type:    model.AnomalyTypeDuplicateCheck,
+			Level:   model.AnomalyLevelInfo,
+			Message: "Duplicate order check unavailable: " + err.Error(),
+		}}
+	}
+
+	window := fmt.Sprintf("%gh", c.duplicates.Window().Hours())
+	duplicates := make([]dedup.Match, 0)
+	splits := make([]dedup.Match, 0)
+	for _, match := range matches {
+		if match.Identical {
+			duplicates = append(duplicates, match)
+		} else {
+			splits = append(splits, match)
+		}
+	}
+
+	rec.Rule(model.AnomalyTypeDuplicateOrder, len(duplicates) > 0, map[string]interface{}{
+		"window":    window,
+		"order_ids": matchedOrderIDs(duplicates),
+	})
+	rec.Rule(model.AnomalyTypeSplitOrder, len(splits) > 0, map[string]interface{}{
+		"window":    window,
+		"order_ids": matchedOrderIDs(splits),
+	})
+
+	issues := make([]model.AnomalyItem, 0, 2)
+	if len(duplicates) > 0 {
+		issues = append(issues, model.AnomalyItem{
+			Type:            model.AnomalyTypeDuplicateOrder,
+			Level:           model.AnomalyLevelCritical,
+			Message:         fmt.Sprintf("Same recipient and SKUs as %d order(s) within %s: %s", len(duplicates), window, merchantOrderNos(duplicates)),
+			RelatedOrderIDs: matchedOrderIDs(duplicates),
+		})
+	}
+	if len(splits) > 0 {
+		issues = append(issues, model.AnomalyItem{
+			Type:            model.AnomalyTypeSplitOrder,
+			Level:           model.AnomalyLevelWarning,
+			Message:         fmt.Sprintf("Same recipient with overlapping SKUs as %d order(s) within %s: %s", len(splits), window, merchantOrderNos(splits)),
+			RelatedOrderIDs: matchedOrderIDs(splits),
+		})
+	}
+	return issues
+}
+
+// parcelWeights 各包裹重量（千克，用于解释总重的构成）
+func parcelWeights(shipment *model.Shipment) []float64 {
+	weights := make([]float64, 0, len(shipment.Parcels))
+	for _, parcel := range shipment.Parcels {
+		weights = append(weights, parcel.Weight.Kilograms())
+	}
+	return weights
+}
+
+// shipmentSKUs 货件中所有商品的 SKU（去重与归一化由 dedup 负责）
+func shipmentSKUs(shipment *model.Shipment) []string {
+	skus := make([]string, 0)
+	for _, parcel := range shipment.Parcels {
+		for _, item := range parcel.Items {
+			skus = append(skus, item.SKU)
+		}
+	}
+	return skus
+}
+
+// matchedOrderIDs 疑似重复订单 ID 列表
+func matchedOrderIDs(matches []dedup.Match) []string {
+	ids := make([]string, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.OrderID)
+	}
+	return ids
+}
+
+// merchantOrderNos 疑似重复订单的商家订单号及共有 SKU（用于提示文案）
+func merchantOrderNos(matches []dedup.Match) string {
+	parts := make([]string, 0, len(matches))
+	for _, match := range matches {
+		parts = append(parts, fmt.Sprintf("%s [%s]", match.MerchantOrderNo, strings.Join(match.SharedSKUs, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
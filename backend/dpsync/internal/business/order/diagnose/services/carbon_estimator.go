@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/carbon"
+	"oip/dpsync/internal/business/explain"
+)
+
+// carbonRulesVersion 碳排放估算口径版本（调整计费重或距离口径时需同步更新；排放因子随数据版本变化）
+const carbonRulesVersion = "carbon-2025-12"
+
+// CarbonEstimator 运输碳排放估算器
+// 候选费率取本次任务共享的 shipping 费率结果（与 shipping 诊断项同一份报价），逐个按计费重、线路距离与运输方式估算 CO2e
+type CarbonEstimator struct {
+	rateVersion func() string // 费率计算器版本（计入组合版本）
+	factors     *carbon.Service
+}
+
+// NewCarbonEstimator 创建碳排放估算器实例
+func NewCarbonEstimator(rateVersion func() string, factors *carbon.Service) *CarbonEstimator {
+	return &CarbonEstimator{
+		rateVersion: rateVersion,
+		factors:     factors,
+	}
+}
+
+// Type 诊断类型
+func (e *CarbonEstimator) Type() string {
+	return model.DiagnosisTypeCarbon
+}
+
+// Diagnose 执行碳排放诊断（实现 Diagnoser 接口）
+// 候选费率来自降级报价时结果不写入缓存（与 shipping 诊断一致）
+func (e *CarbonEstimator) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	result, degraded, err := e.Estimate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if degraded {
+		return noCache{data: result}, nil
+	}
+	return result, nil
+}
+
+// ResultSchema 诊断结果结构
+func (e *CarbonEstimator) ResultSchema() interface{} {
+	return &model.CarbonResult{}
+}
+
+// Version 估算口径与费率计算器的组合版本（实现 VersionedDiagnoser 接口，费率计算器版本已包含排放因子版本）
+func (e *CarbonEstimator) Version() string {
+	return "rules:" + carbonRulesVersion + ";" + e.rateVersion()
+}
+
+// OptionsKey 候选费率依赖的选项（实现 OptionsKeyer 接口，与 shipping 诊断一致）
+func (e *CarbonEstimator) OptionsKey(input *DiagnoseInput) string {
+	return input.rateOptionsKey()
+}
+
+// Estimate 估算各候选费率的碳排放
+// 第二个返回值表示候选费率中存在承运商报价失败（结果不宜缓存）
+func (e *CarbonEstimator) Estimate(ctx context.Context, input *DiagnoseInput) (*model.CarbonResult, bool, error) {
+	if e.factors == nil {
+		return nil, false, fmt.Errorf("carbon factors not configured")
+	}
+
+	shipping, err := input.shippingRates(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	weight := billableWeightKg(input.Shipment)
+	result := &model.CarbonResult{
+		BillableWeightKg: roundTo2Decimals(weight),
+		DistanceKm:       laneDistance(shipping.Lane),
+		Options:          make([]model.CarbonOption, 0, len(shipping.Rates)),
+		FactorsVersion:   e.factors.Version(),
+	}
+	degraded := len(shipping.CarrierErrors) > 0
+
+	rec := explain.FromContext(ctx)
+	rec.Input("billable_weight_kg", result.BillableWeightKg)
+	rec.Input("distance_km", result.DistanceKm)
+
+	switch {
+	case shipping.Lane == nil:
+		result.Unavailable = "origin or destination country is missing"
+		return result, degraded, nil
+	case result.DistanceKm == nil:
+		result.Unavailable = "lane distance unknown: origin or destination postal code could not be located"
+		return result, degraded, nil
+	}
+
+	for _, rate := range shipping.Rates {
+		estimate := e.factors.Estimate(rate.Carrier, rate.Service, weight, *result.DistanceKm)
+		rec.Rule("EMISSION_ESTIMATE", true, map[string]interface{}{
+			"carrier":     rate.Carrier,
+			"service":     rate.Service,
+			"mode":        estimate.Mode,
+			"distance_km": estimate.DistanceKm,
+			"co2e_kg":     estimate.Co2eKg,
+		})
+		result.Options = append(result.Options, model.CarbonOption{
+			Carrier:    rate.Carrier,
+			Service:    rate.Service,
+			Mode:       estimate.Mode,
+			DistanceKm: estimate.DistanceKm,
+			Co2eKg:     estimate.Co2eKg,
+			TotalFee:   rate.TotalFee,
+			Currency:   rate.Currency,
+			Tags:       rate.Tags,
+		})
+	}
+
+	sort.SliceStable(result.Options, func(i, j int) bool {
+		if result.Options[i].Co2eKg != result.Options[j].Co2eKg {
+			return result.Options[i].Co2eKg < result.Options[j].Co2eKg
+		}
+		return result.Options[i].TotalFee < result.Options[j].TotalFee
+	})
+	if len(result.Options) > 0 {
+		lowest := result.Options[0]
+		result.LowestEmissionCode = fmt.Sprintf("%s_%s", lowest.Carrier, lowest.Service)
+	}
+
+	return result, degraded, nil
+}
+
+// billableWeightKg 货件计费重合计（各包裹实重与体积重取大，体积重口径与包装诊断一致）
+func billableWeightKg(shipment *model.Shipment) float64 {
+	if shipment == nil {
+		return 0
+	}
+	total := 0.0
+	for _, parcel := range shipment.Parcels {
+		total += math.Max(parcel.Weight.Kilograms(), parcel.Dimension.CubicCentimeters()/dimDivisor)
+	}
+	return total
+}
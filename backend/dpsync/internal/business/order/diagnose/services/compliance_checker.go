@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+)
+
+// ComplianceChecker 海关合规检测器（HS 编码校验 + 品名检查 + 禁限运筛查）
+type ComplianceChecker struct{}
+
+// NewComplianceChecker 创建合规检测器实例
+func NewComplianceChecker() *ComplianceChecker {
+	return &ComplianceChecker{}
+}
+
+// Type 诊断类型
+func (c *ComplianceChecker) Type() string {
+	return model.DiagnosisTypeCompliance
+}
+
+// Diagnose 执行海关合规诊断（实现 Diagnoser 接口）
+func (c *ComplianceChecker) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	return c.Check(ctx, input.Shipment)
+}
+
+// ResultSchema 诊断结果结构
+func (c *ComplianceChecker) ResultSchema() interface{} {
+	return &model.ComplianceResult{}
+}
+
+// Version 合规规则版本（实现 VersionedDiagnoser 接口）
+func (c *ComplianceChecker) Version() string {
+	return "rules:" + complianceRulesVersion
+}
+
+// Check 执行合规检测
+// 参数 shipment 为物流信息，使用 ship_to.country 作为目的国，逐个检查 parcels[].items[]
+func (c *ComplianceChecker) Check(ctx context.Context, shipment *model.Shipment) (*model.ComplianceResult, error) {
+	rec := explain.FromContext(ctx)
+	destination := destinationCountry(shipment)
+	rec.Input("destination", destination)
+	rec.Input("destination_rule_count", len(destinationComplianceRules[destination]))
+
+	result := &model.ComplianceResult{
+		Destination: destination,
+		Items:       make([]model.ComplianceItem, 0),
+	}
+
+	if shipment == nil {
+		return result, nil
+	}
+
+	for i, parcel := range shipment.Parcels {
+		for j, item := range parcel.Items {
+			complianceItem := model.ComplianceItem{
+				ParcelIndex:   i,
+				ItemIndex:     j,
+				SKU:           strings.TrimSpace(item.SKU),
+				Description:   strings.TrimSpace(item.Description),
+				HSCode:        strings.TrimSpace(item.HSCode),
+				OriginCountry: normalizeCountry(item.OriginCountry),
+				Battery:       item.Battery,
+			}
+			complianceItem.Issues = c.checkItem(destination, complianceItem)
+			if rec.Enabled() {
+				matched := make([]string, 0, len(complianceItem.Issues))
+				for _, issue := range complianceItem.Issues {
+					matched = append(matched, issue.Type)
+				}
+				rec.Rule("ITEM_COMPLIANCE", len(matched) > 0, map[string]interface{}{
+					"parcel_index":      i,
+					"item_index":        j,
+					"hs_code":           complianceItem.HSCode,
+					"description_words": normalizeDescription(complianceItem.Description),
+					"issues":            matched,
+				})
+			}
+
+			if len(complianceItem.Issues) > 0 {
+				result.Items = append(result.Items, complianceItem)
+			}
+		}
+	}
+
+	result.HasIssue = len(result.Items) > 0
+
+	return result, nil
+}
+
+// checkItem 检查单个商品，返回命中的问题列表
+func (c *ComplianceChecker) checkItem(destination string, item model.ComplianceItem) []model.ComplianceIssue {
+	issues := make([]model.ComplianceIssue, 0)
+
+	// 规则 1：HS 编码格式
+	if item.HSCode == "" {
+		issues = append(issues, model.ComplianceIssue{
+			Type:    model.ComplianceTypeMissingHSCode,
+			Level:   model.AnomalyLevelInfo,
+			Message: "HS code not provided; customs may classify the item manually",
+		})
+	} else if err := validateHSCode(item.HSCode); err != nil {
+		issues = append(issues, model.ComplianceIssue{
+			Type:    model.ComplianceTypeInvalidHSCode,
+			Level:   model.AnomalyLevelWarning,
+			Message: fmt.Sprintf("HS code %q is invalid: %v", item.HSCode, err),
+		})
+	}
+
+	// 规则 2：笼统品名
+	words := normalizeDescription(item.Description)
+	if isVagueDescription(words) {
+		issues = append(issues, model.ComplianceIssue{
+			Type:    model.ComplianceTypeVagueDescription,
+			Level:   model.AnomalyLevelWarning,
+			Message: fmt.Sprintf("Description %q is too vague for customs; describe what the item is and what it is made of", item.Description),
+		})
+	}
+
+	// 规则 3：申报的锂电池（优先于品名关键词，同一商品只报告一条危险品问题）
+	matched := make(map[string]bool)
+	if issue, ok := batteryIssue(item.Battery); ok {
+		matched[issue.Type] = true
+		issues = append(issues, issue)
+	}
+
+	// 规则 4：危险品 + 目的国禁限运关键词
+	rules := make([]complianceKeywordRule, 0, len(globalComplianceRules))
+	rules = append(rules, globalComplianceRules...)
+	rules = append(rules, destinationComplianceRules[destination]...)
+
+	padded := " " + strings.Join(words, " ") + " "
+	for _, rule := range rules {
+		if matched[rule.Type] {
+			// 同一类型只报告第一条（规则按严重程度排列）
+			continue
+		}
+		if strings.Contains(padded, " "+rule.Keyword+" ") {
+			matched[rule.Type] = true
+			issues = append(issues, model.ComplianceIssue{
+				Type:    rule.Type,
+				Level:   rule.Level,
+				Keyword: rule.Keyword,
+				Message: rule.Reason,
+			})
+		}
+	}
+
+	return issues
+}
+
+// validateHSCode 校验 HS 编码格式
+// 允许 "." 与空格分隔，去除后须为 6/8/10 位数字，前两位为有效章号（01-97，77 为保留章）
+func validateHSCode(hsCode string) error {
+	digits := strings.NewReplacer(".", "", " ", "").Replace(hsCode)
+
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("must contain digits only")
+		}
+	}
+
+	switch len(digits) {
+	case 6, 8, 10:
+	default:
+		return fmt.Errorf("must be 6, 8 or 10 digits, got %d", len(digits))
+	}
+
+	chapter := int(digits[0]-'0')*10 + int(digits[1]-'0')
+	if chapter < 1 || chapter > 97 || chapter == 77 {
+		return fmt.Errorf("chapter %02d does not exist", chapter)
+	}
+
+	return nil
+}
+
+// normalizeDescription 品名归一化：转小写，非字母数字字符视为分隔符
+func normalizeDescription(description string) []string {
+	return strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// isVagueDescription 去除修饰词后，品名为空或仅由笼统词构成
+func isVagueDescription(words []string) bool {
+	for _, w := range words {
+		if descriptionFillerWords[w] {
+			continue
+		}
+		if !vagueDescriptionTerms[w] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"oip/common/model"
+)
+
+// complianceRulesVersion 合规规则版本（调整关键词或 HS 校验规则时需同步更新）
+const complianceRulesVersion = "compliance-2026-10"
+
+// complianceKeywordRule 描述关键词规则
+type complianceKeywordRule struct {
+	Keyword string // 归一化后的关键词（小写、单词间单空格）
+	Type    string // RESTRICTED_ITEM/DANGEROUS_GOODS
+	Level   string // INFO/WARNING/CRITICAL
+	Reason  string // 规则说明
+}
+
+// globalComplianceRules 对所有目的国生效的危险品规则（主要针对航空运输）
+var globalComplianceRules = []complianceKeywordRule{
+	{"lithium", model.ComplianceTypeDangerousGoods, model.AnomalyLevelCritical, "lithium batteries are UN3480/UN3481 dangerous goods and need DG declaration"},
+	{"li ion", model.ComplianceTypeDangerousGoods, model.AnomalyLevelCritical, "lithium-ion batteries are UN3480/UN3481 dangerous goods and need DG declaration"},
+	{"power bank", model.ComplianceTypeDangerousGoods, model.AnomalyLevelCritical, "power banks are shipped as standalone lithium batteries (UN3480)"},
+	{"battery", model.ComplianceTypeDangerousGoods, model.AnomalyLevelWarning, "batteries may be regulated; declare chemistry and watt-hour rating"},
+	{"batteries", model.ComplianceTypeDangerousGoods, model.AnomalyLevelWarning, "batteries may be regulated; declare chemistry and watt-hour rating"},
+	{"aerosol", model.ComplianceTypeDangerousGoods, model.AnomalyLevelCritical, "aerosols are class 2 dangerous goods"},
+	{"perfume", model.ComplianceTypeDangerousGoods, model.AnomalyLevelWarning, "perfume is a flammable liquid (UN1266) and is restricted by air"},
+	{"nail polish", model.ComplianceTypeDangerousGoods, model.AnomalyLevelWarning, "nail polish is a flammable liquid (UN1263)"},
+	{"lighter", model.ComplianceTypeDangerousGoods, model.AnomalyLevelCritical, "lighters are forbidden in air cargo"},
+	{"flammable", model.ComplianceTypeDangerousGoods, model.AnomalyLevelCritical, "flammable goods require DG handling"},
+	{"dry ice", model.ComplianceTypeDangerousGoods, model.AnomalyLevelWarning, "dry ice is class 9 dangerous goods (UN1845)"},
+	{"magnet", model.ComplianceTypeDangerousGoods, model.AnomalyLevelInfo, "strong magnets may require magnetic field testing for air transport"},
+}
+
+// destinationComplianceRules 按目的国（ISO 两位代码）生效的禁限运规则
+var destinationComplianceRules = map[string][]complianceKeywordRule{
+	"US": {
+		{"kinder surprise", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "confectionery with embedded toys is prohibited by FDA"},
+		{"cuban cigar", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "Cuban-origin tobacco is prohibited"},
+		{"absinthe", model.ComplianceTypeRestrictedItem, model.AnomalyLevelWarning, "absinthe is restricted by TTB thujone limits"},
+		{"seeds", model.ComplianceTypeRestrictedItem, model.AnomalyLevelWarning, "seeds require USDA phytosanitary certificate"},
+	},
+	"CN": {
+		{"e cigarette", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "e-cigarettes are prohibited for cross-border parcels"},
+		{"seeds", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "plant seeds are prohibited without quarantine permit"},
+		{"meat", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "meat products are prohibited"},
+		{"publication", model.ComplianceTypeRestrictedItem, model.AnomalyLevelWarning, "printed publications are subject to content review"},
+	},
+	"AU": {
+		{"seeds", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "seeds are subject to biosecurity import conditions"},
+		{"honey", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "honey and bee products are prohibited"},
+		{"e cigarette", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "nicotine vaping products need a prescription"},
+	},
+	"GB": {
+		{"pepper spray", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "pepper spray is a prohibited weapon"},
+		{"knife", model.ComplianceTypeRestrictedItem, model.AnomalyLevelWarning, "bladed articles require age-verified delivery"},
+	},
+	"DE": {
+		{"pepper spray", model.ComplianceTypeRestrictedItem, model.AnomalyLevelWarning, "pepper spray must be labelled for animal defence only"},
+	},
+	"JP": {
+		{"e cigarette", model.ComplianceTypeRestrictedItem, model.AnomalyLevelWarning, "nicotine liquids are regulated as pharmaceuticals"},
+		{"meat", model.ComplianceTypeRestrictedItem, model.AnomalyLevelCritical, "meat products require animal quarantine certificate"},
+	},
+}
+
+// vagueDescriptionTerms 海关不接受的笼统品名
+var vagueDescriptionTerms = map[string]bool{
+	"gift":        true,
+	"gifts":       true,
+	"sample":      true,
+	"samples":     true,
+	"parts":       true,
+	"part":        true,
+	"accessories": true,
+	"accessory":   true,
+	"goods":       true,
+	"merchandise": true,
+	"stuff":       true,
+	"misc":        true,
+	"other":       true,
+	"present":     true,
+}
+
+// descriptionFillerWords 判断笼统品名时忽略的修饰词
+var descriptionFillerWords = map[string]bool{
+	"a":    true,
+	"an":   true,
+	"the":  true,
+	"free": true,
+	"of":   true,
+	"item": true,
+	"for":  true,
+	"my":   true,
+}
+
+// batteryIssue 申报锂电池对应的危险品问题（非锂电池或未申报时不报告）
+// 单独运输的锂电池为完全受限危险品，安装在设备中或与设备同包装的按 Section II 处理
+func batteryIssue(battery *model.Battery) (model.ComplianceIssue, bool) {
+	if !battery.IsLithium() {
+		return model.ComplianceIssue{}, false
+	}
+
+	standaloneUN, equipmentUN := "UN3480", "UN3481"
+	if battery.Chemistry == model.BatteryChemistryLithiumMetal {
+		standaloneUN, equipmentUN = "UN3090", "UN3091"
+	}
+
+	if battery.IsStandalone() {
+		return model.ComplianceIssue{
+			Type:    model.ComplianceTypeDangerousGoods,
+			Level:   model.AnomalyLevelCritical,
+			Message: fmt.Sprintf("standalone lithium batteries are %s dangerous goods and need DG declaration", standaloneUN),
+		}, true
+	}
+	return model.ComplianceIssue{
+		Type:    model.ComplianceTypeDangerousGoods,
+		Level:   model.AnomalyLevelWarning,
+		Message: fmt.Sprintf("lithium batteries packed with or contained in equipment are %s; check watt-hour rating and lithium battery mark requirements", equipmentUN),
+	}, true
+}
+
+// containsDangerousGoods 货件中是否含危险品（申报了锂电池，或命中 WARNING 及以上级别的危险品关键词）
+// 用于承运商服务约束过滤，INFO 级别（如磁性物品）不影响服务可用性
+func containsDangerousGoods(shipment *model.Shipment) bool {
+	if shipment == nil {
+		return false
+	}
+
+	for _, parcel := range shipment.Parcels {
+		for _, item := range parcel.Items {
+			if item.Battery.IsLithium() {
+				return true
+			}
+			padded := " " + strings.Join(normalizeDescription(item.Description), " ") + " "
+			for _, rule := range globalComplianceRules {
+				if rule.Type != model.ComplianceTypeDangerousGoods || rule.Level == model.AnomalyLevelInfo {
+					continue
+				}
+				if strings.Contains(padded, " "+rule.Keyword+" ") {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
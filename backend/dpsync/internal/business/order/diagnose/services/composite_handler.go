@@ -2,104 +2,308 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
 
 	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
 )
 
 // DiagnoseInput 诊断输入参数
 type DiagnoseInput struct {
-	RequestID       string
-	OrderID         string
-	AccountID       int64
-	MerchantOrderNo string
-	Shipment        map[string]interface{}
+	RequestID            string
+	OrderID              string
+	AccountID            int64
+	MerchantOrderNo      string
+	Shipment             *model.Shipment
+	Diagnosers           []string                      // 本次需要执行的诊断类型（为空时执行全部已注册诊断器）
+	PreferredCurrency    string                        // 账号偏好币种（为空时只输出原币种金额）
+	Strategy             *model.RecommendationStrategy // 费率推荐策略（为空时推荐最便宜）
+	Contracts            []model.RateContract          // 账号协议价（为空时按牌价计算）
+	OrderCreatedAt       time.Time                     // 下单时间（时效估算起点）
+	PromisedDeliveryDate string                        // 承诺送达日（YYYY-MM-DD，为空表示未承诺）
+	Boxes                []model.Box                   // 账号箱型目录（为空时不给出换箱建议）
+	Incoterm             string                        // 贸易术语 DDP/DDU（为空时按 DDU 估算到岸成本）
+	Account              *model.AccountProfile         // 下单账号概况（为空时不评估新账号风险）
+	Selected             *model.SelectedService        // 商家选用的承运商服务（为空时不核对多付运费）
+	Enrichments          []model.ItemEnrichment        // 下单时由商品目录补全的商品字段（不参与诊断，原样写入结果）
+	Explain              bool                          // explain 模式：记录各诊断器的输入、规则中间值与费率卡行
+
+	rates *sharedRates // 本次任务共享的 shipping 费率结果（由 CompositeHandler 创建）
 }
 
+// rateOptionsKey 影响 shipping 费率结果的选项摘要（依赖共享费率的诊断器同样使用）
+// 送达日期估算依赖下单时间，按分钟取整计入（同一订单重新诊断仍可命中缓存）
+func (in *DiagnoseInput) rateOptionsKey() string {
+	key := in.PreferredCurrency + ";" + in.Strategy.Key() + ";" + in.createdAtKey() + ";" + in.PromisedDeliveryDate
+	if len(in.Contracts) > 0 {
+		key += ";contracts:" + digest(in.Contracts)
+	}
+	return key
+}
+
+// createdAtKey 下单时间按分钟取整（用于缓存键）
+func (in *DiagnoseInput) createdAtKey() string {
+	return in.OrderCreatedAt.UTC().Truncate(time.Minute).Format(time.RFC3339)
+}
+
+// digest 选项内容摘要（JSON 序列化后取 sha256 前 8 字节）
+func digest(v interface{}) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// engineVersion 诊断引擎版本（调整调度、缓存或结果结构时需同步更新），随诊断结果回传用于结果版本记录
+const engineVersion = "engine-2026-10"
+
 // CompositeHandler 复合诊断处理器
+// 按输入选择的诊断类型从 Registry 取出诊断器并发执行
 type CompositeHandler struct {
-	shippingCalc   *ShippingCalculator
-	anomalyChecker *AnomalyChecker
+	registry *Registry
+	cache    ResultCache   // 可选，为 nil 时不缓存
+	shadows  *ShadowRunner // 可选，为 nil 时不执行影子诊断
 }
 
 // NewCompositeHandler 创建复合诊断处理器实例
-func NewCompositeHandler() *CompositeHandler {
+func NewCompositeHandler(registry *Registry, cache ResultCache, shadows *ShadowRunner) *CompositeHandler {
 	return &CompositeHandler{
-		shippingCalc:   NewShippingCalculator(),
-		anomalyChecker: NewAnomalyChecker(),
+		registry: registry,
+		cache:    cache,
+		shadows:  shadows,
 	}
 }
 
 // Diagnose 执行完整的订单诊断流程
-// 返回 DiagnosisResultData（包含 shipping 和 anomaly 两个诊断项）
+// 所有选中的诊断器并发执行，各自拥有独立的超时时间；单个诊断器失败或超时只影响自己的诊断项
+// 返回 DiagnosisResultData（诊断项顺序与选择顺序一致，任一诊断项失败时 Partial=true）
+// explain 模式下不读取缓存（缓存的诊断项没有执行轨迹），Traces 与 Items 一一对应
 func (h *CompositeHandler) Diagnose(ctx context.Context, input *DiagnoseInput) (*model.DiagnosisResultData, error) {
-	items := make([]model.DiagnosisItem, 0, 2)
+	types := h.registry.resolve(input.Diagnosers)
+	items := make([]model.DiagnosisItem, len(types))
+	recorders := make([]*explain.Recorder, len(types))
 
-	// 1. 物流费率诊断
-	shippingItem := h.diagnoseShipping(ctx, input)
-	items = append(items, shippingItem)
+	fingerprint := ""
+	if h.cache != nil {
+		fingerprint = input.Shipment.Fingerprint()
+	}
 
-	// 2. 异常检测诊断
-	anomalyItem := h.diagnoseAnomaly(ctx, input)
-	items = append(items, anomalyItem)
+	// 先查缓存：shipping 诊断项命中缓存时作为本次任务共享的费率结果，依赖费率的诊断器与其保持一致
+	entries := make([]registeredDiagnoser, len(types))
+	cacheKeys := make([]string, len(types))
+	pending := make([]bool, len(types))
+	for i, diagnosisType := range types {
+		if input.Explain {
+			recorders[i] = explain.NewRecorder(diagnosisType)
+		}
 
-	return &model.DiagnosisResultData{
-		Items: items,
-	}, nil
-}
+		entry, ok := h.registry.diagnosers[diagnosisType]
+		if !ok {
+			items[i] = failedItem(diagnosisType, "unknown diagnoser: "+diagnosisType)
+			continue
+		}
+		entries[i] = entry
 
-// diagnoseShipping 执行物流费率诊断
-func (h *CompositeHandler) diagnoseShipping(ctx context.Context, input *DiagnoseInput) model.DiagnosisItem {
-	result, err := h.shippingCalc.Calculate(ctx, input.OrderID, input.Shipment)
-	if err != nil {
-		return model.DiagnosisItem{
-			Type:   model.DiagnosisTypeShipping,
-			Status: model.DiagnosisStatusFailed,
-			Error:  err.Error(),
+		// 命中缓存的诊断项直接复用
+		cacheKeys[i] = h.cacheKey(entry, input, fingerprint)
+		if cacheKeys[i] != "" && !input.Explain {
+			if item, ok := h.cache.Get(cacheKeys[i]); ok {
+				item.Version = diagnoserVersion(entry)
+				items[i] = item
+				continue
+			}
 		}
+		pending[i] = true
 	}
 
-	// 序列化结果为 JSON
-	dataJSON, err := json.Marshal(result)
-	if err != nil {
-		return model.DiagnosisItem{
-			Type:   model.DiagnosisTypeShipping,
-			Status: model.DiagnosisStatusFailed,
-			Error:  "Failed to marshal shipping result: " + err.Error(),
+	input.rates = h.newSharedRates(ctx, types, items, recorders)
+
+	var wg sync.WaitGroup
+	for i := range types {
+		if !pending[i] {
+			continue
 		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			item, cacheable := runDiagnoser(ctx, entries[i], input, recorders[i])
+			item.Version = diagnoserVersion(entries[i])
+			items[i] = item
+			if cacheKeys[i] != "" && cacheable {
+				h.cache.Set(cacheKeys[i], item)
+			}
+		}(i)
 	}
+	wg.Wait()
 
-	return model.DiagnosisItem{
-		Type:     model.DiagnosisTypeShipping,
-		Status:   model.DiagnosisStatusSuccess,
-		DataJSON: dataJSON,
+	partial := false
+	for _, item := range items {
+		if item.Status != model.DiagnosisStatusSuccess {
+			partial = true
+			break
+		}
+	}
+
+	result := &model.DiagnosisResultData{
+		EngineVersion: engineVersion,
+		Items:         items,
+		Partial:       partial,
+		Enrichments:   input.Enrichments,
+	}
+	if input.Explain {
+		result.Traces = make([]model.DiagnoserTrace, len(recorders))
+		for i, rec := range recorders {
+			result.Traces[i] = rec.Trace()
+		}
 	}
+
+	return result, nil
 }
 
-// diagnoseAnomaly 执行异常检测诊断
-func (h *CompositeHandler) diagnoseAnomaly(ctx context.Context, input *DiagnoseInput) model.DiagnosisItem {
-	result, err := h.anomalyChecker.Check(ctx, input.Shipment)
-	if err != nil {
-		return model.DiagnosisItem{
-			Type:   model.DiagnosisTypeAnomaly,
-			Status: model.DiagnosisStatusFailed,
-			Error:  err.Error(),
+// RunShadows 异步执行影子诊断（未配置影子诊断器时不做任何事）
+// 影子诊断在回调发出后开始，不占用线上诊断的超时预算，结果只写入影子结果存储，不影响 live 结果与回调；
+// 并发上限与退出等待由 ShadowRunner 负责
+func (h *CompositeHandler) RunShadows(ctx context.Context, input *DiagnoseInput, live *model.DiagnosisResultData) {
+	if h.shadows == nil || live == nil {
+		return
+	}
+	h.shadows.Submit(ctx, input, live)
+}
+
+// newSharedRates 创建本次任务共享的 shipping 费率结果
+// 计算使用注册表中的 shipping 诊断器及其超时，shipping 被选中时轨迹记入其记录器；shipping 诊断项命中缓存时直接使用缓存结果
+func (h *CompositeHandler) newSharedRates(ctx context.Context, types []string, items []model.DiagnosisItem, recorders []*explain.Recorder) *sharedRates {
+	entry, ok := h.registry.diagnosers[model.DiagnosisTypeShipping]
+	if !ok {
+		return newSharedRates(ctx, nil, 0, nil)
+	}
+	calculator, _ := entry.diagnoser.(rateCalculator)
+
+	var rec *explain.Recorder
+	cached := -1
+	for i, diagnosisType := range types {
+		if diagnosisType != model.DiagnosisTypeShipping {
+			continue
+		}
+		rec = recorders[i]
+		if items[i].Status == model.DiagnosisStatusSuccess {
+			cached = i
 		}
 	}
 
-	// 序列化结果为 JSON
-	dataJSON, err := json.Marshal(result)
-	if err != nil {
-		return model.DiagnosisItem{
-			Type:   model.DiagnosisTypeAnomaly,
-			Status: model.DiagnosisStatusFailed,
-			Error:  "Failed to marshal anomaly result: " + err.Error(),
+	rates := newSharedRates(ctx, calculator, entry.timeout, rec)
+	if cached >= 0 {
+		rates.seed(items[cached])
+	}
+	return rates
+}
+
+// cacheKey 计算诊断项缓存键，未启用缓存或诊断器不可缓存时返回空串
+func (h *CompositeHandler) cacheKey(entry registeredDiagnoser, input *DiagnoseInput, fingerprint string) string {
+	if h.cache == nil || fingerprint == "" {
+		return ""
+	}
+	versioned, ok := entry.diagnoser.(VersionedDiagnoser)
+	if !ok {
+		return ""
+	}
+	options := ""
+	if keyer, ok := entry.diagnoser.(OptionsKeyer); ok {
+		options = keyer.OptionsKey(input)
+	}
+	return resultCacheKey(versioned.Type(), versioned.Version(), options, fingerprint)
+}
+
+// diagnoserVersion 诊断器当前的规则/费率卡版本（未实现 VersionedDiagnoser 时为空）
+func diagnoserVersion(entry registeredDiagnoser) string {
+	if versioned, ok := entry.diagnoser.(VersionedDiagnoser); ok {
+		return versioned.Version()
+	}
+	return ""
+}
+
+// diagnoserOutcome 诊断器执行结果（用于在 goroutine 间传递）
+type diagnoserOutcome struct {
+	data interface{}
+	err  error
+}
+
+// noCache 包装诊断结果，标记该结果不写入 ResultCache（如承运商接口降级时的报价）
+type noCache struct {
+	data interface{}
+}
+
+// MarshalJSON 序列化为被包装的结果
+func (n noCache) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.data)
+}
+
+// runDiagnoser 在独立超时 Context 下执行单个诊断器，并将结果包装为 DiagnosisItem
+// 第二个返回值表示结果是否可以缓存（仅成功且诊断器未标记 noCache 的结果可缓存）
+// rec 非空时放入诊断器 Context，超时后已记录的部分轨迹仍会保留
+func runDiagnoser(ctx context.Context, entry registeredDiagnoser, input *DiagnoseInput, rec *explain.Recorder) (model.DiagnosisItem, bool) {
+	diagnosisType := entry.diagnoser.Type()
+
+	diagCtx, cancel := context.WithTimeout(ctx, entry.timeout)
+	defer cancel()
+	if rec != nil {
+		diagCtx = explain.WithRecorder(diagCtx, rec)
+	}
+
+	// 带缓冲，超时返回后诊断器 goroutine 仍可写入并退出，避免泄漏
+	outcomeCh := make(chan diagnoserOutcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				outcomeCh <- diagnoserOutcome{err: fmt.Errorf("diagnoser panicked: %v", r)}
+			}
+		}()
+		data, err := entry.diagnoser.Diagnose(diagCtx, input)
+		outcomeCh <- diagnoserOutcome{data: data, err: err}
+	}()
+
+	select {
+	case outcome := <-outcomeCh:
+		if outcome.err != nil {
+			return failedItem(diagnosisType, outcome.err.Error()), false
+		}
+		_, uncached := outcome.data.(noCache)
+		item := buildItem(diagnosisType, outcome.data)
+		return item, !uncached && item.Status == model.DiagnosisStatusSuccess
+
+	case <-diagCtx.Done():
+		if ctx.Err() != nil {
+			// 整体 Context 已取消（如 Processor 超时），非诊断器自身超时
+			return failedItem(diagnosisType, "diagnosis cancelled: "+ctx.Err().Error()), false
 		}
+		return failedItem(diagnosisType, fmt.Sprintf("diagnoser timeout after %s", entry.timeout)), false
+	}
+}
+
+// buildItem 序列化诊断结果，构造成功的诊断项
+func buildItem(diagnosisType string, data interface{}) model.DiagnosisItem {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return failedItem(diagnosisType, fmt.Sprintf("Failed to marshal %s result: %s", diagnosisType, err.Error()))
 	}
 
 	return model.DiagnosisItem{
-		Type:     model.DiagnosisTypeAnomaly,
+		Type:     diagnosisType,
 		Status:   model.DiagnosisStatusSuccess,
 		DataJSON: dataJSON,
 	}
 }
+
+// failedItem 构造失败的诊断项
+func failedItem(diagnosisType string, errMsg string) model.DiagnosisItem {
+	return model.DiagnosisItem{
+		Type:   diagnosisType,
+		Status: model.DiagnosisStatusFailed,
+		Error:  errMsg,
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import "strings"
+
+// countryAliases 常见国家写法 → ISO 3166-1 alpha-2
+// 订单接入层未强制国家代码格式，诊断侧统一做一次归一化
+var countryAliases = map[string]string{
+	"USA":            "US",
+	"UNITED STATES":  "US",
+	"UK":             "GB",
+	"GBR":            "GB",
+	"UNITED KINGDOM": "GB",
+	"CHN":            "CN",
+	"CHINA":          "CN",
+	"JPN":            "JP",
+	"JAPAN":          "JP",
+	"CAN":            "CA",
+	"CANADA":         "CA",
+	"DEU":            "DE",
+	"GERMANY":        "DE",
+	"FRA":            "FR",
+	"FRANCE":         "FR",
+	"AUS":            "AU",
+	"AUSTRALIA":      "AU",
+	"MEX":            "MX",
+	"MEXICO":         "MX",
+}
+
+// normalizeCountry 将国家字段归一化为 ISO 两位代码（无法识别时原样大写返回）
+func normalizeCountry(country string) string {
+	c := strings.ToUpper(strings.TrimSpace(country))
+	if alias, ok := countryAliases[c]; ok {
+		return alias
+	}
+	return c
+}
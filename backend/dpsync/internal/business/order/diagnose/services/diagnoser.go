@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"oip/dpsync/internal/business/carbon"
+	"oip/dpsync/internal/business/dedup"
+	"oip/dpsync/internal/business/eligibility"
+	"oip/dpsync/internal/business/fx"
+	"oip/dpsync/internal/business/geo"
+	"oip/dpsync/internal/business/insurance"
+	"oip/dpsync/internal/business/rating"
+	"oip/dpsync/internal/business/risk"
+	"oip/dpsync/internal/business/screening"
+	"oip/dpsync/internal/business/surcharge"
+	"oip/dpsync/internal/business/tariff"
+	"oip/dpsync/internal/business/transit"
+)
+
+// Diagnoser 诊断器接口
+// 新增诊断能力只需实现该接口并注册到 Registry，CompositeHandler 负责并发调度与结果包装
+type Diagnoser interface {
+	// Type 诊断类型（与 model.DiagnosisType* 常量一致，作为 DiagnosisItem.Type）
+	Type() string
+
+	// Diagnose 执行诊断，返回值会被序列化为 DiagnosisItem.DataJSON
+	Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error)
+
+	// ResultSchema 返回结果结构体的零值，用于描述 DataJSON 的结构
+	ResultSchema() interface{}
+}
+
+// VersionedDiagnoser 可选接口：诊断结果仅由货件内容与规则/费率表版本决定
+// 实现该接口的诊断器结果可被 ResultCache 缓存；版本变化（如热更新汇率表）后缓存自然失效
+type VersionedDiagnoser interface {
+	Diagnoser
+
+	// Version 当前规则/费率表版本
+	Version() string
+}
+
+// OptionsKeyer 可选接口：诊断结果还依赖订单/账号级选项（如偏好币种、箱型目录）
+// 返回值计入 ResultCache 键；未实现时结果只由货件内容与版本决定，不同选项的订单共享缓存
+type OptionsKeyer interface {
+	// OptionsKey 影响本诊断器结果的选项摘要
+	OptionsKey(input *DiagnoseInput) string
+}
+
+// defaultDiagnoserTimeout 未单独指定超时时间的诊断器使用的默认超时
+const defaultDiagnoserTimeout = 5 * time.Second
+
+// registeredDiagnoser 已注册的诊断器及其超时配置
+type registeredDiagnoser struct {
+	diagnoser Diagnoser
+	timeout   time.Duration
+}
+
+// Registry 诊断器注册表（进程级，启动时注册完成后只读）
+type Registry struct {
+	diagnosers map[string]registeredDiagnoser
+	order      []string // 注册顺序，决定诊断项输出顺序
+}
+
+// NewRegistry 创建空的诊断器注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		diagnosers: make(map[string]registeredDiagnoser),
+		order:      make([]string, 0),
+	}
+}
+
+// Dependencies 诊断器依赖的进程级共享组件（由 Manager 启动时初始化）
+type Dependencies struct {
+	FX          *fx.Converter        // 汇率转换服务
+	Surcharge   *surcharge.Service   // 附加费查询服务
+	Quoter      *rating.Quoter       // 承运商报价服务
+	Eligibility *eligibility.Service // 承运商服务约束
+	Transit     *transit.Service     // 时效估算服务
+	Tariff      *tariff.Service      // 进口税费查询服务
+	Duplicates  *dedup.Detector      // 重复订单检测（可选，为 nil 时不检测）
+	Screening   *screening.Service   // 拒绝往来方名单
+	Risk        *risk.Service        // 风险评分规则
+	Geo         *geo.Service         // 离线地理编码（邮编质心）
+	Carbon      *carbon.Service      // 运输碳排放因子
+	Insurance   *insurance.Service   // 运输保险参考数据
+}
+
+// NewDefaultDependencies 使用内置数据创建依赖（测试工具等无配置场景使用）
+func NewDefaultDependencies() *Dependencies {
+	converter, err := fx.NewConverter(fx.DefaultRateTable())
+	if err != nil {
+		panic(err)
+	}
+	surcharges, err := surcharge.NewService(surcharge.DefaultDataset())
+	if err != nil {
+		panic(err)
+	}
+	constraints, err := eligibility.NewService(eligibility.DefaultCatalogue())
+	if err != nil {
+		panic(err)
+	}
+	calendar, err := transit.NewService(transit.DefaultCalendar())
+	if err != nil {
+		panic(err)
+	}
+	tariffs, err := tariff.NewService(tariff.DefaultTable())
+	if err != nil {
+		panic(err)
+	}
+	deniedParties, err := screening.NewService(screening.DefaultList(), "")
+	if err != nil {
+		panic(err)
+	}
+	riskRules, err := risk.NewService(risk.DefaultRules())
+	if err != nil {
+		panic(err)
+	}
+	locator, err := geo.NewService(geo.DefaultDataset())
+	if err != nil {
+		panic(err)
+	}
+	emissions, err := carbon.NewService(carbon.DefaultFactors())
+	if err != nil {
+		panic(err)
+	}
+	insuranceTable, err := insurance.NewService(insurance.DefaultTable())
+	if err != nil {
+		panic(err)
+	}
+	return &Dependencies{
+		FX:          converter,
+		Surcharge:   surcharges,
+		Quoter:      rating.NewDefaultQuoter(),
+		Eligibility: constraints,
+		Transit:     calendar,
+		Tariff:      tariffs,
+		Screening:   deniedParties,
+		Risk:        riskRules,
+		Geo:         locator,
+		Carbon:      emissions,
+		Insurance:   insuranceTable,
+	}
+}
+
+// NewDefaultRegistry 创建包含内置诊断器的注册表
+func NewDefaultRegistry(deps *Dependencies) *Registry {
+	r := NewRegistry()
+	shipping := NewShippingCalculator(deps.Quoter, deps.FX, deps.Surcharge, deps.Eligibility, deps.Transit, deps.Geo, deps.Carbon)
+	r.MustRegister(shipping, 3*time.Second)
+	r.MustRegister(NewAnomalyChecker(deps.FX, deps.Surcharge, deps.Duplicates), time.Second)
+	r.MustRegister(NewComplianceChecker(), time.Second)
+	r.MustRegister(NewLandedCostEstimator(deps.FX, deps.Tariff), time.Second)
+	r.MustRegister(NewPackagingAdvisor(), time.Second)
+	r.MustRegister(NewScreeningChecker(deps.Screening), 2*time.Second)
+	r.MustRegister(NewRiskScorer(deps.Risk, deps.FX, deps.Duplicates), time.Second)
+	r.MustRegister(NewCarbonEstimator(shipping.Version, deps.Carbon), 3*time.Second)
+	r.MustRegister(NewInsuranceAdvisor(shipping.Version, deps.FX, deps.Insurance), 3*time.Second)
+	r.MustRegister(NewServiceAuditor(shipping.Version, deps.FX), 3*time.Second)
+	return r
+}
+
+// Register 注册诊断器，timeout<=0 时使用默认超时
+func (r *Registry) Register(d Diagnoser, timeout time.Duration) error {
+	diagnosisType := d.Type()
+	if diagnosisType == "" {
+		return fmt.Errorf("diagnoser type cannot be empty")
+	}
+	if _, exists := r.diagnosers[diagnosisType]; exists {
+		return fmt.Errorf("diagnoser already registered: %s", diagnosisType)
+	}
+	if timeout <= 0 {
+		timeout = defaultDiagnoserTimeout
+	}
+
+	r.diagnosers[diagnosisType] = registeredDiagnoser{
+		diagnoser: d,
+		timeout:   timeout,
+	}
+	r.order = append(r.order, diagnosisType)
+
+	return nil
+}
+
+// MustRegister 注册诊断器，失败时 panic（仅用于启动阶段）
+func (r *Registry) MustRegister(d Diagnoser, timeout time.Duration) {
+	if err := r.Register(d, timeout); err != nil {
+		panic(err)
+	}
+}
+
+// Get 根据诊断类型获取诊断器
+func (r *Registry) Get(diagnosisType string) (Diagnoser, bool) {
+	entry, ok := r.diagnosers[diagnosisType]
+	return entry.diagnoser, ok
+}
+
+// Types 返回所有已注册的诊断类型（按注册顺序）
+func (r *Registry) Types() []string {
+	types := make([]string, len(r.order))
+	copy(types, r.order)
+	return types
+}
+
+// Schemas 返回所有诊断类型对应的结果结构
+func (r *Registry) Schemas() map[string]interface{} {
+	schemas := make(map[string]interface{}, len(r.order))
+	for _, diagnosisType := range r.order {
+		schemas[diagnosisType] = r.diagnosers[diagnosisType].diagnoser.ResultSchema()
+	}
+	return schemas
+}
+
+// resolve 根据选择列表解析本次需要执行的诊断类型
+// selected 为空时返回全部已注册类型；去重并保留调用方给定的顺序
+func (r *Registry) resolve(selected []string) []string {
+	if len(selected) == 0 {
+		return r.Types()
+	}
+
+	seen := make(map[string]bool, len(selected))
+	types := make([]string, 0, len(selected))
+	for _, diagnosisType := range selected {
+		if seen[diagnosisType] {
+			continue
+		}
+		seen[diagnosisType] = true
+		types = append(types, diagnosisType)
+	}
+	return types
+}
@@ -10,33 +10,28 @@ import (
 	"oip/dpsync/pkg/lmstfy"
 )
 
-// DiagnosisService 诊断服务（仅负责诊断逻辑，不涉及 DB 操作）
-// 职责：执行诊断 → 发送回调到 callback 队列
+// DiagnosisService 诊断回调服务（不涉及 DB 操作）
+// 职责：将 Process 阶段的诊断结果发送到 callback 队列（诊断只执行一次）
 type DiagnosisService struct {
-	compositeHandler *CompositeHandler
-	lmstfyClient     *lmstfy.Client
-	callbackQueue    string
+	lmstfyClient  *lmstfy.Client
+	callbackQueue string
 }
 
-// NewDiagnosisService 创建诊断服务实例
+// NewDiagnosisService 创建诊断回调服务实例
 func NewDiagnosisService(
 	lmstfyClient *lmstfy.Client,
 	callbackQueue string,
 ) *DiagnosisService {
 	return &DiagnosisService{
-		compositeHandler: NewCompositeHandler(),
-		lmstfyClient:     lmstfyClient,
-		callbackQueue:    callbackQueue,
+		lmstfyClient:  lmstfyClient,
+		callbackQueue: callbackQueue,
 	}
 }
 
-// ExecuteDiagnosis 执行诊断并发送回调
-// 返回 error 表示整个流程失败（诊断失败或回调发送失败）
-func (s *DiagnosisService) ExecuteDiagnosis(ctx context.Context, input *DiagnoseInput) error {
-	// 1. 执行诊断（不查询 DB，使用 payload 传入的数据）
-	diagnosisData, diagErr := s.compositeHandler.Diagnose(ctx, input)
-
-	// 2. 构造回调消息
+// SendCallback 发送诊断结果回调
+// diagErr 非空时回调状态为 FAILED；返回 error 表示回调发送失败
+func (s *DiagnosisService) SendCallback(ctx context.Context, input *DiagnoseInput, diagnosisData *model.DiagnosisResultData, diagErr error) error {
+	// 1. 构造回调消息
 	callback := model.OrderDiagnoseCallback{
 		RequestID:   input.RequestID,
 		OrderID:     input.OrderID,
@@ -54,13 +49,13 @@ func (s *DiagnosisService) ExecuteDiagnosis(ctx context.Context, input *Diagnose
 		callback.DiagnosisResult = diagnosisData
 	}
 
-	// 3. 序列化回调消息为 JSON
+	// 2. 序列化回调消息为 JSON
 	callbackJSON, err := json.Marshal(callback)
 	if err != nil {
 		return fmt.Errorf("failed to marshal callback: %w", err)
 	}
 
-	// 4. 发送回调到 callback 队列
+	// 3. 发送回调到 callback 队列
 	// ttl=0 表示永不过期, delay=0 表示立即可用
 	if err := s.lmstfyClient.Publish(s.callbackQueue, callbackJSON, 0, 0); err != nil {
 		return fmt.Errorf("failed to publish callback: %w", err)
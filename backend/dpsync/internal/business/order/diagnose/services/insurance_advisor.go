@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+	"oip/dpsync/internal/business/fx"
+	"oip/dpsync/internal/business/insurance"
+)
+
+// insuranceRulesVersion 保险建议规则版本（调整建议条件或保费口径时需同步更新）
+const insuranceRulesVersion = "insurance-2025-12"
+
+// InsuranceAdvisor 运输保险建议器
+// 申报货值对比各候选承运商服务的默认赔付责任，高货值或高丢损线路且货值超出赔付责任时建议足额投保
+// 候选服务与推荐服务取本次任务共享的 shipping 费率结果
+type InsuranceAdvisor struct {
+	rateVersion func() string // 费率计算器版本（计入组合版本）
+	converter   *fx.Converter
+	table       *insurance.Service
+}
+
+// NewInsuranceAdvisor 创建运输保险建议器实例
+func NewInsuranceAdvisor(rateVersion func() string, converter *fx.Converter, table *insurance.Service) *InsuranceAdvisor {
+	return &InsuranceAdvisor{
+		rateVersion: rateVersion,
+		converter:   converter,
+		table:       table,
+	}
+}
+
+// Type 诊断类型
+func (a *InsuranceAdvisor) Type() string {
+	return model.DiagnosisTypeInsurance
+}
+
+// Diagnose 执行运输保险诊断（实现 Diagnoser 接口）
+// 候选服务来自降级报价时结果不写入缓存（与 shipping 诊断一致）
+func (a *InsuranceAdvisor) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	result, degraded, err := a.Advise(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if degraded {
+		return noCache{data: result}, nil
+	}
+	return result, nil
+}
+
+// ResultSchema 诊断结果结构
+func (a *InsuranceAdvisor) ResultSchema() interface{} {
+	return &model.InsuranceResult{}
+}
+
+// Version 建议规则、保险参考数据与费率计算器的组合版本（实现 VersionedDiagnoser 接口，费率计算器版本已包含汇率表版本）
+func (a *InsuranceAdvisor) Version() string {
+	version := "rules:" + insuranceRulesVersion
+	if a.table != nil {
+		version += ";insurance:" + a.table.Version()
+	}
+	return version + ";" + a.rateVersion()
+}
+
+// OptionsKey 候选服务与推荐服务依赖的选项（实现 OptionsKeyer 接口，与 shipping 诊断一致）
+func (a *InsuranceAdvisor) OptionsKey(input *DiagnoseInput) string {
+	return input.rateOptionsKey()
+}
+
+// Advise 给出运输保险建议
+// 第二个返回值表示候选服务中存在承运商报价失败（结果不宜缓存）
+func (a *InsuranceAdvisor) Advise(ctx context.Context, input *DiagnoseInput) (*model.InsuranceResult, bool, error) {
+	if a.table == nil {
+		return nil, false, fmt.Errorf("insurance table not configured")
+	}
+	table := a.table.Table()
+
+	shipping, err := input.shippingRates(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	degraded := len(shipping.CarrierErrors) > 0
+
+	result := &model.InsuranceResult{
+		RecommendedCode: shipping.RecommendedCode,
+		Reasons:         make([]string, 0),
+		Options:         make([]model.InsuranceOption, 0, len(shipping.Rates)),
+		TableVersion:    table.Version,
+	}
+
+	value := a.declaredValue(input.Shipment, table.Currency)
+	if value == nil {
+		result.Unavailable = "declared value unknown: items have no price or currency cannot be converted"
+		return result, degraded, nil
+	}
+	result.DeclaredValue = value
+
+	origin, destination := originCountry(input.Shipment), destinationCountry(input.Shipment)
+	lossRate := table.LossRate(origin, destination)
+	result.Lane = &model.InsuranceLane{
+		Origin:          origin,
+		Destination:     destination,
+		LossRatePercent: lossRate,
+		HighLoss:        table.HighLossRatePercent > 0 && lossRate >= table.HighLossRatePercent,
+	}
+	highValue := table.HighValueThreshold > 0 && value.Amount >= table.HighValueThreshold
+
+	rec := explain.FromContext(ctx)
+	rec.Input("declared_value", value)
+	rec.Rule("HIGH_VALUE", highValue, map[string]interface{}{
+		"declared_value": value.Amount,
+		"threshold":      table.HighValueThreshold,
+		"currency":       table.Currency,
+	})
+	rec.Rule("HIGH_LOSS_LANE", result.Lane.HighLoss, map[string]interface{}{
+		"origin":            origin,
+		"destination":       destination,
+		"loss_rate_percent": lossRate,
+		"threshold_percent": table.HighLossRatePercent,
+	})
+
+	var picked *model.InsuranceOption
+	for _, rate := range shipping.Rates {
+		liability := table.Liability(rate.Carrier, rate.Service)
+		option := model.InsuranceOption{
+			Carrier:        rate.Carrier,
+			Service:        rate.Service,
+			Liability:      liability,
+			UncoveredValue: roundTo2Decimals(max(value.Amount-liability, 0)),
+			Currency:       table.Currency,
+		}
+		if option.UncoveredValue > 0 {
+			option.Premium, _ = table.Premium(rate.Carrier, option.UncoveredValue)
+			option.Recommended = highValue || result.Lane.HighLoss
+		}
+		rec.Rule("LIABILITY_GAP", option.UncoveredValue > 0, map[string]interface{}{
+			"carrier":         option.Carrier,
+			"service":         option.Service,
+			"liability":       option.Liability,
+			"uncovered_value": option.UncoveredValue,
+			"premium":         option.Premium,
+		})
+		result.Options = append(result.Options, option)
+		if fmt.Sprintf("%s_%s", rate.Carrier, rate.Service) == shipping.RecommendedCode {
+			picked = &result.Options[len(result.Options)-1]
+		}
+	}
+
+	if picked == nil {
+		result.Unavailable = "no eligible shipping service to insure"
+		return result, degraded, nil
+	}
+
+	result.Recommended = picked.Recommended
+	if highValue {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("Declared value %.2f %s reaches the high-value threshold %.2f %s", value.Amount, value.Currency, table.HighValueThreshold, table.Currency))
+	}
+	if result.Lane.HighLoss {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("Lane %s to %s has an estimated loss rate of %.2f%%", origin, destination, lossRate))
+	}
+	if picked.UncoveredValue > 0 {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s %s liability covers %.2f %s; %.2f %s is uninsured (premium %.2f %s)",
+			picked.Carrier, picked.Service, picked.Liability, picked.Currency, picked.UncoveredValue, picked.Currency, picked.Premium, picked.Currency))
+	} else {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s %s liability of %.2f %s covers the declared value", picked.Carrier, picked.Service, picked.Liability, picked.Currency))
+	}
+
+	return result, degraded, nil
+}
+
+// declaredValue 商品申报货值合计（换算为保险参考数据币种；无可用单价时返回 nil）
+func (a *InsuranceAdvisor) declaredValue(shipment *model.Shipment, currency string) *model.Money {
+	if shipment == nil {
+		return nil
+	}
+	value, _ := declaredValue(a.converter, shipment.Parcels, currency)
+	if value == nil || value.Amount <= 0 {
+		return nil
+	}
+	if value.Currency == currency {
+		return &model.Money{Amount: value.Amount, Currency: value.Currency}
+	}
+	return value.Preferred
+}
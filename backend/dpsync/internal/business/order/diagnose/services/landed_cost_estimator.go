@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+	"oip/dpsync/internal/business/fx"
+	"oip/dpsync/internal/business/tariff"
+)
+
+// landedCostRulesVersion 到岸成本估算规则版本（调整计算口径或拒收阈值时需同步更新）
+const landedCostRulesVersion = "landed-cost-2025-12"
+
+// DDU 拒收风险阈值：收件人需缴纳的税费合计占货值的比例
+const (
+	dduRefusalWarningRatio  = 0.10
+	dduRefusalCriticalRatio = 0.25
+)
+
+// LandedCostEstimator 到岸成本估算器（按目的国税率表估算进口关税与增值税/GST）
+type LandedCostEstimator struct {
+	converter *fx.Converter
+	tariffs   *tariff.Service
+}
+
+// NewLandedCostEstimator 创建到岸成本估算器实例
+func NewLandedCostEstimator(converter *fx.Converter, tariffs *tariff.Service) *LandedCostEstimator {
+	return &LandedCostEstimator{
+		converter: converter,
+		tariffs:   tariffs,
+	}
+}
+
+// Type 诊断类型
+func (e *LandedCostEstimator) Type() string {
+	return model.DiagnosisTypeLandedCost
+}
+
+// Diagnose 执行到岸成本诊断（实现 Diagnoser 接口）
+func (e *LandedCostEstimator) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	return e.Estimate(ctx, input)
+}
+
+// ResultSchema 诊断结果结构
+func (e *LandedCostEstimator) ResultSchema() interface{} {
+	return &model.LandedCostResult{}
+}
+
+// Version 估算规则、税率表与汇率表的组合版本（实现 VersionedDiagnoser 接口）
+func (e *LandedCostEstimator) Version() string {
+	version := "rules:" + landedCostRulesVersion
+	if e.tariffs != nil {
+		version += ";tariff:" + e.tariffs.Version()
+	}
+	if e.converter != nil {
+		version += ";fx:" + e.converter.Version()
+	}
+	return version
+}
+
+// OptionsKey 偏好币种与贸易术语（实现 OptionsKeyer 接口）
+func (e *LandedCostEstimator) OptionsKey(input *DiagnoseInput) string {
+	return input.PreferredCurrency + ";" + input.Incoterm
+}
+
+// Estimate 估算到岸成本
+// 申报货值按目的国本币汇总后判断起征点：超过关税起征点时逐项按 HS 章计征关税，
+// 超过进口税起征点时按（货值 + 关税）计征 VAT/GST；运费不计入税基
+func (e *LandedCostEstimator) Estimate(ctx context.Context, input *DiagnoseInput) (*model.LandedCostResult, error) {
+	rec := explain.FromContext(ctx)
+	shipment := input.Shipment
+	incoterm := input.Incoterm
+	if incoterm == "" {
+		incoterm = model.DefaultIncoterm
+	}
+	rec.Input("incoterm", incoterm)
+
+	result := &model.LandedCostResult{
+		Destination: destinationCountry(shipment),
+		Incoterm:    incoterm,
+		PaidBy:      model.LandedCostPaidByRecipient,
+		Items:       make([]model.LandedCostItem, 0),
+		Warnings:    make([]model.LandedCostWarning, 0),
+	}
+	if incoterm == model.IncotermDDP {
+		result.PaidBy = model.LandedCostPaidBySender
+	}
+	if e.tariffs != nil {
+		result.TariffVersion = e.tariffs.Version()
+	}
+
+	if result.Destination == "" {
+		result.Warnings = append(result.Warnings, model.LandedCostWarning{
+			Type:    model.LandedCostWarningDestinationUnset,
+			Level:   model.AnomalyLevelWarning,
+			Message: "Destination country is missing, landed cost cannot be estimated",
+		})
+		return result, nil
+	}
+	origin := originCountry(shipment)
+	rec.Input("origin", origin)
+	rec.Input("destination", result.Destination)
+	rec.Rule("DOMESTIC", origin != "" && origin == result.Destination, nil)
+	if origin != "" && origin == result.Destination {
+		result.Domestic = true
+		return result, nil
+	}
+
+	if e.tariffs == nil || e.converter == nil {
+		return nil, fmt.Errorf("landed cost estimator is not configured")
+	}
+	destination, ok := e.tariffs.Lookup(result.Destination)
+	rec.Rule(model.LandedCostWarningTariffNotFound, !ok, map[string]interface{}{
+		"destination":    result.Destination,
+		"tariff_version": result.TariffVersion,
+	})
+	if !ok {
+		result.Warnings = append(result.Warnings, model.LandedCostWarning{
+			Type:    model.LandedCostWarningTariffNotFound,
+			Level:   model.AnomalyLevelInfo,
+			Message: fmt.Sprintf("No tariff data for destination %s, duties and taxes are not estimated", result.Destination),
+		})
+		return result, nil
+	}
+
+	result.Currency = destination.Currency
+	result.TaxName = destination.TaxName
+	result.TaxPercent = destination.TaxPercent
+
+	rec.Input("tariff_row", destination)
+
+	e.valueItems(result, shipment, destination)
+	e.applyCharges(result, destination)
+	rec.Rule("DUTY_DE_MINIMIS", result.DutyDeMinimisApplied, map[string]interface{}{
+		"goods_value":     result.GoodsValue,
+		"duty_de_minimis": destination.DutyDeMinimis,
+		"duty":            result.Duty,
+	})
+	rec.Rule("TAX_DE_MINIMIS", result.TaxDeMinimisApplied, map[string]interface{}{
+		"goods_value":       result.GoodsValue,
+		"tax_de_minimis":    destination.TaxDeMinimis,
+		"tax_includes_duty": destination.TaxIncludesDuty,
+		"tax_percent":       destination.TaxPercent,
+		"tax":               result.Tax,
+	})
+
+	e.applyPreferredCurrency(result, input.PreferredCurrency)
+
+	warning := dduRefusalWarning(result)
+	if rec.Enabled() && result.GoodsValue > 0 {
+		rec.Rule(model.LandedCostWarningDDURefusalRisk, warning != nil, map[string]interface{}{
+			"incoterm":       result.Incoterm,
+			"total_charges":  result.TotalCharges,
+			"goods_value":    result.GoodsValue,
+			"charges_ratio":  roundTo2Decimals(result.TotalCharges / result.GoodsValue),
+			"warning_ratio":  dduRefusalWarningRatio,
+			"critical_ratio": dduRefusalCriticalRatio,
+		})
+	}
+	if warning != nil {
+		result.Warnings = append(result.Warnings, *warning)
+	}
+
+	return result, nil
+}
+
+// valueItems 逐项换算货值（单价 × 数量 → 目的国本币）并确定适用关税税率
+func (e *LandedCostEstimator) valueItems(result *model.LandedCostResult, shipment *model.Shipment, destination *tariff.Destination) {
+	rates := make(map[string]bool)
+	unknown := make(map[string]bool)
+
+	for i, parcel := range shipment.Parcels {
+		for j, item := range parcel.Items {
+			if item.Price == nil {
+				result.Warnings = append(result.Warnings, model.LandedCostWarning{
+					Type:    model.LandedCostWarningMissingPrice,
+					Level:   model.AnomalyLevelWarning,
+					Message: fmt.Sprintf("Item #%d in parcel #%d has no price, excluded from goods value", j+1, i+1),
+				})
+				continue
+			}
+
+			quantity := item.Quantity
+			if quantity <= 0 {
+				quantity = 1
+			}
+			currency := strings.TrimSpace(item.Price.Currency)
+
+			converted, rate, err := e.converter.Convert(item.Price.Amount*float64(quantity), currency, destination.Currency)
+			if err != nil {
+				if !unknown[currency] {
+					unknown[currency] = true
+					result.Warnings = append(result.Warnings, model.LandedCostWarning{
+						Type:    model.LandedCostWarningUnknownCurrency,
+						Level:   model.AnomalyLevelWarning,
+						Message: fmt.Sprintf("Currency %q cannot be converted, item value excluded from goods value", currency),
+					})
+				}
+				continue
+			}
+			if rate.From != rate.To && !rates[rate.From] {
+				rates[rate.From] = true
+				result.ExchangeRates = append(result.ExchangeRates, *rate)
+			}
+
+			chapter := tariff.Chapter(item.HSCode)
+			percent, _ := destination.DutyPercent(chapter)
+			if chapter == "" {
+				result.Warnings = append(result.Warnings, model.LandedCostWarning{
+					Type:    model.LandedCostWarningMissingHSCode,
+					Level:   model.AnomalyLevelInfo,
+					Message: fmt.Sprintf("Item #%d in parcel #%d has no valid HS code, default duty rate %.1f%% applied", j+1, i+1, percent),
+				})
+			}
+
+			result.Items = append(result.Items, model.LandedCostItem{
+				ParcelIndex: i,
+				ItemIndex:   j,
+				SKU:         strings.TrimSpace(item.SKU),
+				HSChapter:   chapter,
+				Value:       converted.Amount,
+				DutyPercent: percent,
+			})
+			result.GoodsValue += converted.Amount
+		}
+	}
+
+	result.GoodsValue = roundTo2Decimals(result.GoodsValue)
+}
+
+// applyCharges 按起征点计算关税、进口环节税与代垫手续费
+func (e *LandedCostEstimator) applyCharges(result *model.LandedCostResult, destination *tariff.Destination) {
+	if destination.DutyDeMinimis > 0 && result.GoodsValue <= destination.DutyDeMinimis {
+		result.DutyDeMinimisApplied = true
+	} else {
+		duty := 0.0
+		for i := range result.Items {
+			result.Items[i].Duty = roundTo2Decimals(result.Items[i].Value * result.Items[i].DutyPercent / 100)
+			duty += result.Items[i].Duty
+		}
+		result.Duty = roundTo2Decimals(duty)
+	}
+
+	if destination.TaxDeMinimis > 0 && result.GoodsValue <= destination.TaxDeMinimis {
+		result.TaxDeMinimisApplied = true
+	} else {
+		taxBase := result.GoodsValue
+		if destination.TaxIncludesDuty {
+			taxBase += result.Duty
+		}
+		result.Tax = roundTo2Decimals(taxBase * destination.TaxPercent / 100)
+	}
+
+	if result.Duty+result.Tax > 0 {
+		result.ClearanceFee = destination.ClearanceFee
+	}
+	result.TotalCharges = roundTo2Decimals(result.Duty + result.Tax + result.ClearanceFee)
+	result.LandedCost = roundTo2Decimals(result.GoodsValue + result.TotalCharges)
+}
+
+// applyPreferredCurrency 税费合计换算为账号偏好币种（不支持的币种只给出提示，不影响估算结果）
+func (e *LandedCostEstimator) applyPreferredCurrency(result *model.LandedCostResult, preferredCurrency string) {
+	if preferredCurrency == "" {
+		return
+	}
+
+	preferred, rate, err := e.converter.Convert(result.TotalCharges, result.Currency, preferredCurrency)
+	if err != nil {
+		result.Warnings = append(result.Warnings, model.LandedCostWarning{
+			Type:    model.LandedCostWarningUnknownCurrency,
+			Level:   model.AnomalyLevelInfo,
+			Message: fmt.Sprintf("Preferred currency %q is not supported", preferredCurrency),
+		})
+		return
+	}
+	result.PreferredCharges = preferred
+	if rate.From != rate.To {
+		result.ExchangeRates = append(result.ExchangeRates, *rate)
+	}
+}
+
+// dduRefusalWarning DDU 下收件人需在派送时缴纳税费，税费占货值比例越高越容易被拒收
+func dduRefusalWarning(result *model.LandedCostResult) *model.LandedCostWarning {
+	if result.Incoterm != model.IncotermDDU || result.TotalCharges <= 0 || result.GoodsValue <= 0 {
+		return nil
+	}
+
+	ratio := result.TotalCharges / result.GoodsValue
+	message := fmt.Sprintf("Recipient must pay %.2f %s in duties, taxes and fees on delivery (%.0f%% of goods value)",
+		result.TotalCharges, result.Currency, ratio*100)
+
+	level := model.AnomalyLevelInfo
+	switch {
+	case ratio >= dduRefusalCriticalRatio:
+		level = model.AnomalyLevelCritical
+		message += "; high risk of refused delivery, consider shipping DDP"
+	case ratio >= dduRefusalWarningRatio:
+		level = model.AnomalyLevelWarning
+		message += "; recipient may refuse the parcel, consider shipping DDP"
+	}
+
+	return &model.LandedCostWarning{
+		Type:    model.LandedCostWarningDDURefusalRisk,
+		Level:   level,
+		Message: message,
+	}
+}
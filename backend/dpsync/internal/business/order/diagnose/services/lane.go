@@ -0,0 +1,65 @@
+package services
+
+import (
+	"math"
+	"strings"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/geo"
+)
+
+// resolveLane 解析运输线路：始发地与收件地按邮编质心定位并计算大圆距离
+// 国内线路只要有一端仅能定位到国家质心，距离就没有意义，不给出距离（也不按距离分区计价）
+func resolveLane(locator *geo.Service, shipment *model.Shipment) *model.Lane {
+	origin, destination := originCountry(shipment), destinationCountry(shipment)
+	if origin == "" || destination == "" {
+		return nil
+	}
+
+	lane := &model.Lane{Type: model.LaneTypeInternational}
+	if origin == destination {
+		lane.Type = model.LaneTypeDomestic
+	}
+	if locator == nil {
+		return lane
+	}
+	lane.GeoVersion = locator.Version()
+
+	from, fromOK := locator.Resolve(origin, shipment.ShipFrom.PostalCode)
+	to, toOK := locator.Resolve(destination, shipment.ShipTo.PostalCode)
+	if fromOK {
+		lane.Origin = geoLocation(origin, from)
+	}
+	if toOK {
+		lane.Destination = geoLocation(destination, to)
+	}
+	if !fromOK || !toOK {
+		return lane
+	}
+	if lane.Type == model.LaneTypeDomestic && (from.Precision == geo.PrecisionCountry || to.Precision == geo.PrecisionCountry) {
+		return lane
+	}
+
+	distance := math.Round(geo.Distance(from.Point, to.Point))
+	lane.DistanceKm = &distance
+	return lane
+}
+
+// geoLocation 地理编码结果转换为诊断输出结构
+func geoLocation(country string, location *geo.Location) *model.GeoLocation {
+	return &model.GeoLocation{
+		Country:      strings.ToUpper(country),
+		PostalPrefix: location.PostalPrefix,
+		Latitude:     location.Lat,
+		Longitude:    location.Lon,
+		Precision:    location.Precision,
+	}
+}
+
+// laneDistance 线路距离（线路未知或无法计算距离时为空）
+func laneDistance(lane *model.Lane) *float64 {
+	if lane == nil {
+		return nil
+	}
+	return lane.DistanceKm
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+)
+
+// packagingRulesVersion 包装规则版本（调整阈值或体积重口径时需同步更新）
+const packagingRulesVersion = "packaging-2025-12"
+
+const (
+	// dimDivisor 体积重除数（cm³/kg，国际快递通用口径）
+	dimDivisor = 5000.0
+	// itemWeightTolerance 包裹重量允许低于商品重量合计的比例（称重误差）
+	itemWeightTolerance = 0.05
+	// maxPlausibleDensity 超过该密度（kg/m³）视为重量或尺寸填写错误
+	maxPlausibleDensity = 2000.0
+	// minPlausibleDensity 低于该密度（kg/m³）视为箱子过大或尺寸填写错误
+	minPlausibleDensity = 10.0
+	// assumedContentDensity 估算内容物体积时假定的装箱密度（kg/m³），用于判断换小箱后是否装得下
+	assumedContentDensity = 200.0
+)
+
+// PackagingAdvisor 包装诊断器（包裹/商品重量一致性、密度合理性、箱型与合箱建议）
+type PackagingAdvisor struct{}
+
+// NewPackagingAdvisor 创建包装诊断器实例
+func NewPackagingAdvisor() *PackagingAdvisor {
+	return &PackagingAdvisor{}
+}
+
+// Type 诊断类型
+func (a *PackagingAdvisor) Type() string {
+	return model.DiagnosisTypePackaging
+}
+
+// Diagnose 执行包装诊断（实现 Diagnoser 接口）
+func (a *PackagingAdvisor) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	return a.Advise(ctx, input.Shipment, input.Boxes)
+}
+
+// ResultSchema 诊断结果结构
+func (a *PackagingAdvisor) ResultSchema() interface{} {
+	return &model.PackagingResult{}
+}
+
+// Version 包装规则版本（实现 VersionedDiagnoser 接口）
+func (a *PackagingAdvisor) Version() string {
+	return "rules:" + packagingRulesVersion
+}
+
+// OptionsKey 账号箱型目录（实现 OptionsKeyer 接口）
+func (a *PackagingAdvisor) OptionsKey(input *DiagnoseInput) string {
+	if len(input.Boxes) == 0 {
+		return ""
+	}
+	return "boxes:" + digest(input.Boxes)
+}
+
+// packedParcel 参与箱型推荐的包裹
+type packedParcel struct {
+	index         int
+	weightKg      float64
+	contentVolume float64 // 估算的内容物体积（cm³）
+	billableKg    float64
+}
+
+// Advise 执行包装诊断
+// 箱型建议只在包裹按体积重计费时给出：换用体积更小、可容纳估算内容物且承重足够的账号箱型
+func (a *PackagingAdvisor) Advise(ctx context.Context, shipment *model.Shipment, boxes []model.Box) (*model.PackagingResult, error) {
+	result := &model.PackagingResult{
+		DimDivisor: dimDivisor,
+		Parcels:    make([]model.ParcelPackaging, 0),
+		BoxCount:   len(boxes),
+	}
+	if shipment == nil {
+		return result, nil
+	}
+
+	rec := explain.FromContext(ctx)
+	rec.Input("dim_divisor", dimDivisor)
+	rec.Input("box_count", len(boxes))
+	rec.Input("thresholds", map[string]interface{}{
+		"item_weight_tolerance": itemWeightTolerance,
+		"min_density_kg_per_m3": minPlausibleDensity,
+		"max_density_kg_per_m3": maxPlausibleDensity,
+	})
+
+	packed := make([]packedParcel, 0, len(shipment.Parcels))
+	for i, parcel := range shipment.Parcels {
+		diagnosis, candidate := a.checkParcel(i, parcel)
+
+		if candidate != nil {
+			packed = append(packed, *candidate)
+			if diagnosis.DimWeightKg > diagnosis.WeightKg {
+				diagnosis.Suggestion = suggestBox(boxes, candidate, diagnosis.VolumeCm3)
+			}
+		}
+
+		matched := make([]string, 0, len(diagnosis.Issues))
+		for _, issue := range diagnosis.Issues {
+			matched = append(matched, issue.Type)
+			if issue.Level != model.AnomalyLevelInfo {
+				result.HasIssue = true
+			}
+		}
+		rec.Rule("PARCEL_PACKAGING", len(matched) > 0, map[string]interface{}{
+			"parcel_index":       i,
+			"weight_kg":          diagnosis.WeightKg,
+			"items_weight_kg":    diagnosis.ItemsWeightKg,
+			"volume_cm3":         diagnosis.VolumeCm3,
+			"density_kg_per_m3":  diagnosis.DensityKgPerM3,
+			"dim_weight_kg":      diagnosis.DimWeightKg,
+			"billable_weight_kg": diagnosis.BillableWeightKg,
+			"issues":             matched,
+		})
+		if candidate != nil && diagnosis.DimWeightKg > diagnosis.WeightKg {
+			rec.Rule("BOX_SUGGESTION", diagnosis.Suggestion != nil, map[string]interface{}{
+				"parcel_index":       i,
+				"content_volume_cm3": roundTo2Decimals(candidate.contentVolume),
+				"suggestion":         diagnosis.Suggestion,
+			})
+		}
+		result.BillableWeightKg += diagnosis.BillableWeightKg
+		result.Parcels = append(result.Parcels, diagnosis)
+	}
+	result.BillableWeightKg = roundTo2Decimals(result.BillableWeightKg)
+
+	// 所有包裹都参与箱型推荐时才考虑合箱
+	if len(shipment.Parcels) > 1 && len(packed) == len(shipment.Parcels) {
+		result.Consolidation = suggestConsolidation(boxes, packed)
+		rec.Rule("CONSOLIDATION", result.Consolidation != nil, map[string]interface{}{
+			"parcel_count":  len(packed),
+			"consolidation": result.Consolidation,
+		})
+	}
+
+	return result, nil
+}
+
+// checkParcel 检查单个包裹；包裹重量与尺寸齐全且密度合理时返回参与箱型推荐的候选
+func (a *PackagingAdvisor) checkParcel(index int, parcel *model.Parcel) (model.ParcelPackaging, *packedParcel) {
+	weight := parcel.Weight.Kilograms()
+	volume := parcel.Dimension.CubicCentimeters()
+
+	diagnosis := model.ParcelPackaging{
+		ParcelIndex:      index,
+		WeightKg:         roundTo2Decimals(weight),
+		ItemsWeightKg:    roundTo2Decimals(itemsWeightKg(parcel.Items)),
+		VolumeCm3:        roundTo2Decimals(volume),
+		DimWeightKg:      roundTo2Decimals(volume / dimDivisor),
+		BillableWeightKg: roundTo2Decimals(math.Max(weight, volume/dimDivisor)),
+		Issues:           make([]model.PackagingIssue, 0),
+	}
+
+	if diagnosis.ItemsWeightKg > 0 && weight < diagnosis.ItemsWeightKg*(1-itemWeightTolerance) {
+		diagnosis.Issues = append(diagnosis.Issues, model.PackagingIssue{
+			Type:    model.PackagingTypeWeightBelowItems,
+			Level:   model.AnomalyLevelCritical,
+			Message: fmt.Sprintf("Parcel #%d weighs %.2f kg, less than its items (%.2f kg)", index+1, weight, diagnosis.ItemsWeightKg),
+		})
+	}
+
+	if volume <= 0 {
+		diagnosis.Issues = append(diagnosis.Issues, model.PackagingIssue{
+			Type:    model.PackagingTypeMissingDimension,
+			Level:   model.AnomalyLevelInfo,
+			Message: fmt.Sprintf("Parcel #%d has no dimensions, density and dimensional weight cannot be checked", index+1),
+		})
+		return diagnosis, nil
+	}
+	if weight <= 0 {
+		return diagnosis, nil
+	}
+
+	// 密度不合理说明重量或尺寸有误，不据此给出箱型建议
+	density := weight / (volume / 1e6)
+	diagnosis.DensityKgPerM3 = roundTo2Decimals(density)
+	switch {
+	case density > maxPlausibleDensity:
+		diagnosis.Issues = append(diagnosis.Issues, model.PackagingIssue{
+			Type:    model.PackagingTypeDensityTooHigh,
+			Level:   model.AnomalyLevelWarning,
+			Message: fmt.Sprintf("Parcel #%d density %.0f kg/m³ is implausible, check weight and dimensions", index+1, density),
+		})
+		return diagnosis, nil
+	case density < minPlausibleDensity:
+		diagnosis.Issues = append(diagnosis.Issues, model.PackagingIssue{
+			Type:    model.PackagingTypeDensityTooLow,
+			Level:   model.AnomalyLevelWarning,
+			Message: fmt.Sprintf("Parcel #%d density %.1f kg/m³ is implausibly low, the box may be oversized or dimensions wrong", index+1, density),
+		})
+		return diagnosis, nil
+	}
+
+	// 包裹重量低于商品重量合计时按商品重量估算内容物与承重
+	packedWeight := math.Max(weight, diagnosis.ItemsWeightKg)
+	return diagnosis, &packedParcel{
+		index:         index,
+		weightKg:      packedWeight,
+		contentVolume: math.Min(volume, packedWeight/assumedContentDensity*1e6),
+		billableKg:    math.Max(weight, volume/dimDivisor),
+	}
+}
+
+// suggestBox 为单个包裹选择体积更小且能降低计费重的箱型
+func suggestBox(boxes []model.Box, parcel *packedParcel, currentVolume float64) *model.BoxSuggestion {
+	box := smallestFittingBox(boxes, parcel.contentVolume, parcel.weightKg, currentVolume)
+	if box == nil {
+		return nil
+	}
+
+	dimWeight := box.Dimension.CubicCentimeters() / dimDivisor
+	billable := math.Max(parcel.weightKg, dimWeight)
+	saving := roundTo2Decimals(parcel.billableKg - billable)
+	if saving <= 0 {
+		return nil
+	}
+
+	return &model.BoxSuggestion{
+		Box:              box.Name,
+		DimWeightKg:      roundTo2Decimals(dimWeight),
+		BillableWeightKg: roundTo2Decimals(billable),
+		SavingKg:         saving,
+	}
+}
+
+// suggestConsolidation 多个包裹合并为一箱后计费重更低时给出合箱建议
+func suggestConsolidation(boxes []model.Box, parcels []packedParcel) *model.ConsolidationSuggestion {
+	weight, content, current := 0.0, 0.0, 0.0
+	indexes := make([]int, 0, len(parcels))
+	for _, parcel := range parcels {
+		weight += parcel.weightKg
+		content += parcel.contentVolume
+		current += parcel.billableKg
+		indexes = append(indexes, parcel.index)
+	}
+
+	box := smallestFittingBox(boxes, content, weight, math.Inf(1))
+	if box == nil {
+		return nil
+	}
+
+	billable := math.Max(weight, box.Dimension.CubicCentimeters()/dimDivisor)
+	saving := roundTo2Decimals(current - billable)
+	if saving <= 0 {
+		return nil
+	}
+
+	return &model.ConsolidationSuggestion{
+		Box:                     box.Name,
+		ParcelIndexes:           indexes,
+		CurrentBillableWeightKg: roundTo2Decimals(current),
+		BillableWeightKg:        roundTo2Decimals(billable),
+		SavingKg:                saving,
+	}
+}
+
+// smallestFittingBox 体积不小于内容物、小于当前体积且承重足够的最小箱型（无满足项时返回 nil）
+func smallestFittingBox(boxes []model.Box, contentVolume, weightKg, maxVolume float64) *model.Box {
+	var best *model.Box
+	bestVolume := 0.0
+	for i := range boxes {
+		box := &boxes[i]
+		volume := box.Dimension.CubicCentimeters()
+		if volume < contentVolume || volume >= maxVolume {
+			continue
+		}
+		if box.MaxWeight != nil && box.MaxWeight.Kilograms() > 0 && weightKg > box.MaxWeight.Kilograms() {
+			continue
+		}
+		if best == nil || volume < bestVolume {
+			best, bestVolume = box, volume
+		}
+	}
+	return best
+}
+
+// itemsWeightKg 商品重量合计（单件重量 × 数量，数量缺省按 1 件）
+func itemsWeightKg(items []*model.Item) float64 {
+	total := 0.0
+	for _, item := range items {
+		quantity := item.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		total += item.Weight.Kilograms() * float64(quantity)
+	}
+	return total
+}
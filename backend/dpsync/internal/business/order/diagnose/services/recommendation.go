@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"oip/common/model"
+)
+
+// recommendationVersion 推荐规则版本（调整评分方式或承运商可靠性数据时需同步更新）
+const recommendationVersion = "recommend-2025-12"
+
+// defaultOnTimeRate 未登记承运商的准时率
+const defaultOnTimeRate = 0.90
+
+// carrierOnTimeRates 承运商准时送达率（近 12 个月公开数据，作为 WEIGHTED 策略的可靠性得分）
+var carrierOnTimeRates = map[string]float64{
+	"FEDEX": 0.95,
+	"UPS":   0.96,
+	"USPS":  0.91,
+	"DHL":   0.94,
+}
+
+// recommendation 推荐结果
+type recommendation struct {
+	index  int    // 推荐费率在 rates 中的下标
+	reason string // 推荐理由
+}
+
+// recommend 按策略从费率列表中选出推荐项（rates 不能为空）
+// WEIGHTED 策略会为每条费率写入 Score
+func recommend(rates []model.ShippingRate, strategy *model.RecommendationStrategy) recommendation {
+	switch strategy.Name {
+	case model.RecommendStrategyFastest:
+		idx := findFastest(rates)
+		return recommendation{
+			index:  idx,
+			reason: fmt.Sprintf("%s has the shortest transit time (%d days) at %s", rateName(rates[idx]), rates[idx].TransitDays, rateFee(rates[idx])),
+		}
+
+	case model.RecommendStrategyCheapestWithin:
+		idx := -1
+		for i, rate := range rates {
+			if rate.TransitDays > strategy.MaxTransitDays {
+				continue
+			}
+			if idx < 0 || isCheaper(rate, rates[idx]) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			// 没有满足时效要求的服务时退而推荐最快的
+			idx = findFastest(rates)
+			return recommendation{
+				index: idx,
+				reason: fmt.Sprintf("no rate delivers within %d days; %s is the fastest option (%d days) at %s",
+					strategy.MaxTransitDays, rateName(rates[idx]), rates[idx].TransitDays, rateFee(rates[idx])),
+			}
+		}
+		return recommendation{
+			index: idx,
+			reason: fmt.Sprintf("%s is the cheapest option delivering within %d days (%d days) at %s",
+				rateName(rates[idx]), strategy.MaxTransitDays, rates[idx].TransitDays, rateFee(rates[idx])),
+		}
+
+	case model.RecommendStrategyWeighted:
+		return recommendWeighted(rates, strategy.Weights)
+
+	default:
+		idx := findCheapest(rates)
+		return recommendation{
+			index:  idx,
+			reason: fmt.Sprintf("%s is the cheapest option at %s", rateName(rates[idx]), rateFee(rates[idx])),
+		}
+	}
+}
+
+// recommendWeighted 加权评分推荐
+// 运费、时效按候选集内的最小/最大值线性归一化到 0~1（越低越好），可靠性取承运商准时率
+func recommendWeighted(rates []model.ShippingRate, weights *model.StrategyWeights) recommendation {
+	minFee, maxFee := rates[0].TotalFee, rates[0].TotalFee
+	minDays, maxDays := rates[0].TransitDays, rates[0].TransitDays
+	for _, rate := range rates {
+		minFee = math.Min(minFee, rate.TotalFee)
+		maxFee = math.Max(maxFee, rate.TotalFee)
+		if rate.TransitDays < minDays {
+			minDays = rate.TransitDays
+		}
+		if rate.TransitDays > maxDays {
+			maxDays = rate.TransitDays
+		}
+	}
+
+	totalWeight := weights.Cost + weights.TransitTime + weights.Reliability
+
+	best := -1
+	var bestCost, bestTransit, bestReliability float64
+	for i := range rates {
+		cost := normalizedScore(rates[i].TotalFee, minFee, maxFee)
+		transit := normalizedScore(float64(rates[i].TransitDays), float64(minDays), float64(maxDays))
+		reliability := onTimeRate(rates[i].Carrier)
+
+		score := roundTo2Decimals((weights.Cost*cost + weights.TransitTime*transit + weights.Reliability*reliability) / totalWeight)
+		rates[i].Score = &score
+
+		if best < 0 || score > *rates[best].Score || (score == *rates[best].Score && isCheaper(rates[i], rates[best])) {
+			best = i
+			bestCost, bestTransit, bestReliability = cost, transit, reliability
+		}
+	}
+
+	return recommendation{
+		index: best,
+		reason: fmt.Sprintf("%s has the highest weighted score %.2f (cost %.2f, transit %.2f, reliability %.2f) at %s",
+			rateName(rates[best]), *rates[best].Score, bestCost, bestTransit, bestReliability, rateFee(rates[best])),
+	}
+}
+
+// normalizedScore 线性归一化得分：最小值得 1，最大值得 0；全部相等时得 1
+func normalizedScore(value, min, max float64) float64 {
+	if max <= min {
+		return 1
+	}
+	return (max - value) / (max - min)
+}
+
+// onTimeRate 承运商准时率
+func onTimeRate(carrier string) float64 {
+	if rate, ok := carrierOnTimeRates[strings.ToUpper(strings.TrimSpace(carrier))]; ok {
+		return rate
+	}
+	return defaultOnTimeRate
+}
+
+// isCheaper 运费更低，运费相同时比较时效
+func isCheaper(a, b model.ShippingRate) bool {
+	if a.TotalFee != b.TotalFee {
+		return a.TotalFee < b.TotalFee
+	}
+	return a.TransitDays < b.TransitDays
+}
+
+// rateName 费率展示名（承运商 + 服务）
+func rateName(rate model.ShippingRate) string {
+	return rate.Carrier + " " + rate.Service
+}
+
+// rateFee 费率展示金额
+func rateFee(rate model.ShippingRate) string {
+	return fmt.Sprintf("%.2f %s", rate.TotalFee, rate.Currency)
+}
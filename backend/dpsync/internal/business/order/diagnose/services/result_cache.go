@@ -0,0 +1,117 @@
+package services
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"oip/common/model"
+)
+
+// ResultCache 诊断结果缓存
+// 以「诊断类型 + 规则/费率表版本 + 诊断器相关选项 + 货件指纹」为键缓存单个诊断项，
+// 相同货件在版本未变化时不再重复计算
+type ResultCache interface {
+	Get(key string) (model.DiagnosisItem, bool)
+	Set(key string, item model.DiagnosisItem)
+}
+
+// MemoryResultCache 进程内 LRU 结果缓存（并发安全）
+type MemoryResultCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	entries  map[string]*list.Element
+}
+
+// cacheEntry 缓存条目
+type cacheEntry struct {
+	key       string
+	item      model.DiagnosisItem
+	expiresAt time.Time
+}
+
+// NewMemoryResultCache 创建进程内结果缓存
+// capacity 为最大条目数，ttl<=0 表示条目不过期（仅按 LRU 淘汰）
+func NewMemoryResultCache(capacity int, ttl time.Duration) *MemoryResultCache {
+	return &MemoryResultCache{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get 查询缓存（过期条目视为未命中并移除）
+func (c *MemoryResultCache) Get(key string) (model.DiagnosisItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return model.DiagnosisItem{}, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return model.DiagnosisItem{}, false
+	}
+
+	c.ll.MoveToFront(elem)
+	return entry.item, true
+}
+
+// Set 写入缓存，超出容量时淘汰最久未使用的条目
+func (c *MemoryResultCache) Set(key string, item model.DiagnosisItem) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.item = item
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.ll.PushFront(&cacheEntry{
+		key:       key,
+		item:      item,
+		expiresAt: expiresAt,
+	})
+
+	for c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Len 当前缓存条目数
+func (c *MemoryResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// removeElement 移除条目（调用方需持有锁）
+func (c *MemoryResultCache) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// resultCacheKey 诊断项缓存键
+// options 为影响该诊断器结果的订单/账号级选项（见 OptionsKeyer，未实现时为空）
+func resultCacheKey(diagnosisType, version, options, fingerprint string) string {
+	return strings.Join([]string{diagnosisType, version, options, fingerprint}, "|")
+}
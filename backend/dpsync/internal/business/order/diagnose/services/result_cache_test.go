@@ -0,0 +1,172 @@
+package services
+
+import (
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"oip/common/model"
+)
+
+func TestResultCacheKey(t *testing.T) {
+	base := resultCacheKey("shipping", "v1", "EUR;;2026-10-01T10:15:00Z;", "abc")
+	if want := "shipping|v1|EUR;;2026-10-01T10:15:00Z;|abc"; base != want {
+		t.Fatalf("resultCacheKey() = %q, want %q", base, want)
+	}
+
+	tests := []struct {
+		name                                   string
+		diagnosisType, version, options, print string
+	}{
+		{name: "type", diagnosisType: "anomaly", version: "v1", options: "EUR;;2026-10-01T10:15:00Z;", print: "abc"},
+		{name: "version", diagnosisType: "shipping", version: "v2", options: "EUR;;2026-10-01T10:15:00Z;", print: "abc"},
+		{name: "options", diagnosisType: "shipping", version: "v1", options: "USD;;2026-10-01T10:15:00Z;", print: "abc"},
+		{name: "fingerprint", diagnosisType: "shipping", version: "v1", options: "EUR;;2026-10-01T10:15:00Z;", print: "abd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultCacheKey(tt.diagnosisType, tt.version, tt.options, tt.print); got == base {
+				t.Errorf("resultCacheKey() with different %s = %q, want a different key", tt.name, got)
+			}
+		})
+	}
+}
+
+func TestOptionsKey(t *testing.T) {
+	keyers := map[string]OptionsKeyer{
+		"shipping":    &ShippingCalculator{},
+		"carbon":      &CarbonEstimator{},
+		"insurance":   &InsuranceAdvisor{},
+		"audit":       &ServiceAuditor{},
+		"anomaly":     &AnomalyChecker{},
+		"landed_cost": &LandedCostEstimator{},
+		"packaging":   &PackagingAdvisor{},
+		"risk":        &RiskScorer{},
+	}
+	rates := []string{"shipping", "carbon", "insurance", "audit"}
+
+	tests := []struct {
+		name    string
+		mutate  func(in *DiagnoseInput)
+		changed []string // 预期摘要发生变化的诊断器
+	}{
+		{
+			name:   "same input",
+			mutate: func(in *DiagnoseInput) {},
+		},
+		{
+			name:   "order time within the same minute",
+			mutate: func(in *DiagnoseInput) { in.OrderCreatedAt = in.OrderCreatedAt.Add(30 * time.Second) },
+		},
+		{
+			name: "order time in another time zone",
+			mutate: func(in *DiagnoseInput) {
+				in.OrderCreatedAt = in.OrderCreatedAt.In(time.FixedZone("UTC+8", 8*3600))
+			},
+		},
+		{
+			name:    "order time in the next minute",
+			mutate:  func(in *DiagnoseInput) { in.OrderCreatedAt = in.OrderCreatedAt.Add(time.Minute) },
+			changed: append([]string{"risk"}, rates...),
+		},
+		{
+			name:    "preferred currency",
+			mutate:  func(in *DiagnoseInput) { in.PreferredCurrency = "USD" },
+			changed: append([]string{"anomaly", "landed_cost"}, rates...),
+		},
+		{
+			name: "strategy",
+			mutate: func(in *DiagnoseInput) {
+				in.Strategy = &model.RecommendationStrategy{Name: model.RecommendStrategyFastest}
+			},
+			changed: rates,
+		},
+		{
+			name:    "promised delivery date",
+			mutate:  func(in *DiagnoseInput) { in.PromisedDeliveryDate = "2026-10-09" },
+			changed: rates,
+		},
+		{
+			name:    "contracts",
+			mutate:  func(in *DiagnoseInput) { in.Contracts[0].DiscountPercent = 15 },
+			changed: rates,
+		},
+		{
+			name:    "incoterm",
+			mutate:  func(in *DiagnoseInput) { in.Incoterm = model.IncotermDDU },
+			changed: []string{"landed_cost"},
+		},
+		{
+			name:    "boxes",
+			mutate:  func(in *DiagnoseInput) { in.Boxes = nil },
+			changed: []string{"packaging"},
+		},
+		{
+			name:    "selected service",
+			mutate:  func(in *DiagnoseInput) { in.Selected.Service = "Express" },
+			changed: []string{"audit"},
+		},
+		{
+			name:    "account profile",
+			mutate:  func(in *DiagnoseInput) { in.Account.PriorOrders = 3 },
+			changed: []string{"risk"},
+		},
+		{
+			name:    "fields outside options",
+			mutate:  func(in *DiagnoseInput) { in.RequestID, in.Explain = "req-2", true },
+			changed: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, other := newKeyInput(), newKeyInput()
+			tt.mutate(other)
+
+			var changed []string
+			for name, keyer := range keyers {
+				if keyer.OptionsKey(base) != keyer.OptionsKey(other) {
+					changed = append(changed, name)
+				}
+			}
+			sort.Strings(changed)
+			want := append([]string(nil), tt.changed...)
+			sort.Strings(want)
+
+			if !slices.Equal(changed, want) {
+				t.Errorf("changed OptionsKey = %v, want %v", changed, want)
+			}
+		})
+	}
+}
+
+func TestRiskScorerOptionsKeyWithoutAccount(t *testing.T) {
+	in := newKeyInput()
+	in.Account = nil
+	if got := (&RiskScorer{}).OptionsKey(in); got != "" {
+		t.Fatalf("OptionsKey() without account = %q, want empty", got)
+	}
+}
+
+// newKeyInput 构造各选项均非空的诊断输入（每次返回独立副本）
+func newKeyInput() *DiagnoseInput {
+	return &DiagnoseInput{
+		RequestID:         "req-1",
+		OrderID:           "order-1",
+		PreferredCurrency: "EUR",
+		Strategy:          &model.RecommendationStrategy{Name: model.RecommendStrategyCheapest},
+		Contracts: []model.RateContract{
+			{Carrier: "FedEx", DiscountPercent: 10},
+		},
+		OrderCreatedAt:       time.Date(2026, 10, 1, 10, 15, 20, 0, time.UTC),
+		PromisedDeliveryDate: "2026-10-08",
+		Boxes: []model.Box{
+			{Name: "S", Dimension: &model.Dimension{Width: 20, Height: 15, Depth: 10, Unit: "cm"}},
+		},
+		Incoterm: model.IncotermDDP,
+		Account:  &model.AccountProfile{CreatedAt: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)},
+		Selected: &model.SelectedService{Carrier: "FedEx", Service: "Ground"},
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/dedup"
+	"oip/dpsync/internal/business/explain"
+	"oip/dpsync/internal/business/fx"
+	"oip/dpsync/internal/business/risk"
+)
+
+// riskRulesVersion 风险评分逻辑版本（调整信号判定方式时需同步更新；权重与阈值随规则数据版本变化）
+const riskRulesVersion = "risk-2025-12"
+
+// RiskScorer 订单风险评分器（多个加权信号合成 0-100 风险分，并列出命中的信号）
+type RiskScorer struct {
+	rules      *risk.Service
+	converter  *fx.Converter
+	duplicates *dedup.Detector // 为 nil 时不评估收件人订单频率
+}
+
+// NewRiskScorer 创建风险评分器实例
+func NewRiskScorer(rules *risk.Service, converter *fx.Converter, duplicates *dedup.Detector) *RiskScorer {
+	return &RiskScorer{
+		rules:      rules,
+		converter:  converter,
+		duplicates: duplicates,
+	}
+}
+
+// Type 诊断类型
+func (s *RiskScorer) Type() string {
+	return model.DiagnosisTypeRisk
+}
+
+// Diagnose 执行风险评分（实现 Diagnoser 接口）
+// 启用近期订单索引后结果依赖其他订单，相同货件的结果不能复用，因此不写入缓存
+func (s *RiskScorer) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	result, err := s.Score(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if s.duplicates != nil {
+		return noCache{data: result}, nil
+	}
+	return result, nil
+}
+
+// ResultSchema 诊断结果结构
+func (s *RiskScorer) ResultSchema() interface{} {
+	return &model.RiskResult{}
+}
+
+// Version 评分逻辑、风险规则与汇率表的组合版本（实现 VersionedDiagnoser 接口）
+func (s *RiskScorer) Version() string {
+	version := "rules:" + riskRulesVersion
+	if s.rules != nil {
+		version += ";risk:" + s.rules.Version()
+	}
+	if s.converter != nil {
+		version += ";fx:" + s.converter.Version()
+	}
+	return version
+}
+
+// OptionsKey 账号概况与下单时间（实现 OptionsKeyer 接口，新账号首单按下单时的账号注册时长判断）
+func (s *RiskScorer) OptionsKey(input *DiagnoseInput) string {
+	if input.Account == nil {
+		return ""
+	}
+	return "account:" + digest(input.Account) + ";" + input.createdAtKey()
+}
+
+// Score 计算订单风险分
+// 风险分为命中信号的权重之和（上限 100），权重为 0 的信号不参与评估
+func (s *RiskScorer) Score(ctx context.Context, input *DiagnoseInput) (*model.RiskResult, error) {
+	if s.rules == nil {
+		return nil, fmt.Errorf("risk rules not configured")
+	}
+	rules := s.rules.Rules()
+	rec := explain.FromContext(ctx)
+	rec.Input("weights", rules.Weights)
+	rec.Input("rules_version", rules.Version)
+
+	result := &model.RiskResult{
+		Factors:      make([]model.RiskFactor, 0),
+		RulesVersion: rules.Version,
+	}
+	add := func(signal, message string) {
+		result.Factors = append(result.Factors, model.RiskFactor{
+			Signal:  signal,
+			Points:  rules.Weights[signal],
+			Message: message,
+		})
+	}
+	unavailable := func(signal string) {
+		result.Unavailable = append(result.Unavailable, signal)
+	}
+	enabled := func(signal string) bool {
+		return rules.Weights[signal] > 0
+	}
+
+	var shipTo *model.Address
+	if input.Shipment != nil {
+		shipTo = input.Shipment.ShipTo
+	}
+
+	// 信号 1：高货值寄往货代/转运地址
+	if enabled(model.RiskSignalForwarderHighValue) && shipTo != nil {
+		if reason, ok := rules.Forwarder(normalizeCountry(shipTo.Country), shipTo.PostalCode, shipTo.CompanyName, shipTo.Street1, shipTo.Street2); ok {
+			value, _ := declaredValue(s.converter, input.Shipment.Parcels, "")
+			if value != nil {
+				rec.Rule(model.RiskSignalForwarderHighValue, value.Amount >= rules.HighValueThreshold, map[string]interface{}{
+					"forwarder_reason": reason,
+					"declared_value":   value.Amount,
+					"currency":         value.Currency,
+					"threshold":        rules.HighValueThreshold,
+				})
+			}
+			switch {
+			case value == nil:
+				unavailable(model.RiskSignalForwarderHighValue)
+			case value.Amount >= rules.HighValueThreshold:
+				add(model.RiskSignalForwarderHighValue, fmt.Sprintf("Declared value %.2f %s shipped to a forwarder address (%s)", value.Amount, value.Currency, reason))
+			}
+		}
+	}
+
+	// 信号 2：电话国际区号与目的国不一致（本地格式电话无法判断，跳过）
+	if enabled(model.RiskSignalPhoneCountryMismatch) && shipTo != nil {
+		destination := normalizeCountry(shipTo.Country)
+		code, countries, ok := risk.PhoneCountries(shipTo.Phone)
+		if ok && destination != "" {
+			rec.Rule(model.RiskSignalPhoneCountryMismatch, !containsString(countries, destination), map[string]interface{}{
+				"calling_code":    code,
+				"phone_countries": countries,
+				"destination":     destination,
+			})
+		}
+		if ok && destination != "" && !containsString(countries, destination) {
+			add(model.RiskSignalPhoneCountryMismatch, fmt.Sprintf("Recipient phone country code +%s (%s) does not match destination %s", code, strings.Join(countries, "/"), destination))
+		}
+	}
+
+	// 信号 3：一次性邮箱
+	if enabled(model.RiskSignalDisposableEmail) && shipTo != nil {
+		domain, ok := rules.DisposableEmail(shipTo.Email)
+		rec.Rule(model.RiskSignalDisposableEmail, ok, map[string]interface{}{
+			"domain": domain,
+		})
+		if ok {
+			add(model.RiskSignalDisposableEmail, fmt.Sprintf("Recipient email uses disposable domain %s", domain))
+		}
+	}
+
+	// 信号 4：新账号首单（旧版本 dpmain 未下发账号概况时无法评估）
+	if enabled(model.RiskSignalNewAccountFirstOrder) {
+		if input.Account == nil {
+			unavailable(model.RiskSignalNewAccountFirstOrder)
+		} else if age := input.OrderCreatedAt.Sub(input.Account.CreatedAt); s.newAccount(rec, input.Account, age, rules) {
+			add(model.RiskSignalNewAccountFirstOrder, fmt.Sprintf("First order from an account registered %s ago", formatAge(age)))
+		}
+	}
+
+	// 信号 5：短时间内寄往同一收件人的订单过多（窗口与重复订单检测一致）
+	if enabled(model.RiskSignalRecipientVelocity) && rules.RecipientOrders > 0 && shipTo != nil {
+		s.recipientVelocity(ctx, rec, input, rules, add, unavailable)
+	}
+
+	sort.SliceStable(result.Factors, func(i, j int) bool {
+		return result.Factors[i].Points > result.Factors[j].Points
+	})
+	for _, factor := range result.Factors {
+		result.Score += factor.Points
+	}
+	result.Score = min(result.Score, 100)
+	result.Level = rules.Level(result.Score)
+	rec.Rule("RISK_LEVEL", result.Level != model.RiskLevelLow, map[string]interface{}{
+		"score":        result.Score,
+		"medium_score": rules.MediumScore,
+		"high_score":   rules.HighScore,
+		"level":        result.Level,
+		"unavailable":  result.Unavailable,
+	})
+
+	return result, nil
+}
+
+// newAccount 账号尚无历史订单且注册时长不足 NewAccountDays 天
+func (s *RiskScorer) newAccount(rec *explain.Recorder, account *model.AccountProfile, age time.Duration, rules *risk.Rules) bool {
+	matched := account.PriorOrders == 0 && age < time.Duration(rules.NewAccountDays)*24*time.Hour
+	rec.Rule(model.RiskSignalNewAccountFirstOrder, matched, map[string]interface{}{
+		"account_age":      formatAge(age),
+		"prior_orders":     account.PriorOrders,
+		"new_account_days": rules.NewAccountDays,
+	})
+	return matched
+}
+
+// recipientVelocity 近期订单索引中同一账号、同一收件人的其他订单数达到阈值时命中
+func (s *RiskScorer) recipientVelocity(ctx context.Context, rec *explain.Recorder, input *DiagnoseInput, rules *risk.Rules, add func(signal, message string), unavailable func(signal string)) {
+	if s.duplicates == nil {
+		unavailable(model.RiskSignalRecipientVelocity)
+		return
+	}
+
+	others, err := s.duplicates.RecentOrders(ctx, dedup.Entry{
+		OrderID:         input.OrderID,
+		AccountID:       input.AccountID,
+		MerchantOrderNo: input.MerchantOrderNo,
+		AddressKey:      recipientKey(input.Shipment.ShipTo),
+		SKUs:            shipmentSKUs(input.Shipment),
+		CreatedAt:       input.OrderCreatedAt,
+	})
+	if err != nil {
+		// 索引不可用时不影响其他信号
+		unavailable(model.RiskSignalRecipientVelocity)
+		return
+	}
+
+	rec.Rule(model.RiskSignalRecipientVelocity, len(others) >= rules.RecipientOrders, map[string]interface{}{
+		"other_orders": len(others),
+		"threshold":    rules.RecipientOrders,
+		"window":       fmt.Sprintf("%gh", s.duplicates.Window().Hours()),
+	})
+	if len(others) >= rules.RecipientOrders {
+		add(model.RiskSignalRecipientVelocity, fmt.Sprintf("%d other order(s) to the same recipient within %gh", len(others), s.duplicates.Window().Hours()))
+	}
+}
+
+// formatAge 账号注册时长（不足一天按小时展示）
+func formatAge(age time.Duration) string {
+	if age < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
+
+// containsString 判断字符串切片是否包含指定值
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
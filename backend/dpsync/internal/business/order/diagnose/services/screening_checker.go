@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+	"oip/dpsync/internal/business/screening"
+)
+
+// screeningRulesVersion 筛查规则版本（调整归一化方式或匹配阈值时需同步更新）
+const screeningRulesVersion = "screening-2025-12"
+
+// ScreeningChecker 拒绝往来方筛查器（收件人姓名、公司名与地址对名单做模糊匹配）
+type ScreeningChecker struct {
+	list *screening.Service
+}
+
+// NewScreeningChecker 创建拒绝往来方筛查器实例
+func NewScreeningChecker(list *screening.Service) *ScreeningChecker {
+	return &ScreeningChecker{list: list}
+}
+
+// Type 诊断类型
+func (c *ScreeningChecker) Type() string {
+	return model.DiagnosisTypeScreening
+}
+
+// Diagnose 执行拒绝往来方筛查（实现 Diagnoser 接口）
+func (c *ScreeningChecker) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	return c.Screen(ctx, input.Shipment)
+}
+
+// ResultSchema 诊断结果结构
+func (c *ScreeningChecker) ResultSchema() interface{} {
+	return &model.ScreeningResult{}
+}
+
+// Version 筛查规则与名单的组合版本（实现 VersionedDiagnoser 接口，名单重新加载后缓存自然失效）
+func (c *ScreeningChecker) Version() string {
+	version := "rules:" + screeningRulesVersion
+	if c.list != nil {
+		version += ";list:" + c.list.Version()
+	}
+	return version
+}
+
+// Screen 筛查收件方
+// 姓名与公司名分别与名单主体名称及别名比对；地址仅用于确认命中或在同国时单独触发复核
+func (c *ScreeningChecker) Screen(ctx context.Context, shipment *model.Shipment) (*model.ScreeningResult, error) {
+	result := &model.ScreeningResult{
+		ReviewLevel: model.ScreeningLevelClear,
+		Matches:     make([]model.ScreeningMatch, 0),
+		Screened:    make([]string, 0),
+	}
+	if c.list == nil {
+		return result, nil
+	}
+	result.ListVersion = c.list.Version()
+
+	if shipment == nil || shipment.ShipTo == nil {
+		return result, nil
+	}
+
+	party := screeningParty(shipment.ShipTo)
+	for _, query := range party.Names {
+		result.Screened = append(result.Screened, query.Field)
+	}
+	if party.Address != "" {
+		result.Screened = append(result.Screened, "address")
+	}
+
+	rec := explain.FromContext(ctx)
+	if rec.Enabled() {
+		names := make(map[string]string, len(party.Names))
+		for _, query := range party.Names {
+			names[query.Field] = query.Value
+		}
+		rec.Input("names", names)
+		rec.Input("address", party.Address)
+		rec.Input("country", party.Country)
+	}
+	rec.Input("list_version", result.ListVersion)
+
+	result.Matches = c.list.Screen(party)
+	rec.Rule("DENIED_PARTY_MATCH", len(result.Matches) > 0, map[string]interface{}{
+		"matches": result.Matches,
+	})
+	for _, match := range result.Matches {
+		if match.ReviewLevel == model.ScreeningLevelBlock {
+			result.ReviewLevel = model.ScreeningLevelBlock
+			break
+		}
+		result.ReviewLevel = model.ScreeningLevelReview
+	}
+
+	return result, nil
+}
+
+// screeningParty 从收件地址提取待筛查字段（空字段不参与筛查）
+func screeningParty(shipTo *model.Address) screening.Party {
+	party := screening.Party{
+		Names:   make([]screening.Query, 0, 2),
+		Country: normalizeCountry(shipTo.Country),
+	}
+	if name := strings.TrimSpace(shipTo.ContactName); name != "" {
+		party.Names = append(party.Names, screening.Query{Field: "contact_name", Value: name})
+	}
+	if name := strings.TrimSpace(shipTo.CompanyName); name != "" {
+		party.Names = append(party.Names, screening.Query{Field: "company_name", Value: name})
+	}
+
+	parts := make([]string, 0, 4)
+	for _, part := range []string{shipTo.Street1, shipTo.Street2, shipTo.City, shipTo.PostalCode} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	party.Address = strings.Join(parts, ", ")
+
+	return party
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+	"oip/dpsync/internal/business/fx"
+)
+
+// serviceAuditRulesVersion 选用服务核对规则版本（调整节省金额或替代服务口径时需同步更新）
+const serviceAuditRulesVersion = "service-audit-2025-12"
+
+// ServiceAuditor 商家选用服务核对器
+// 将商家选用的承运商服务（及其报价）与 shipping 诊断计算出的费率对比，给出可节省的运费与更便宜/更快的替代服务
+// 费率取本次任务共享的 shipping 费率结果，核对结论与商家看到的 shipping 诊断项一致
+type ServiceAuditor struct {
+	rateVersion func() string // 费率计算器版本（计入组合版本）
+	converter   *fx.Converter
+}
+
+// NewServiceAuditor 创建选用服务核对器实例
+func NewServiceAuditor(rateVersion func() string, converter *fx.Converter) *ServiceAuditor {
+	return &ServiceAuditor{
+		rateVersion: rateVersion,
+		converter:   converter,
+	}
+}
+
+// Type 诊断类型
+func (a *ServiceAuditor) Type() string {
+	return model.DiagnosisTypeServiceAudit
+}
+
+// Diagnose 执行选用服务核对（实现 Diagnoser 接口）
+// 费率来自降级报价时结果不写入缓存（与 shipping 诊断一致）
+func (a *ServiceAuditor) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	result, degraded, err := a.Audit(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if degraded {
+		return noCache{data: result}, nil
+	}
+	return result, nil
+}
+
+// ResultSchema 诊断结果结构
+func (a *ServiceAuditor) ResultSchema() interface{} {
+	return &model.ServiceAuditResult{}
+}
+
+// Version 核对规则与费率计算器的组合版本（实现 VersionedDiagnoser 接口）
+func (a *ServiceAuditor) Version() string {
+	return "rules:" + serviceAuditRulesVersion + ";" + a.rateVersion()
+}
+
+// OptionsKey 费率依赖的选项与商家选用的服务（实现 OptionsKeyer 接口）
+func (a *ServiceAuditor) OptionsKey(input *DiagnoseInput) string {
+	key := input.rateOptionsKey()
+	if input.Selected != nil {
+		key += ";selected:" + digest(input.Selected)
+	}
+	return key
+}
+
+// Audit 核对商家选用的服务
+// 核对基准：有报价时取报价（换算为费率币种），否则取计算出的该服务应付运费
+// 第二个返回值表示费率中存在承运商报价失败（结果不宜缓存）
+func (a *ServiceAuditor) Audit(ctx context.Context, input *DiagnoseInput) (*model.ServiceAuditResult, bool, error) {
+	result := &model.ServiceAuditResult{
+		Selected:     input.Selected,
+		Alternatives: make([]model.ServiceAuditAlternative, 0),
+	}
+	if input.Selected == nil {
+		result.Unavailable = "no selected service provided with the order"
+		return result, false, nil
+	}
+
+	shipping, err := input.shippingRates(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	degraded := len(shipping.CarrierErrors) > 0
+	rates := shipping.Rates
+	if len(rates) == 0 {
+		result.Unavailable = "no eligible shipping service to compare with"
+		return result, degraded, nil
+	}
+
+	selectedIdx := -1
+	for i, rate := range rates {
+		if strings.EqualFold(rate.Carrier, input.Selected.Carrier) && strings.EqualFold(rate.Service, input.Selected.Service) {
+			selectedIdx = i
+			break
+		}
+	}
+	result.Matched = selectedIdx >= 0
+	result.Currency = rateCurrency
+
+	switch {
+	case input.Selected.QuotedPrice != nil:
+		fee, err := toRateCurrency(a.converter, input.Selected.QuotedPrice.Amount, input.Selected.QuotedPrice.Currency)
+		if err != nil {
+			result.Unavailable = fmt.Sprintf("quoted price cannot be converted to %s", rateCurrency)
+			return result, degraded, nil
+		}
+		result.SelectedFee = fee
+	case result.Matched:
+		result.SelectedFee = rates[selectedIdx].TotalFee
+	default:
+		result.Unavailable = fmt.Sprintf("selected service %s is not among the computed rates and no quoted price was provided", input.Selected.Code())
+		return result, degraded, nil
+	}
+	if result.Matched {
+		result.SelectedDays = rates[selectedIdx].TransitDays
+	}
+
+	cheapest := rates[findCheapest(rates)]
+	result.CheapestCode = fmt.Sprintf("%s_%s", cheapest.Carrier, cheapest.Service)
+	savings := roundTo2Decimals(result.SelectedFee - cheapest.TotalFee)
+
+	rec := explain.FromContext(ctx)
+	rec.Input("selected", input.Selected)
+	rec.Input("selected_fee", result.SelectedFee)
+	rec.Input("selected_matched", result.Matched)
+	rec.Rule("OVERPAYING", savings > 0, map[string]interface{}{
+		"selected_fee":  result.SelectedFee,
+		"cheapest_code": result.CheapestCode,
+		"cheapest_fee":  cheapest.TotalFee,
+		"savings":       savings,
+	})
+	if savings > 0 {
+		result.PotentialSavings = savings
+		result.Overpaying = true
+	}
+
+	for i, rate := range rates {
+		if i == selectedIdx {
+			continue
+		}
+		if alternative, ok := compareAlternative(rate, result); ok {
+			result.Alternatives = append(result.Alternatives, alternative)
+		}
+	}
+	sort.SliceStable(result.Alternatives, func(i, j int) bool {
+		return result.Alternatives[i].Savings > result.Alternatives[j].Savings
+	})
+
+	return result, degraded, nil
+}
+
+// compareAlternative 对比替代服务与选用服务：更便宜的服务均列出；选用服务已匹配时，更快但更贵的服务也列出
+func compareAlternative(rate model.ShippingRate, result *model.ServiceAuditResult) (model.ServiceAuditAlternative, bool) {
+	alternative := model.ServiceAuditAlternative{
+		Carrier:     rate.Carrier,
+		Service:     rate.Service,
+		TotalFee:    rate.TotalFee,
+		TransitDays: rate.TransitDays,
+		Savings:     roundTo2Decimals(result.SelectedFee - rate.TotalFee),
+	}
+	if result.Matched {
+		alternative.DaysDelta = rate.TransitDays - result.SelectedDays
+	}
+
+	switch {
+	case alternative.Savings > 0 && !result.Matched:
+		alternative.Comparison = model.ServiceAuditCheaper
+	case alternative.Savings > 0 && alternative.DaysDelta < 0:
+		alternative.Comparison = model.ServiceAuditCheaperFaster
+	case alternative.Savings > 0 && alternative.DaysDelta == 0:
+		alternative.Comparison = model.ServiceAuditCheaperSameSpeed
+	case alternative.Savings > 0:
+		alternative.Comparison = model.ServiceAuditCheaperSlower
+	case result.Matched && alternative.DaysDelta < 0:
+		alternative.Comparison = model.ServiceAuditFasterCostlier
+	default:
+		return alternative, false
+	}
+	return alternative, true
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/shadow"
+	"oip/dpsync/pkg/logger"
+)
+
+// ShadowRunner 影子诊断执行器
+// 影子诊断器与某个线上诊断器类型相同、使用候选规则或费率卡，在线上诊断完成后对同一订单执行，
+// 结果与线上诊断项对比后写入 shadow.Store，从不进入诊断结果与回调；统计用于判断候选版本能否上线
+// 候选 shipping 诊断器按自己的费率卡重新计算费率；依赖费率的影子诊断器（如 carbon）使用线上任务共享的费率结果，只对比自身规则
+// 影子诊断异步执行，同时执行的任务数受 concurrency 限制（超出时丢弃本次影子诊断），退出时由 Close 等待执行中的任务
+type ShadowRunner struct {
+	live    *Registry // 线上诊断器注册表（校验类型、读取线上版本）
+	shadows []registeredShadow
+	store   shadow.Store
+	logger  logger.Logger
+
+	slots  chan struct{} // 并发执行的影子诊断任务（每个任务执行全部影子诊断器）
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// defaultShadowConcurrency 未指定并发上限时同时执行的影子诊断任务数
+const defaultShadowConcurrency = 16
+
+// registeredShadow 已注册的影子诊断器
+type registeredShadow struct {
+	name  string
+	entry registeredDiagnoser
+}
+
+// ShadowInfo 影子诊断器概况
+type ShadowInfo struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	LiveVersion   string `json:"live_version,omitempty"`
+	ShadowVersion string `json:"shadow_version,omitempty"`
+}
+
+// ShadowStats 影子诊断器概况与累计统计
+type ShadowStats struct {
+	ShadowInfo
+	*shadow.Counters
+	AgreementRate float64 `json:"agreement_rate"` // 一致次数 / 成功对比次数
+}
+
+// NewShadowRunner 创建影子诊断执行器，concurrency<=0 时使用默认并发上限
+func NewShadowRunner(live *Registry, store shadow.Store, concurrency int, log logger.Logger) *ShadowRunner {
+	if concurrency <= 0 {
+		concurrency = defaultShadowConcurrency
+	}
+	return &ShadowRunner{
+		live:    live,
+		shadows: make([]registeredShadow, 0),
+		store:   store,
+		logger:  log,
+		slots:   make(chan struct{}, concurrency),
+	}
+}
+
+// Register 注册影子诊断器（名称唯一，诊断类型必须已有线上诊断器），timeout<=0 时使用默认超时
+func (r *ShadowRunner) Register(name string, d Diagnoser, timeout time.Duration) error {
+	if name == "" {
+		return fmt.Errorf("shadow diagnoser name cannot be empty")
+	}
+	for _, s := range r.shadows {
+		if s.name == name {
+			return fmt.Errorf("shadow diagnoser already registered: %s", name)
+		}
+	}
+	if _, ok := r.live.Get(d.Type()); !ok {
+		return fmt.Errorf("shadow diagnoser %s: no live diagnoser of type %s", name, d.Type())
+	}
+	if timeout <= 0 {
+		timeout = defaultDiagnoserTimeout
+	}
+
+	r.shadows = append(r.shadows, registeredShadow{
+		name:  name,
+		entry: registeredDiagnoser{diagnoser: d, timeout: timeout},
+	})
+	return nil
+}
+
+// Shadows 返回已注册的影子诊断器（按注册顺序）
+func (r *ShadowRunner) Shadows() []ShadowInfo {
+	infos := make([]ShadowInfo, 0, len(r.shadows))
+	for _, s := range r.shadows {
+		infos = append(infos, r.info(s))
+	}
+	return infos
+}
+
+// Has 是否存在指定名称的影子诊断器
+func (r *ShadowRunner) Has(name string) bool {
+	for _, s := range r.shadows {
+		if s.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats 返回各影子诊断器的累计统计
+func (r *ShadowRunner) Stats(ctx context.Context) ([]*ShadowStats, error) {
+	stats := make([]*ShadowStats, 0, len(r.shadows))
+	for _, s := range r.shadows {
+		counters, err := r.store.Counters(ctx, s.name)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, &ShadowStats{
+			ShadowInfo:    r.info(s),
+			Counters:      counters,
+			AgreementRate: counters.AgreementRate(),
+		})
+	}
+	return stats, nil
+}
+
+// Recent 返回影子诊断器最近的结果（新的在前）
+func (r *ShadowRunner) Recent(ctx context.Context, name string, limit int) ([]*shadow.Result, error) {
+	return r.store.Recent(ctx, name, limit)
+}
+
+// Submit 异步执行全部影子诊断（不受调用方 Context 取消影响）
+// 已达并发上限或执行器已关闭时丢弃本次影子诊断并记录日志，不阻塞线上诊断
+func (r *ShadowRunner) Submit(ctx context.Context, input *DiagnoseInput, live *model.DiagnosisResultData) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		r.logger.Warnf(ctx, "[Shadow] Runner closed, skipped: order_id=%s", input.OrderID)
+		return
+	}
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		r.logger.Warnf(ctx, "[Shadow] Concurrency limit %d reached, skipped: order_id=%s", cap(r.slots), input.OrderID)
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer func() {
+			<-r.slots
+			r.wg.Done()
+		}()
+		r.Run(context.WithoutCancel(ctx), input, live)
+	}()
+}
+
+// Close 停止接收新的影子诊断，等待执行中的影子诊断写入结果后关闭结果存储
+// ctx 到期时不再等待并返回错误（此时不关闭结果存储，执行中的影子诊断仍可写入）
+func (r *ShadowRunner) Close(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("wait for shadow diagnosers: %w", ctx.Err())
+	}
+
+	if closer, ok := r.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Run 对线上诊断结果执行全部影子诊断（并发执行，全部完成后返回）
+// 线上未执行或执行失败的诊断类型没有对比基准，对应的影子诊断器跳过
+func (r *ShadowRunner) Run(ctx context.Context, input *DiagnoseInput, live *model.DiagnosisResultData) {
+	liveItems := make(map[string]model.DiagnosisItem, len(live.Items))
+	for _, item := range live.Items {
+		liveItems[item.Type] = item
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range r.shadows {
+		liveItem, ok := liveItems[s.entry.diagnoser.Type()]
+		if !ok || liveItem.Status != model.DiagnosisStatusSuccess {
+			continue
+		}
+
+		wg.Add(1)
+		go func(s registeredShadow, liveItem model.DiagnosisItem) {
+			defer wg.Done()
+			r.runShadow(ctx, s, input, liveItem)
+		}(s, liveItem)
+	}
+	wg.Wait()
+}
+
+// runShadow 执行单个影子诊断器并保存对比结果（保存失败只记录日志）
+func (r *ShadowRunner) runShadow(ctx context.Context, s registeredShadow, input *DiagnoseInput, liveItem model.DiagnosisItem) {
+	start := time.Now()
+	item, _ := runDiagnoser(ctx, s.entry, input, nil)
+
+	result := &shadow.Result{
+		Name:          s.name,
+		Type:          liveItem.Type,
+		OrderID:       input.OrderID,
+		RequestID:     input.RequestID,
+		LiveVersion:   liveItem.Version,
+		ShadowVersion: diagnoserVersion(s.entry),
+		DurationMs:    time.Since(start).Milliseconds(),
+		CreatedAt:     time.Now(),
+	}
+
+	if item.Status != model.DiagnosisStatusSuccess {
+		result.Outcome = shadow.OutcomeFailed
+		result.Error = item.Error
+	} else {
+		result.Data = item.DataJSON
+		differences, err := shadow.Compare(liveItem.DataJSON, item.DataJSON)
+		switch {
+		case err != nil:
+			result.Outcome = shadow.OutcomeFailed
+			result.Error = err.Error()
+		case len(differences) > 0:
+			result.Outcome = shadow.OutcomeDisagreed
+			result.Differences = differences
+		default:
+			result.Outcome = shadow.OutcomeAgreed
+		}
+	}
+
+	if err := r.store.Save(ctx, result); err != nil {
+		r.logger.Warnf(ctx, "[Shadow] Save result failed: name=%s, order_id=%s, error=%v", s.name, input.OrderID, err)
+	}
+}
+
+// info 影子诊断器概况（线上版本取当前注册的线上诊断器）
+func (r *ShadowRunner) info(s registeredShadow) ShadowInfo {
+	info := ShadowInfo{
+		Name:          s.name,
+		Type:          s.entry.diagnoser.Type(),
+		ShadowVersion: diagnoserVersion(s.entry),
+	}
+	if entry, ok := r.live.diagnosers[info.Type]; ok {
+		info.LiveVersion = diagnoserVersion(entry)
+	}
+	return info
+}
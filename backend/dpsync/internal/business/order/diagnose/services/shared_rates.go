@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+)
+
+// rateCalculator 计算 shipping 费率结果（由注册表中的 shipping 诊断器实现）
+type rateCalculator interface {
+	Calculate(ctx context.Context, input *DiagnoseInput) (*model.ShippingResult, error)
+}
+
+// sharedRates 单次诊断任务共享的 shipping 费率结果
+// 承运商报价、服务约束、附加费、协议价与时效估算在一次任务内只执行一次，
+// shipping 诊断项与依赖费率的诊断器（carbon、insurance、service_audit）基于同一份报价得出结论；
+// 各调用方在自己的超时内等待结果，计算本身使用 shipping 诊断器的超时，不受先到调用方的超时影响
+type sharedRates struct {
+	ctx        context.Context // 任务 Context（带 shipping 诊断器的执行轨迹记录器）
+	calculator rateCalculator
+	timeout    time.Duration
+
+	once   sync.Once
+	done   chan struct{}
+	result *model.ShippingResult
+	err    error
+}
+
+// newSharedRates 创建任务内共享的费率结果，calculator 为 nil（未注册 shipping 诊断器）时依赖费率的诊断器直接失败
+func newSharedRates(ctx context.Context, calculator rateCalculator, timeout time.Duration, rec *explain.Recorder) *sharedRates {
+	if rec != nil {
+		ctx = explain.WithRecorder(ctx, rec)
+	}
+	return &sharedRates{
+		ctx:        ctx,
+		calculator: calculator,
+		timeout:    timeout,
+		done:       make(chan struct{}),
+	}
+}
+
+// seed 使用缓存命中的 shipping 诊断项作为共享结果（解析失败时忽略，仍按需计算）
+func (s *sharedRates) seed(item model.DiagnosisItem) {
+	var result model.ShippingResult
+	if err := json.Unmarshal(item.DataJSON, &result); err != nil {
+		return
+	}
+	s.once.Do(func() {
+		s.result = &result
+		close(s.done)
+	})
+}
+
+// get 取共享结果：首次调用时开始计算，之后的调用等待同一次计算完成
+func (s *sharedRates) get(ctx context.Context, input *DiagnoseInput) (*model.ShippingResult, error) {
+	s.once.Do(func() {
+		go s.compute(input)
+	})
+
+	select {
+	case <-s.done:
+		return s.result, s.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// compute 在 shipping 诊断器的超时内计算费率（panic 转为错误）
+func (s *sharedRates) compute(input *DiagnoseInput) {
+	defer close(s.done)
+	defer func() {
+		if r := recover(); r != nil {
+			s.err = fmt.Errorf("shipping calculation panicked: %v", r)
+		}
+	}()
+
+	if s.calculator == nil {
+		s.err = fmt.Errorf("shipping rate calculator not registered")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	s.result, s.err = s.calculator.Calculate(ctx, input)
+}
+
+// shippingRates 本次任务共享的 shipping 费率结果（未经 CompositeHandler 调度时返回错误）
+func (in *DiagnoseInput) shippingRates(ctx context.Context) (*model.ShippingResult, error) {
+	if in.rates == nil {
+		return nil, fmt.Errorf("shipping rates not available")
+	}
+	return in.rates.get(ctx, in)
+}
+
+// sharesRatesWith 共享费率结果是否由 calculator 计算（影子诊断中的候选 shipping 诊断器不共享，自行计算）
+func (in *DiagnoseInput) sharesRatesWith(calculator rateCalculator) bool {
+	return in.rates != nil && in.rates.calculator == calculator
+}
@@ -3,76 +3,458 @@ package services
 import (
 	"context"
 	"fmt"
-	"hash/fnv"
-	"math/rand"
+	"time"
 
 	"oip/common/model"
+	"oip/dpsync/internal/business/carbon"
+	"oip/dpsync/internal/business/eligibility"
+	"oip/dpsync/internal/business/explain"
+	"oip/dpsync/internal/business/fx"
+	"oip/dpsync/internal/business/geo"
+	"oip/dpsync/internal/business/rating"
+	"oip/dpsync/internal/business/surcharge"
+	"oip/dpsync/internal/business/transit"
 )
 
-// ShippingCalculator 物流费率计算器（Mock）
-type ShippingCalculator struct{}
+// rateCurrency 诊断结果中运费的统一币种（承运商报价币种不同时按汇率换算）
+const rateCurrency = "USD"
+
+// ShippingCalculator 物流费率计算器
+// 通过 rating.Quoter 并发查询各承运商报价，过滤不满足服务约束的服务，叠加附加费后给出推荐
+type ShippingCalculator struct {
+	quoter      *rating.Quoter
+	converter   *fx.Converter
+	surcharges  *surcharge.Service
+	eligibility *eligibility.Service
+	transit     *transit.Service
+	geo         *geo.Service
+	emissions   *carbon.Service
+}
 
 // NewShippingCalculator 创建费率计算器实例
-func NewShippingCalculator() *ShippingCalculator {
-	return &ShippingCalculator{}
-}
-
-// Calculate 计算物流费率（Mock - 基于 order_id 生成确定性伪随机费率）
-// shipment 参数包含物流信息,未来可用于更精确的费率计算
-func (c *ShippingCalculator) Calculate(ctx context.Context, orderID string, shipment map[string]interface{}) (*model.ShippingResult, error) {
-	// 1. 基于 order_id 生成确定性种子
-	seed := hashSeed(orderID)
-	rng := rand.New(rand.NewSource(seed))
-
-	// 2. Mock 承运商费率表
-	carriers := []struct {
-		Carrier     string
-		Service     string
-		BaseRate    float64
-		TransitDays int
-	}{
-		{"FedEx", "Ground", 12.50, 3},
-		{"UPS", "Ground", 15.20, 3},
-		{"USPS", "Priority", 9.80, 5},
-		{"DHL", "Express", 28.00, 1},
-	}
-
-	// 3. 生成费率（加入随机波动，但同一 order_id 结果一致）
-	rates := make([]model.ShippingRate, 0, len(carriers))
-	for _, carrier := range carriers {
-		// 随机波动 ±10%
-		fluctuation := carrier.BaseRate * (rng.Float64() - 0.5) * 0.2
-		totalFee := carrier.BaseRate + fluctuation
-
-		rates = append(rates, model.ShippingRate{
-			Carrier:     carrier.Carrier,
-			Service:     carrier.Service,
-			TotalFee:    roundTo2Decimals(totalFee),
-			TransitDays: carrier.TransitDays,
-			Tags:        []string{},
+func NewShippingCalculator(quoter *rating.Quoter, converter *fx.Converter, surcharges *surcharge.Service, constraints *eligibility.Service, calendar *transit.Service, locator *geo.Service, emissions *carbon.Service) *ShippingCalculator {
+	return &ShippingCalculator{
+		quoter:      quoter,
+		converter:   converter,
+		surcharges:  surcharges,
+		eligibility: constraints,
+		transit:     calendar,
+		geo:         locator,
+		emissions:   emissions,
+	}
+}
+
+// Type 诊断类型
+func (c *ShippingCalculator) Type() string {
+	return model.DiagnosisTypeShipping
+}
+
+// Diagnose 执行物流费率诊断（实现 Diagnoser 接口）
+// 费率结果在任务内共享（依赖费率的诊断器使用同一份结果）；存在承运商降级或报价失败时结果不写入缓存
+func (c *ShippingCalculator) Diagnose(ctx context.Context, input *DiagnoseInput) (interface{}, error) {
+	var result *model.ShippingResult
+	var err error
+	if input.sharesRatesWith(c) {
+		result, err = input.shippingRates(ctx)
+	} else {
+		result, err = c.Calculate(ctx, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.CarrierErrors) > 0 {
+		return noCache{data: result}, nil
+	}
+	return result, nil
+}
+
+// ResultSchema 诊断结果结构
+func (c *ShippingCalculator) ResultSchema() interface{} {
+	return &model.ShippingResult{}
+}
+
+// Version 报价来源、汇率表、附加费数据集、服务约束目录、时效日历、邮编质心数据、排放因子与推荐规则的组合版本（实现 VersionedDiagnoser 接口）
+func (c *ShippingCalculator) Version() string {
+	version := "rates:" + c.quoter.Version() + ";" + recommendationVersion
+	if c.converter != nil {
+		version += ";fx:" + c.converter.Version()
+	}
+	if c.surcharges != nil {
+		version += ";surcharge:" + c.surcharges.Version()
+	}
+	if c.eligibility != nil {
+		version += ";constraints:" + c.eligibility.Version()
+	}
+	if c.transit != nil {
+		version += ";calendar:" + c.transit.Version()
+	}
+	if c.geo != nil {
+		version += ";geo:" + c.geo.Version()
+	}
+	if c.emissions != nil {
+		version += ";carbon:" + c.emissions.Version()
+	}
+	return version
+}
+
+// OptionsKey 偏好币种、推荐策略、下单时间、承诺送达日与协议价（实现 OptionsKeyer 接口）
+func (c *ShippingCalculator) OptionsKey(input *DiagnoseInput) string {
+	return input.rateOptionsKey()
+}
+
+// Calculate 计算物流费率
+// 1. 按邮编质心定位始发地与收件地，并发查询各承运商报价（距离用于分区计价；单个承运商超时/失败时降级为本地费率卡）
+// 2. 按服务约束目录（重量、尺寸、线路、PO Box、危险品）剔除不可承运的服务，并记录原因
+// 3. 收件地址命中偏远/扩展区域或判定为住宅地址时，按承运商规则叠加附加费
+// 4. 账号有协议价时按合同计算应付运费（折扣或固定运费，另加燃油附加费），同时保留牌价
+// 5. 按下单时间、截单时间与工作日历估算各服务送达日期区间，有承诺送达日时标记能否兑现
+// 6. 线路距离已知时按计费重、距离与运输方式估算各服务的碳排放
+// 7. 按推荐策略（input.Strategy，为空时推荐最便宜）选出推荐费率并给出理由
+// 8. input.PreferredCurrency 非空时，同时给出偏好币种下的费用及所用汇率
+// 所有服务均被剔除时返回空费率列表（不给出推荐），由 Excluded 说明原因
+func (c *ShippingCalculator) Calculate(ctx context.Context, input *DiagnoseInput) (*model.ShippingResult, error) {
+	rec := explain.FromContext(ctx)
+
+	// 1. 定位线路并查询承运商报价
+	lane := resolveLane(c.geo, input.Shipment)
+	rec.Input("lane", lane)
+	rec.Input("total_weight_kg", input.Shipment.TotalWeightKg())
+	carrierQuotes := c.quoter.QuoteAll(ctx, &rating.QuoteRequest{
+		Shipment:   input.Shipment,
+		DistanceKm: laneDistance(lane),
+	})
+
+	// 2. 收件地址类型判定
+	addr := shipToAddress(input.Shipment)
+	classification := surcharge.Classify(addr)
+	facts := eligibility.Facts{
+		Origin:         originCountry(input.Shipment),
+		Destination:    destinationCountry(input.Shipment),
+		POBox:          surcharge.IsPOBox(addr),
+		DangerousGoods: containsDangerousGoods(input.Shipment),
+	}
+	rec.Input("address_type", classification.Type)
+	rec.Input("eligibility_facts", map[string]interface{}{
+		"origin":          facts.Origin,
+		"destination":     facts.Destination,
+		"po_box":          facts.POBox,
+		"dangerous_goods": facts.DangerousGoods,
+	})
+
+	// 3. 过滤不可承运的服务，报价换算为统一币种，叠加附加费
+	rates := make([]model.ShippingRate, 0)
+	excluded := make([]model.ExcludedService, 0)
+	carrierErrors := make([]model.CarrierError, 0)
+	for _, cq := range carrierQuotes {
+		if cq.Error != "" {
+			carrierErrors = append(carrierErrors, model.CarrierError{
+				Carrier:  cq.Carrier,
+				Error:    cq.Error,
+				Fallback: cq.Fallback,
+			})
+		}
+
+		surcharges, err := c.carrierSurcharges(cq.Carrier, addr, classification)
+		if err != nil {
+			return nil, err
+		}
+		rec.Rule("SURCHARGES", len(surcharges) > 0, map[string]interface{}{
+			"carrier":    cq.Carrier,
+			"surcharges": surcharges,
+		})
+
+		for _, quote := range cq.Quotes {
+			reasons := c.checkEligibility(quote, input.Shipment, facts)
+			rec.Rule("SERVICE_EXCLUDED", len(reasons) > 0, map[string]interface{}{
+				"carrier": quote.Carrier,
+				"service": quote.Service,
+				"reasons": reasons,
+			})
+			if len(reasons) > 0 {
+				excluded = append(excluded, model.ExcludedService{
+					Carrier: quote.Carrier,
+					Service: quote.Service,
+					Reasons: reasons,
+				})
+				continue
+			}
+
+			baseFee, err := c.toRateCurrency(quote.Amount, quote.Currency)
+			if err != nil {
+				return nil, err
+			}
+
+			listFee := roundTo2Decimals(baseFee + sumSurcharges(surcharges))
+			rate := model.ShippingRate{
+				Carrier:     quote.Carrier,
+				Service:     quote.Service,
+				BaseFee:     baseFee,
+				ListFee:     listFee,
+				TotalFee:    listFee,
+				Currency:    rateCurrency,
+				TransitDays: quote.TransitDays,
+				Tags:        []string{},
+				Surcharges:  surcharges,
+				Fallback:    cq.Fallback,
+				Zone:        quote.Zone,
+			}
+
+			if contract := model.FindRateContract(input.Contracts, quote.Carrier, quote.Service); contract != nil {
+				if err := c.applyContract(&rate, contract); err != nil {
+					return nil, err
+				}
+				rec.Rule("CONTRACT_PRICE", true, map[string]interface{}{
+					"carrier":   rate.Carrier,
+					"service":   rate.Service,
+					"list_fee":  rate.ListFee,
+					"total_fee": rate.TotalFee,
+				})
+			}
+
+			rates = append(rates, rate)
+		}
+	}
+
+	if len(rates) == 0 && len(excluded) == 0 {
+		return nil, fmt.Errorf("no carrier quotes available")
+	}
+
+	result := &model.ShippingResult{
+		Rates:                rates,
+		AddressType:          classification.Type,
+		AddressTypeReason:    classification.Reason,
+		PromisedDeliveryDate: input.PromisedDeliveryDate,
+		Lane:                 lane,
+	}
+
+	if err := c.estimateDelivery(result, input); err != nil {
+		return nil, err
+	}
+
+	c.estimateEmissions(result, input.Shipment)
+
+	if len(rates) > 0 {
+		// 4. 标记 CHEAPEST、FASTEST 和 LOWEST_EMISSION（无排放估算时不标记）
+		cheapestIdx := findCheapest(rates)
+		fastestIdx := findFastest(rates)
+		rates[cheapestIdx].Tags = append(rates[cheapestIdx].Tags, "CHEAPEST")
+		rates[fastestIdx].Tags = append(rates[fastestIdx].Tags, "FASTEST")
+		if lowestIdx, ok := findLowestEmission(rates); ok {
+			rates[lowestIdx].Tags = append(rates[lowestIdx].Tags, "LOWEST_EMISSION")
+		}
+
+		// 5. 按策略推荐
+		strategy := input.Strategy
+		if strategy == nil {
+			strategy = model.DefaultRecommendationStrategy()
+		}
+		picked := recommend(rates, strategy)
+		result.RecommendedCode = fmt.Sprintf("%s_%s", rates[picked.index].Carrier, rates[picked.index].Service)
+		result.Strategy = strategy
+		result.RecommendReason = picked.reason
+		rec.Rule("RECOMMENDATION", true, map[string]interface{}{
+			"strategy":         strategy,
+			"cheapest":         rateName(rates[cheapestIdx]),
+			"fastest":          rateName(rates[fastestIdx]),
+			"recommended_code": result.RecommendedCode,
+			"reason":           picked.reason,
+		})
+	}
+
+	if len(excluded) > 0 {
+		result.Excluded = excluded
+	}
+	if c.eligibility != nil {
+		result.ConstraintVersion = c.eligibility.Version()
+	}
+	if len(carrierErrors) > 0 {
+		result.CarrierErrors = carrierErrors
+	}
+	if c.surcharges != nil {
+		result.SurchargeVersion = c.surcharges.Version()
+	}
+
+	// 6. 换算为账号偏好币种（保留原币种金额）
+	if err := c.applyPreferredCurrency(result, input.PreferredCurrency); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkEligibility 检查报价对应的服务是否满足约束，返回不满足的原因
+func (c *ShippingCalculator) checkEligibility(quote rating.Quote, shipment *model.Shipment, facts eligibility.Facts) []string {
+	if c.eligibility == nil {
+		return nil
+	}
+	return c.eligibility.Check(quote.Carrier, quote.Service, shipment, facts)
+}
+
+// toRateCurrency 报价金额换算为统一币种
+func (c *ShippingCalculator) toRateCurrency(amount float64, currency string) (float64, error) {
+	return toRateCurrency(c.converter, amount, currency)
+}
+
+// toRateCurrency 金额换算为费率统一币种（未配置汇率服务或币种相同时只做取整）
+func toRateCurrency(converter *fx.Converter, amount float64, currency string) (float64, error) {
+	if currency == "" || currency == rateCurrency || converter == nil {
+		return roundTo2Decimals(amount), nil
+	}
+	converted, _, err := converter.Convert(amount, currency, rateCurrency)
+	if err != nil {
+		return 0, fmt.Errorf("convert quote currency failed: %w", err)
+	}
+	return converted.Amount, nil
+}
+
+// carrierSurcharges 查询承运商附加费，并换算为费率币种
+func (c *ShippingCalculator) carrierSurcharges(carrier string, addr *surcharge.Address, classification surcharge.Classification) ([]model.Surcharge, error) {
+	if c.surcharges == nil {
+		return nil, nil
+	}
+
+	surcharges := c.surcharges.Surcharges(carrier, addr, classification)
+	for i := range surcharges {
+		if surcharges[i].Currency == rateCurrency || c.converter == nil {
+			continue
+		}
+		converted, _, err := c.converter.Convert(surcharges[i].Amount, surcharges[i].Currency, rateCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("convert surcharge currency failed: %w", err)
+		}
+		surcharges[i].Amount = converted.Amount
+		surcharges[i].Currency = converted.Currency
+	}
+
+	return surcharges, nil
+}
+
+// estimateDelivery 为每条费率估算送达日期区间，并对比承诺送达日
+func (c *ShippingCalculator) estimateDelivery(result *model.ShippingResult, input *DiagnoseInput) error {
+	if c.transit == nil {
+		return nil
+	}
+
+	createdAt := input.OrderCreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	originState := ""
+	if input.Shipment != nil && input.Shipment.ShipFrom != nil {
+		originState = input.Shipment.ShipFrom.State
+	}
+
+	for i := range result.Rates {
+		rate := &result.Rates[i]
+		estimate, err := c.transit.Estimate(&transit.EstimateRequest{
+			CreatedAt:          createdAt,
+			Carrier:            rate.Carrier,
+			OriginCountry:      originCountry(input.Shipment),
+			OriginState:        originState,
+			DestinationCountry: destinationCountry(input.Shipment),
+			TransitDays:        rate.TransitDays,
 		})
+		if err != nil {
+			return fmt.Errorf("estimate delivery for %s failed: %w", rateName(*rate), err)
+		}
+
+		rate.EstimatedDelivery = estimate
+		if input.PromisedDeliveryDate != "" {
+			rate.PromiseStatus = estimate.PromiseStatus(input.PromisedDeliveryDate)
+		}
 	}
+	result.CalendarVersion = c.transit.Version()
 
-	// 4. 标记 CHEAPEST 和 FASTEST
-	cheapestIdx := findCheapest(rates)
-	fastestIdx := findFastest(rates)
-	rates[cheapestIdx].Tags = append(rates[cheapestIdx].Tags, "CHEAPEST")
-	rates[fastestIdx].Tags = append(rates[fastestIdx].Tags, "FASTEST")
+	return nil
+}
 
-	// 5. 推荐最便宜的
-	recommendedCode := fmt.Sprintf("%s_%s", rates[cheapestIdx].Carrier, rates[cheapestIdx].Service)
+// estimateEmissions 按计费重与线路距离为每条费率估算碳排放（未配置排放因子或距离未知时跳过）
+func (c *ShippingCalculator) estimateEmissions(result *model.ShippingResult, shipment *model.Shipment) {
+	distance := laneDistance(result.Lane)
+	if c.emissions == nil || distance == nil {
+		return
+	}
 
-	return &model.ShippingResult{
-		RecommendedCode: recommendedCode,
-		Rates:           rates,
-	}, nil
+	weight := billableWeightKg(shipment)
+	for i := range result.Rates {
+		rate := &result.Rates[i]
+		estimate := c.emissions.Estimate(rate.Carrier, rate.Service, weight, *distance)
+		rate.Emission = &model.RateEmission{
+			Mode:   estimate.Mode,
+			Co2eKg: estimate.Co2eKg,
+		}
+	}
 }
 
-// hashSeed 基于字符串生成确定性种子
-func hashSeed(s string) int64 {
-	h := fnv.New64a()
-	h.Write([]byte(s))
-	return int64(h.Sum64())
+// applyContract 按协议价计算应付运费
+// 固定运费优先于折扣；燃油附加费按协议基础运费计算，作为 FUEL 附加费计入应付运费
+func (c *ShippingCalculator) applyContract(rate *model.ShippingRate, contract *model.RateContract) error {
+	contractBase := rate.BaseFee * (1 - contract.DiscountPercent/100)
+	if contract.FixedFee != nil {
+		fixed, err := c.toRateCurrency(contract.FixedFee.Amount, contract.FixedFee.Currency)
+		if err != nil {
+			return fmt.Errorf("convert contract fixed fee failed: %w", err)
+		}
+		contractBase = fixed
+	}
+	contractBase = roundTo2Decimals(contractBase)
+
+	// 同一承运商的费率共享附加费切片，追加前先复制
+	surcharges := make([]model.Surcharge, 0, len(rate.Surcharges)+1)
+	surcharges = append(surcharges, rate.Surcharges...)
+	if contract.FuelSurchargePercent != nil && *contract.FuelSurchargePercent > 0 {
+		surcharges = append(surcharges, model.Surcharge{
+			Type:        model.SurchargeTypeFuel,
+			Description: fmt.Sprintf("contract fuel surcharge %.2f%%", *contract.FuelSurchargePercent),
+			Amount:      roundTo2Decimals(contractBase * *contract.FuelSurchargePercent / 100),
+			Currency:    rateCurrency,
+		})
+	}
+
+	rate.Surcharges = surcharges
+	rate.TotalFee = roundTo2Decimals(contractBase + sumSurcharges(surcharges))
+	rate.Contract = &model.ContractPrice{
+		Service:              contract.Service,
+		DiscountPercent:      contract.DiscountPercent,
+		FixedFee:             contract.FixedFee,
+		FuelSurchargePercent: contract.FuelSurchargePercent,
+		BaseFee:              contractBase,
+		Savings:              roundTo2Decimals(rate.ListFee - rate.TotalFee),
+	}
+
+	return nil
+}
+
+// sumSurcharges 附加费合计
+func sumSurcharges(surcharges []model.Surcharge) float64 {
+	total := 0.0
+	for _, s := range surcharges {
+		total += s.Amount
+	}
+	return total
+}
+
+// applyPreferredCurrency 为每条费率补充偏好币种金额，并记录所用汇率
+func (c *ShippingCalculator) applyPreferredCurrency(result *model.ShippingResult, preferredCurrency string) error {
+	if preferredCurrency == "" || c.converter == nil {
+		return nil
+	}
+
+	rate, err := c.converter.Rate(rateCurrency, preferredCurrency)
+	if err != nil {
+		return fmt.Errorf("convert to preferred currency failed: %w", err)
+	}
+
+	for i := range result.Rates {
+		result.Rates[i].PreferredFee = &model.Money{
+			Amount:   roundTo2Decimals(result.Rates[i].TotalFee * rate.Rate),
+			Currency: rate.To,
+		}
+	}
+	result.PreferredCurrency = rate.To
+	result.ExchangeRate = rate
+
+	return nil
 }
 
 // roundTo2Decimals 四舍五入到两位小数
@@ -80,28 +462,40 @@ func roundTo2Decimals(f float64) float64 {
 	return float64(int(f*100+0.5)) / 100
 }
 
-// findCheapest 找到最便宜的费率索引
+// findCheapest 找到最便宜的费率索引（运费相同时取时效更短的）
 func findCheapest(rates []model.ShippingRate) int {
 	minIdx := 0
-	minFee := rates[0].TotalFee
 	for i, rate := range rates {
-		if rate.TotalFee < minFee {
-			minFee = rate.TotalFee
+		if isCheaper(rate, rates[minIdx]) {
 			minIdx = i
 		}
 	}
 	return minIdx
 }
 
-// findFastest 找到最快的费率索引
+// findFastest 找到最快的费率索引（时效相同时取运费更低的）
 func findFastest(rates []model.ShippingRate) int {
 	minIdx := 0
-	minDays := rates[0].TransitDays
 	for i, rate := range rates {
-		if rate.TransitDays < minDays {
-			minDays = rate.TransitDays
+		if rate.TransitDays < rates[minIdx].TransitDays ||
+			(rate.TransitDays == rates[minIdx].TransitDays && rate.TotalFee < rates[minIdx].TotalFee) {
 			minIdx = i
 		}
 	}
 	return minIdx
 }
+
+// findLowestEmission 找到排放最低的费率索引（排放相同时取运费更低的；没有排放估算时 ok=false）
+func findLowestEmission(rates []model.ShippingRate) (int, bool) {
+	minIdx := -1
+	for i, rate := range rates {
+		if rate.Emission == nil {
+			continue
+		}
+		if minIdx < 0 || rate.Emission.Co2eKg < rates[minIdx].Emission.Co2eKg ||
+			(rate.Emission.Co2eKg == rates[minIdx].Emission.Co2eKg && rate.TotalFee < rates[minIdx].TotalFee) {
+			minIdx = i
+		}
+	}
+	return minIdx, minIdx >= 0
+}
@@ -1,13 +1,30 @@
 package diagnose
 
-import "oip/common/model"
+import (
+	"encoding/json"
+	"time"
+
+	"oip/common/model"
+)
 
 // DiagnosePayload Job 消息中的业务数据
 type DiagnosePayload struct {
-	OrderID         string                 `json:"order_id"`
-	AccountID       int64                  `json:"account_id"`
-	MerchantOrderNo string                 `json:"merchant_order_no"`
-	Shipment        map[string]interface{} `json:"shipment"`
+	OrderID              string                        `json:"order_id"`
+	AccountID            int64                         `json:"account_id"`
+	MerchantOrderNo      string                        `json:"merchant_order_no"`
+	Shipment             json.RawMessage               `json:"shipment"` // 在 PreProcess 中严格解码为 model.Shipment
+	Diagnosers           []string                      `json:"diagnosers,omitempty"`
+	PreferredCurrency    string                        `json:"preferred_currency,omitempty"`
+	Strategy             *model.RecommendationStrategy `json:"strategy,omitempty"`
+	Contracts            []model.RateContract          `json:"contracts,omitempty"`
+	OrderCreatedAt       time.Time                     `json:"order_created_at"`
+	PromisedDeliveryDate string                        `json:"promised_delivery_date,omitempty"`
+	Boxes                []model.Box                   `json:"boxes,omitempty"`
+	Incoterm             string                        `json:"incoterm,omitempty"`
+	Account              *model.AccountProfile         `json:"account,omitempty"`
+	Selected             *model.SelectedService        `json:"selected,omitempty"`
+	Enrichments          []model.ItemEnrichment        `json:"enrichments,omitempty"`
+	Explain              bool                          `json:"explain,omitempty"`
 }
 
 // DiagnoseInput 诊断服务输入
@@ -16,19 +33,26 @@ type DiagnoseInput struct {
 	OrderID         string
 	AccountID       int64
 	MerchantOrderNo string
-	Shipment        map[string]interface{}
+	Shipment        *model.Shipment
+	Diagnosers      []string
 }
 
 // DiagnosisResultData 业务处理结果
 type DiagnosisResultData struct {
-	Items       []model.DiagnosisItem
-	OrderID     string
-	ProcessedAt int64
+	EngineVersion string
+	Items         []model.DiagnosisItem
+	Traces        []model.DiagnoserTrace
+	Enrichments   []model.ItemEnrichment
+	OrderID       string
+	ProcessedAt   int64
 }
 
 // DiagnosisOutput 最终输出结构
 type DiagnosisOutput struct {
-	Items       []model.DiagnosisItem `json:"items"`
-	OrderID     string                `json:"order_id"`
-	ProcessedAt int64                 `json:"processed_at"`
+	EngineVersion string                 `json:"engine_version,omitempty"`
+	Items         []model.DiagnosisItem  `json:"items"`
+	Traces        []model.DiagnoserTrace `json:"traces,omitempty"` // 仅 explain 模式
+	Enrichments   []model.ItemEnrichment `json:"enrichments,omitempty"`
+	OrderID       string                 `json:"order_id"`
+	ProcessedAt   int64                  `json:"processed_at"`
 }
@@ -0,0 +1,60 @@
+package rating
+
+import (
+	"context"
+
+	"oip/common/model"
+)
+
+// CarrierAdapter 承运商适配器
+// 屏蔽各承运商报价接口差异，统一提供报价与服务可用性查询
+type CarrierAdapter interface {
+	// Carrier 承运商名称（如 FedEx/UPS）
+	Carrier() string
+
+	// Quote 查询该承运商对货件可用的全部服务报价
+	Quote(ctx context.Context, req *QuoteRequest) ([]Quote, error)
+
+	// Availability 查询该承运商各服务对货件的可用性
+	Availability(ctx context.Context, req *QuoteRequest) ([]ServiceAvailability, error)
+}
+
+// QuoteRequest 报价请求（承运商报价接口的请求体）
+type QuoteRequest struct {
+	Shipment   *model.Shipment `json:"shipment"`
+	DistanceKm *float64        `json:"distance_km,omitempty"` // 始发地与收件地的大圆距离（无法定位时为空，不按距离分区计价）
+}
+
+// Quote 单个服务报价
+type Quote struct {
+	Carrier     string  `json:"carrier"`
+	Service     string  `json:"service"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+	TransitDays int     `json:"transit_days"`
+	Zone        string  `json:"zone,omitempty"` // 按距离分区计价时命中的分区
+}
+
+// ServiceAvailability 服务可用性
+type ServiceAvailability struct {
+	Service   string `json:"service"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"` // 不可用原因
+}
+
+// QuoteResponse 报价接口响应体
+type QuoteResponse struct {
+	Carrier string  `json:"carrier"`
+	Quotes  []Quote `json:"quotes"`
+}
+
+// AvailabilityResponse 可用性接口响应体
+type AvailabilityResponse struct {
+	Carrier  string                `json:"carrier"`
+	Services []ServiceAvailability `json:"services"`
+}
+
+// ErrorResponse 承运商接口错误响应体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
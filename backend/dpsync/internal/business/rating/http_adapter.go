@@ -0,0 +1,105 @@
+package rating
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HTTPAdapter 基于 HTTP 的承运商适配器
+// 接口约定：
+//
+//	POST {endpoint}/v1/carriers/{carrier}/rates         请求体 QuoteRequest，响应 QuoteResponse
+//	POST {endpoint}/v1/carriers/{carrier}/availability  请求体 QuoteRequest，响应 AvailabilityResponse
+type HTTPAdapter struct {
+	carrier  string
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPAdapter 创建 HTTP 承运商适配器
+// 超时由调用方通过 ctx 控制，client 为 nil 时使用 http.DefaultClient
+func NewHTTPAdapter(carrier, endpoint string, client *http.Client) *HTTPAdapter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPAdapter{
+		carrier:  carrier,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   client,
+	}
+}
+
+// Carrier 承运商名称
+func (a *HTTPAdapter) Carrier() string {
+	return a.carrier
+}
+
+// Quote 查询报价
+func (a *HTTPAdapter) Quote(ctx context.Context, req *QuoteRequest) ([]Quote, error) {
+	var resp QuoteResponse
+	if err := a.post(ctx, "rates", req, &resp); err != nil {
+		return nil, err
+	}
+
+	// 以适配器配置的承运商名称为准，避免接口返回的名称大小写不一致
+	for i := range resp.Quotes {
+		resp.Quotes[i].Carrier = a.carrier
+	}
+
+	return resp.Quotes, nil
+}
+
+// Availability 查询服务可用性
+func (a *HTTPAdapter) Availability(ctx context.Context, req *QuoteRequest) ([]ServiceAvailability, error) {
+	var resp AvailabilityResponse
+	if err := a.post(ctx, "availability", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Services, nil
+}
+
+// post 发送请求并解析响应
+func (a *HTTPAdapter) post(ctx context.Context, action string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal %s request failed: %w", action, err)
+	}
+
+	reqURL := fmt.Sprintf("%s/v1/carriers/%s/%s", a.endpoint, url.PathEscape(a.carrier), action)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create %s request failed: %w", action, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := a.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s %s request failed: %w", a.carrier, action, err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("read %s response failed: %w", action, err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("%s %s returned %d: %s", a.carrier, action, httpResp.StatusCode, errResp.Error)
+		}
+		return fmt.Errorf("%s %s returned %d", a.carrier, action, httpResp.StatusCode)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("unmarshal %s response failed: %w", action, err)
+	}
+
+	return nil
+}
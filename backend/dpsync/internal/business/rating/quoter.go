@@ -0,0 +1,162 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"oip/common/model"
+	"oip/dpsync/internal/business/explain"
+)
+
+// defaultCarrierTimeout 单个承运商报价的默认超时
+const defaultCarrierTimeout = 2 * time.Second
+
+// CarrierQuotes 单个承运商的报价结果
+type CarrierQuotes struct {
+	Carrier  string
+	Quotes   []Quote
+	Fallback bool   // 是否使用了降级报价
+	Error    string // 主适配器错误（降级成功时仍保留，便于排查）
+}
+
+// carrierEntry 已注册的承运商适配器及其降级适配器
+type carrierEntry struct {
+	adapter  CarrierAdapter
+	fallback CarrierAdapter // 可选
+}
+
+// Quoter 多承运商报价器
+// 并发调用各承运商适配器，单个承运商超时或失败时使用降级适配器（通常为本地费率卡）
+type Quoter struct {
+	entries []carrierEntry
+	timeout time.Duration
+}
+
+// NewQuoter 创建报价器，timeout<=0 时使用默认超时
+func NewQuoter(timeout time.Duration) *Quoter {
+	if timeout <= 0 {
+		timeout = defaultCarrierTimeout
+	}
+	return &Quoter{
+		entries: make([]carrierEntry, 0),
+		timeout: timeout,
+	}
+}
+
+// NewDefaultQuoter 使用内置费率卡创建报价器（离线模式）
+func NewDefaultQuoter() *Quoter {
+	return NewStaticQuoter(DefaultRateCards())
+}
+
+// NewStaticQuoter 使用给定费率卡创建报价器（离线模式）
+func NewStaticQuoter(cards []*RateCard) *Quoter {
+	q := NewQuoter(0)
+	for _, card := range cards {
+		q.Add(NewStaticAdapter(card), nil)
+	}
+	return q
+}
+
+// Add 注册承运商适配器（按注册顺序输出报价），fallback 可为 nil
+func (q *Quoter) Add(adapter CarrierAdapter, fallback CarrierAdapter) {
+	q.entries = append(q.entries, carrierEntry{
+		adapter:  adapter,
+		fallback: fallback,
+	})
+}
+
+// Carriers 已注册的承运商
+func (q *Quoter) Carriers() []string {
+	carriers := make([]string, 0, len(q.entries))
+	for _, entry := range q.entries {
+		carriers = append(carriers, entry.adapter.Carrier())
+	}
+	return carriers
+}
+
+// Version 报价来源版本（参与诊断结果缓存键计算）
+// 静态费率卡使用费率卡版本，实时接口标记为 live
+func (q *Quoter) Version() string {
+	parts := make([]string, 0, len(q.entries))
+	for _, entry := range q.entries {
+		version := "live"
+		if static, ok := entry.adapter.(*StaticAdapter); ok {
+			version = static.Version()
+		}
+		parts = append(parts, entry.adapter.Carrier()+"@"+version)
+	}
+	return strings.Join(parts, ",")
+}
+
+// QuoteAll 并发查询所有承运商报价（结果顺序与注册顺序一致）
+func (q *Quoter) QuoteAll(ctx context.Context, req *QuoteRequest) []CarrierQuotes {
+	results := make([]CarrierQuotes, len(q.entries))
+
+	var wg sync.WaitGroup
+	for i, entry := range q.entries {
+		wg.Add(1)
+		go func(i int, entry carrierEntry) {
+			defer wg.Done()
+			results[i] = q.quoteCarrier(ctx, entry, req)
+		}(i, entry)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// quoteCarrier 查询单个承运商报价，失败时降级
+func (q *Quoter) quoteCarrier(ctx context.Context, entry carrierEntry, req *QuoteRequest) CarrierQuotes {
+	result := CarrierQuotes{Carrier: entry.adapter.Carrier()}
+
+	quoteCtx, cancel := context.WithTimeout(ctx, q.timeout)
+	quotes, err := entry.adapter.Quote(quoteCtx, req)
+	cancel()
+	if err == nil {
+		result.Quotes = quotes
+		if _, static := entry.adapter.(*StaticAdapter); !static {
+			recordLiveQuotes(ctx, quotes)
+		}
+		return result
+	}
+
+	result.Error = err.Error()
+	if quoteCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		result.Error = fmt.Sprintf("quote timeout after %s", q.timeout)
+	}
+
+	if entry.fallback == nil || ctx.Err() != nil {
+		return result
+	}
+
+	fallbackQuotes, fallbackErr := entry.fallback.Quote(ctx, req)
+	if fallbackErr != nil {
+		result.Error = fmt.Sprintf("%s; fallback failed: %v", result.Error, fallbackErr)
+		return result
+	}
+
+	result.Quotes = fallbackQuotes
+	result.Fallback = true
+	return result
+}
+
+// recordLiveQuotes explain 模式下记录实时接口报价（接口不返回费率明细，只记录金额）
+func recordLiveQuotes(ctx context.Context, quotes []Quote) {
+	rec := explain.FromContext(ctx)
+	if !rec.Enabled() {
+		return
+	}
+	for _, quote := range quotes {
+		rec.RateRow(model.TraceRateRow{
+			Carrier:  quote.Carrier,
+			Service:  quote.Service,
+			Source:   "live",
+			Zone:     quote.Zone,
+			Amount:   quote.Amount,
+			Currency: quote.Currency,
+		})
+	}
+}
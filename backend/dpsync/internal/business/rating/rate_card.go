@@ -0,0 +1,262 @@
+package rating
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"oip/common/model"
+)
+
+// RateCard 承运商费率卡（静态适配器与 carrier-sandbox 共用）
+type RateCard struct {
+	Carrier  string         `json:"carrier"`
+	Version  string         `json:"version"`
+	Currency string         `json:"currency"`
+	Services []ServiceRate  `json:"services"`
+	Zones    []DistanceZone `json:"zones,omitempty"` // 距离分区（按 MaxDistanceKm 升序；为空或距离未知时不分区）
+}
+
+// DistanceZone 距离分区：始发地到收件地距离不超过 MaxDistanceKm 时运费乘以 Multiplier
+type DistanceZone struct {
+	Zone          string  `json:"zone"`
+	MaxDistanceKm float64 `json:"max_distance_km"` // 0 表示不限（只能是最后一个分区）
+	Multiplier    float64 `json:"multiplier"`
+}
+
+// ServiceRate 单个服务的计费规则：运费 = BaseRate + PerKg × 总重量（千克）
+type ServiceRate struct {
+	Service     string   `json:"service"`
+	BaseRate    float64  `json:"base_rate"`
+	PerKg       float64  `json:"per_kg"`
+	TransitDays int      `json:"transit_days"`
+	MaxWeightKg float64  `json:"max_weight_kg,omitempty"` // 0 表示不限
+	Countries   []string `json:"countries,omitempty"`     // 可派送目的国，为空表示不限
+}
+
+// defaultGroundZones 内置陆运距离分区（近距离优惠、远距离加价，参照美国陆运 Zone 2-8 的大致距离段）
+func defaultGroundZones() []DistanceZone {
+	return []DistanceZone{
+		{Zone: "ZONE_2", MaxDistanceKm: 250, Multiplier: 0.85},
+		{Zone: "ZONE_3", MaxDistanceKm: 500, Multiplier: 0.92},
+		{Zone: "ZONE_4", MaxDistanceKm: 1000, Multiplier: 1.00},
+		{Zone: "ZONE_5", MaxDistanceKm: 2000, Multiplier: 1.08},
+		{Zone: "ZONE_6", MaxDistanceKm: 3000, Multiplier: 1.15},
+		{Zone: "ZONE_8", Multiplier: 1.25},
+	}
+}
+
+// DefaultRateCards 内置费率卡（未配置承运商接口时使用，亦作为接口失败时的降级报价）
+// 重量、尺寸、线路等服务约束由 eligibility 目录统一判定，内置费率卡不再重复限制
+func DefaultRateCards() []*RateCard {
+	return []*RateCard{
+		{
+			Carrier:  "FedEx",
+			Version:  "builtin-2025-12-zoned",
+			Currency: "USD",
+			Services: []ServiceRate{
+				{Service: "Ground", BaseRate: 12.50, PerKg: 1.20, TransitDays: 3},
+			},
+			Zones: defaultGroundZones(),
+		},
+		{
+			Carrier:  "UPS",
+			Version:  "builtin-2025-12-zoned",
+			Currency: "USD",
+			Services: []ServiceRate{
+				{Service: "Ground", BaseRate: 15.20, PerKg: 1.00, TransitDays: 3},
+			},
+			Zones: defaultGroundZones(),
+		},
+		{
+			Carrier:  "USPS",
+			Version:  "builtin-2025-12",
+			Currency: "USD",
+			Services: []ServiceRate{
+				{Service: "Priority", BaseRate: 9.80, PerKg: 1.50, TransitDays: 5},
+			},
+		},
+		{
+			Carrier:  "DHL",
+			Version:  "builtin-2025-12",
+			Currency: "USD",
+			Services: []ServiceRate{
+				{Service: "Express", BaseRate: 28.00, PerKg: 2.50, TransitDays: 1},
+			},
+		},
+	}
+}
+
+// rateCardFile 费率卡文件结构
+type rateCardFile struct {
+	Cards []*RateCard `json:"cards"`
+}
+
+// LoadRateCards 从 JSON 文件加载费率卡（{"cards": [...]}，每个承运商一张，替换全部内置费率卡）
+func LoadRateCards(path string) ([]*RateCard, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rate cards failed: %w", err)
+	}
+
+	var file rateCardFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unmarshal rate cards failed: %w", err)
+	}
+	if len(file.Cards) == 0 {
+		return nil, fmt.Errorf("rate cards file has no cards")
+	}
+
+	seen := make(map[string]bool, len(file.Cards))
+	for _, card := range file.Cards {
+		if card == nil {
+			return nil, fmt.Errorf("rate cards file contains an empty card")
+		}
+		if err := card.Validate(); err != nil {
+			return nil, err
+		}
+		carrier := strings.ToUpper(card.Carrier)
+		if seen[carrier] {
+			return nil, fmt.Errorf("duplicate rate card for carrier %s", card.Carrier)
+		}
+		seen[carrier] = true
+	}
+
+	return file.Cards, nil
+}
+
+// Validate 校验费率卡
+func (c *RateCard) Validate() error {
+	if c.Carrier == "" {
+		return fmt.Errorf("rate card carrier is required")
+	}
+	if c.Currency == "" {
+		return fmt.Errorf("rate card %s currency is required", c.Carrier)
+	}
+	if len(c.Services) == 0 {
+		return fmt.Errorf("rate card %s has no services", c.Carrier)
+	}
+	for _, s := range c.Services {
+		if s.Service == "" {
+			return fmt.Errorf("rate card %s has service without name", c.Carrier)
+		}
+		if s.BaseRate < 0 || s.PerKg < 0 || s.TransitDays < 0 || s.MaxWeightKg < 0 {
+			return fmt.Errorf("rate card %s service %s has negative values", c.Carrier, s.Service)
+		}
+	}
+	for i, z := range c.Zones {
+		if z.Zone == "" || z.Multiplier <= 0 || z.MaxDistanceKm < 0 {
+			return fmt.Errorf("rate card %s zones[%d] requires zone name, positive multiplier and non-negative max_distance_km", c.Carrier, i)
+		}
+		if z.MaxDistanceKm == 0 && i != len(c.Zones)-1 {
+			return fmt.Errorf("rate card %s zone %s without max_distance_km must be the last zone", c.Carrier, z.Zone)
+		}
+		if i > 0 && z.MaxDistanceKm != 0 && z.MaxDistanceKm <= c.Zones[i-1].MaxDistanceKm {
+			return fmt.Errorf("rate card %s zones must be sorted by max_distance_km", c.Carrier)
+		}
+	}
+	return nil
+}
+
+// Zone 按距离查找分区（未配置分区、距离未知或超出最后一个分区时返回 nil）
+func (c *RateCard) Zone(distanceKm *float64) *DistanceZone {
+	if distanceKm == nil {
+		return nil
+	}
+	for i := range c.Zones {
+		if c.Zones[i].MaxDistanceKm == 0 || *distanceKm <= c.Zones[i].MaxDistanceKm {
+			return &c.Zones[i]
+		}
+	}
+	return nil
+}
+
+// Availability 评估各服务对货件的可用性
+func (c *RateCard) Availability(shipment *model.Shipment) []ServiceAvailability {
+	weight := shipment.TotalWeightKg()
+	destination := ""
+	if shipment.ShipTo != nil {
+		destination = strings.ToUpper(strings.TrimSpace(shipment.ShipTo.Country))
+	}
+
+	result := make([]ServiceAvailability, 0, len(c.Services))
+	for _, s := range c.Services {
+		availability := ServiceAvailability{Service: s.Service, Available: true}
+		switch {
+		case s.MaxWeightKg > 0 && weight > s.MaxWeightKg:
+			availability.Available = false
+			availability.Reason = fmt.Sprintf("weight %.2f kg exceeds limit %.2f kg", weight, s.MaxWeightKg)
+		case len(s.Countries) > 0 && !containsFold(s.Countries, destination):
+			availability.Available = false
+			availability.Reason = fmt.Sprintf("destination %s not served", destination)
+		}
+		result = append(result, availability)
+	}
+	return result
+}
+
+// Quote 计算可用服务的报价（distanceKm 非空且费率卡配置了距离分区时，运费乘以分区系数）
+func (c *RateCard) Quote(shipment *model.Shipment, distanceKm *float64) []Quote {
+	weight := shipment.TotalWeightKg()
+	availability := c.Availability(shipment)
+	zone := c.Zone(distanceKm)
+
+	quotes := make([]Quote, 0, len(c.Services))
+	for i, s := range c.Services {
+		if !availability[i].Available {
+			continue
+		}
+		quote := Quote{
+			Carrier:     c.Carrier,
+			Service:     s.Service,
+			Amount:      math.Round((s.BaseRate+s.PerKg*weight)*100) / 100,
+			Currency:    c.Currency,
+			TransitDays: s.TransitDays,
+		}
+		if zone != nil {
+			quote.Amount = math.Round((s.BaseRate+s.PerKg*weight)*zone.Multiplier*100) / 100
+			quote.Zone = zone.Zone
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes
+}
+
+// traceRow 报价对应的费率卡行（计价依据）
+func (c *RateCard) traceRow(quote Quote, weightKg float64) model.TraceRateRow {
+	row := model.TraceRateRow{
+		Carrier:  quote.Carrier,
+		Service:  quote.Service,
+		Source:   c.Version,
+		WeightKg: weightKg,
+		Zone:     quote.Zone,
+		Amount:   quote.Amount,
+		Currency: quote.Currency,
+	}
+	for _, s := range c.Services {
+		if s.Service == quote.Service {
+			row.BaseRate = s.BaseRate
+			row.PerKg = s.PerKg
+			break
+		}
+	}
+	for _, z := range c.Zones {
+		if z.Zone == quote.Zone {
+			row.ZoneMultiplier = z.Multiplier
+			break
+		}
+	}
+	return row
+}
+
+// containsFold 大小写不敏感的包含判断
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
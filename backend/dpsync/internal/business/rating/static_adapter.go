@@ -0,0 +1,44 @@
+package rating
+
+import (
+	"context"
+
+	"oip/dpsync/internal/business/explain"
+)
+
+// StaticAdapter 基于本地费率卡的承运商适配器（离线报价 / 接口降级）
+type StaticAdapter struct {
+	card *RateCard
+}
+
+// NewStaticAdapter 创建静态适配器
+func NewStaticAdapter(card *RateCard) *StaticAdapter {
+	return &StaticAdapter{card: card}
+}
+
+// Carrier 承运商名称
+func (a *StaticAdapter) Carrier() string {
+	return a.card.Carrier
+}
+
+// Version 费率卡版本
+func (a *StaticAdapter) Version() string {
+	return a.card.Version
+}
+
+// Quote 查询报价（explain 模式下记录命中的费率卡行）
+func (a *StaticAdapter) Quote(ctx context.Context, req *QuoteRequest) ([]Quote, error) {
+	quotes := a.card.Quote(req.Shipment, req.DistanceKm)
+	if rec := explain.FromContext(ctx); rec.Enabled() {
+		weight := req.Shipment.TotalWeightKg()
+		for _, quote := range quotes {
+			rec.RateRow(a.card.traceRow(quote, weight))
+		}
+	}
+	return quotes, nil
+}
+
+// Availability 查询服务可用性
+func (a *StaticAdapter) Availability(ctx context.Context, req *QuoteRequest) ([]ServiceAvailability, error) {
+	return a.card.Availability(req.Shipment), nil
+}
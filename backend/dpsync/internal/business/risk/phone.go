@@ -0,0 +1,104 @@
+package risk
+
+import (
+	"strings"
+	"unicode"
+)
+
+// callingCodes 国际电话区号 → 使用该区号的国家（ISO 两位代码）
+// 北美编号计划（+1）覆盖美国、加拿大及加勒比地区，区号本身无法区分，均视为一致
+var callingCodes = map[string][]string{
+	"1":   {"US", "CA", "PR", "BS", "BB", "JM", "TT", "DO", "BM", "KY", "VI", "GU", "AG", "DM", "GD", "KN", "LC", "VC", "AI", "MS", "TC", "VG", "SX", "MP", "AS"},
+	"7":   {"RU", "KZ"},
+	"20":  {"EG"},
+	"27":  {"ZA"},
+	"30":  {"GR"},
+	"31":  {"NL"},
+	"32":  {"BE"},
+	"33":  {"FR"},
+	"34":  {"ES"},
+	"36":  {"HU"},
+	"39":  {"IT", "VA"},
+	"40":  {"RO"},
+	"41":  {"CH"},
+	"43":  {"AT"},
+	"44":  {"GB", "GG", "JE", "IM"},
+	"45":  {"DK"},
+	"46":  {"SE"},
+	"47":  {"NO"},
+	"48":  {"PL"},
+	"49":  {"DE"},
+	"51":  {"PE"},
+	"52":  {"MX"},
+	"54":  {"AR"},
+	"55":  {"BR"},
+	"56":  {"CL"},
+	"57":  {"CO"},
+	"58":  {"VE"},
+	"60":  {"MY"},
+	"61":  {"AU"},
+	"62":  {"ID"},
+	"63":  {"PH"},
+	"64":  {"NZ"},
+	"65":  {"SG"},
+	"66":  {"TH"},
+	"81":  {"JP"},
+	"82":  {"KR"},
+	"84":  {"VN"},
+	"86":  {"CN"},
+	"90":  {"TR"},
+	"91":  {"IN"},
+	"92":  {"PK"},
+	"98":  {"IR"},
+	"212": {"MA"},
+	"234": {"NG"},
+	"254": {"KE"},
+	"351": {"PT"},
+	"352": {"LU"},
+	"353": {"IE"},
+	"358": {"FI"},
+	"380": {"UA"},
+	"420": {"CZ"},
+	"852": {"HK"},
+	"853": {"MO"},
+	"880": {"BD"},
+	"886": {"TW"},
+	"966": {"SA"},
+	"971": {"AE"},
+	"972": {"IL"},
+	"974": {"QA"},
+}
+
+// PhoneCountries 解析国际格式电话（+ 或 00 开头）的区号，返回区号及对应国家
+// 本地格式或区号未登记时 ok=false（无法判断，不视为不一致）
+func PhoneCountries(phone string) (code string, countries []string, ok bool) {
+	phone = strings.TrimSpace(phone)
+	switch {
+	case strings.HasPrefix(phone, "+"):
+		phone = phone[1:]
+	case strings.HasPrefix(phone, "00"):
+		phone = phone[2:]
+	default:
+		return "", nil, false
+	}
+
+	digits := make([]rune, 0, 3)
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+			if len(digits) == 3 {
+				break
+			}
+		} else if r != ' ' && r != '-' && r != '(' && r != ')' && r != '.' {
+			break
+		}
+	}
+
+	// 区号为前缀码（任何区号都不是另一个区号的前缀），按最长匹配查找
+	for n := len(digits); n > 0; n-- {
+		if countries, found := callingCodes[string(digits[:n])]; found {
+			return string(digits[:n]), countries, true
+		}
+	}
+	return "", nil, false
+}
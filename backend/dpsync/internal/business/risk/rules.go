@@ -0,0 +1,182 @@
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// Rules 订单风险评分规则：各信号权重、风险等级阈值与货代地址/一次性邮箱等参考名单
+type Rules struct {
+	Version            string             `json:"version"`              // 数据版本（写入诊断结果用于审计）
+	Weights            map[string]int     `json:"weights"`              // key: 风险信号（model.RiskSignal*），value: 命中时计入的分数（0 表示停用）
+	MediumScore        int                `json:"medium_score"`         // 风险分达到该值为 MEDIUM
+	HighScore          int                `json:"high_score"`           // 风险分达到该值为 HIGH
+	HighValueThreshold float64            `json:"high_value_threshold"` // 高货值阈值（以汇率表基准货币计）
+	NewAccountDays     int                `json:"new_account_days"`     // 注册不超过该天数的账号视为新账号
+	RecipientOrders    int                `json:"recipient_orders"`     // 重复订单检测窗口内寄往同一收件人的其他订单达到该数量时命中
+	ForwarderKeywords  []string           `json:"forwarder_keywords"`   // 货代/转运公司名称或地址关键词（按词元匹配）
+	ForwarderAddresses []ForwarderAddress `json:"forwarder_addresses"`  // 已知货代仓库地址
+	DisposableDomains  []string           `json:"disposable_domains"`   // 一次性邮箱域名（子域名同样命中）
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// ForwarderAddress 已知货代仓库地址（国家 + 邮编必填，街道为空时整个邮编区域均视为命中）
+type ForwarderAddress struct {
+	Name       string `json:"name"`
+	Country    string `json:"country"`
+	PostalCode string `json:"postal_code"`
+	Street     string `json:"street,omitempty"` // 街道前缀（忽略大小写与标点）
+}
+
+// signals 支持的风险信号
+var signals = []string{
+	model.RiskSignalForwarderHighValue,
+	model.RiskSignalPhoneCountryMismatch,
+	model.RiskSignalDisposableEmail,
+	model.RiskSignalNewAccountFirstOrder,
+	model.RiskSignalRecipientVelocity,
+}
+
+// DefaultRules 内置风险规则（未配置数据文件时使用）
+func DefaultRules() *Rules {
+	return &Rules{
+		Version: "builtin-2025-12",
+		Weights: map[string]int{
+			model.RiskSignalForwarderHighValue:   35,
+			model.RiskSignalPhoneCountryMismatch: 15,
+			model.RiskSignalDisposableEmail:      20,
+			model.RiskSignalNewAccountFirstOrder: 15,
+			model.RiskSignalRecipientVelocity:    30,
+		},
+		MediumScore:        30,
+		HighScore:          60,
+		HighValueThreshold: 500,
+		NewAccountDays:     7,
+		RecipientOrders:    3,
+		ForwarderKeywords: []string{
+			"forwarder", "forwarding", "freight forwarder", "package forwarding", "parcel forwarding",
+			"reship", "reshipper", "reshipping", "shipito", "myus", "planet express", "stackry", "shipmonk",
+		},
+		ForwarderAddresses: []ForwarderAddress{
+			{Name: "Delaware tax-free forwarder cluster", Country: "US", PostalCode: "19720"},
+			{Name: "Oregon tax-free forwarder cluster", Country: "US", PostalCode: "97230"},
+		},
+		DisposableDomains: []string{
+			"mailinator.com", "guerrillamail.com", "10minutemail.com", "tempmail.com", "temp-mail.org",
+			"yopmail.com", "trashmail.com", "sharklasers.com", "getnada.com", "dispostable.com",
+		},
+	}
+}
+
+// LoadRules 从 JSON 文件加载风险规则
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read risk rules failed: %w", err)
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("unmarshal risk rules failed: %w", err)
+	}
+
+	if err := rules.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+// Normalize 校验并归一化风险规则（信号必须已知，阈值递增，名单统一小写/大写）
+func (r *Rules) Normalize() error {
+	if r.Version == "" {
+		return fmt.Errorf("risk rules version is required")
+	}
+
+	known := make(map[string]bool, len(signals))
+	for _, signal := range signals {
+		known[signal] = true
+	}
+	weights := make(map[string]int, len(r.Weights))
+	for signal, weight := range r.Weights {
+		signal = strings.ToUpper(strings.TrimSpace(signal))
+		if !known[signal] {
+			return fmt.Errorf("unknown risk signal: %s (available: %v)", signal, signals)
+		}
+		if weight < 0 || weight > 100 {
+			return fmt.Errorf("weight of %s must be between 0 and 100", signal)
+		}
+		weights[signal] = weight
+	}
+	r.Weights = weights
+
+	if r.MediumScore <= 0 || r.HighScore <= r.MediumScore || r.HighScore > 100 {
+		return fmt.Errorf("risk level thresholds must satisfy 0 < medium_score < high_score <= 100")
+	}
+	if r.HighValueThreshold < 0 || r.NewAccountDays < 0 || r.RecipientOrders < 0 {
+		return fmt.Errorf("high_value_threshold, new_account_days and recipient_orders cannot be negative")
+	}
+
+	keywords := make([]string, 0, len(r.ForwarderKeywords))
+	for _, keyword := range r.ForwarderKeywords {
+		if normalized := normalizeText(keyword); normalized != "" {
+			keywords = append(keywords, normalized)
+		}
+	}
+	r.ForwarderKeywords = keywords
+
+	for i := range r.ForwarderAddresses {
+		address := &r.ForwarderAddresses[i]
+		address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+		address.PostalCode = strings.ToUpper(strings.TrimSpace(address.PostalCode))
+		if address.Country == "" || address.PostalCode == "" {
+			return fmt.Errorf("forwarder_addresses[%d]: country and postal_code are required", i)
+		}
+	}
+
+	domains := make([]string, 0, len(r.DisposableDomains))
+	for _, domain := range r.DisposableDomains {
+		if domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), ".")); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	sort.Strings(domains)
+	r.DisposableDomains = domains
+
+	if r.UpdatedAt.IsZero() {
+		r.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝
+func (r *Rules) clone() *Rules {
+	copied := *r
+	copied.Weights = make(map[string]int, len(r.Weights))
+	for signal, weight := range r.Weights {
+		copied.Weights[signal] = weight
+	}
+	copied.ForwarderKeywords = append([]string(nil), r.ForwarderKeywords...)
+	copied.ForwarderAddresses = append([]ForwarderAddress(nil), r.ForwarderAddresses...)
+	copied.DisposableDomains = append([]string(nil), r.DisposableDomains...)
+	return &copied
+}
+
+// Level 风险分对应的风险等级
+func (r *Rules) Level(score int) string {
+	switch {
+	case score >= r.HighScore:
+		return model.RiskLevelHigh
+	case score >= r.MediumScore:
+		return model.RiskLevelMedium
+	default:
+		return model.RiskLevelLow
+	}
+}
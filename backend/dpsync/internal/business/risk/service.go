@@ -0,0 +1,53 @@
+package risk
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Service 风险规则服务（并发安全，支持整体替换规则）
+type Service struct {
+	mu    sync.RWMutex
+	rules *Rules
+}
+
+// NewService 创建风险规则服务
+func NewService(rules *Rules) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(rules); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前规则
+func (s *Service) Update(rules *Rules) error {
+	if rules == nil {
+		return fmt.Errorf("risk rules cannot be nil")
+	}
+
+	normalized := rules.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.rules = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Rules 返回当前规则副本（单次评分内使用同一份快照，避免评分过程中规则被替换）
+func (s *Service) Rules() *Rules {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules.clone()
+}
+
+// Version 规则版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules.Version
+}
@@ -0,0 +1,59 @@
+package risk
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Forwarder 判断收件地址是否为货代/转运地址，命中时返回原因
+// 已知仓库地址按国家 + 邮编（+ 街道前缀）匹配；公司名与街道按关键词词元匹配
+func (r *Rules) Forwarder(country, postalCode, companyName, street1, street2 string) (string, bool) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	postalCode = strings.ToUpper(strings.TrimSpace(postalCode))
+	street := normalizeText(street1 + " " + street2)
+
+	for _, address := range r.ForwarderAddresses {
+		if address.Country != country || !strings.HasPrefix(postalCode, address.PostalCode) {
+			continue
+		}
+		if address.Street != "" && !strings.HasPrefix(street, normalizeText(address.Street)) {
+			continue
+		}
+		return "known forwarder address " + address.Name, true
+	}
+
+	padded := " " + normalizeText(companyName+" "+street1+" "+street2) + " "
+	for _, keyword := range r.ForwarderKeywords {
+		if strings.Contains(padded, " "+keyword+" ") {
+			return "forwarder keyword \"" + keyword + "\"", true
+		}
+	}
+
+	return "", false
+}
+
+// DisposableEmail 判断邮箱是否为一次性邮箱域名（子域名同样命中），命中时返回域名
+func (r *Rules) DisposableEmail(email string) (string, bool) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return "", false
+	}
+	domain := strings.ToLower(strings.Trim(strings.TrimSpace(email[at+1:]), "."))
+	if domain == "" {
+		return "", false
+	}
+
+	for _, disposable := range r.DisposableDomains {
+		if domain == disposable || strings.HasSuffix(domain, "."+disposable) {
+			return domain, true
+		}
+	}
+	return "", false
+}
+
+// normalizeText 转小写，非字母数字字符视为分隔符，多个空白合并为一个
+func normalizeText(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
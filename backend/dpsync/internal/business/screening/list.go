@@ -0,0 +1,235 @@
+package screening
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 名单主体类型
+const (
+	EntryTypeIndividual = "INDIVIDUAL"
+	EntryTypeEntity     = "ENTITY"
+)
+
+// List 拒绝往来方名单（Denied-Party List）
+type List struct {
+	Version   string    `json:"version"` // 数据版本（写入诊断结果用于审计）
+	Entries   []Entry   `json:"entries"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Entry 名单条目
+type Entry struct {
+	ID      string   `json:"id"`                // 条目 ID（来源名单中的编号）
+	Name    string   `json:"name"`              // 主体名称
+	Aliases []string `json:"aliases,omitempty"` // 别名
+	Type    string   `json:"type"`              // INDIVIDUAL/ENTITY
+	Country string   `json:"country,omitempty"` // 所在国（ISO 两位代码）
+	Address string   `json:"address,omitempty"` // 地址
+	Program string   `json:"program,omitempty"` // 制裁项目（如 SDN）
+	Source  string   `json:"source,omitempty"`  // 来源名单
+
+	names   []normalizedName // 主体名称与别名的归一化结果
+	address []string         // 地址归一化词元
+}
+
+// csvHeader CSV 名单的列（别名以分号分隔）
+var csvHeader = []string{"id", "name", "aliases", "type", "country", "address", "program", "source"}
+
+// DefaultList 内置示例名单（仅含虚构主体，用于本地调试；生产环境须通过 screening_file 加载正式名单）
+func DefaultList() *List {
+	return &List{
+		Version: "builtin-sample",
+		Entries: []Entry{
+			{ID: "SAMPLE-0001", Name: "Blackwater Maritime Trading LLC", Aliases: []string{"Black Water Maritime"}, Type: EntryTypeEntity, Country: "AE", Address: "Office 1204, Harbour Tower, Dubai", Program: "SAMPLE", Source: "builtin"},
+			{ID: "SAMPLE-0002", Name: "Ivan Petrovich Sidorov", Aliases: []string{"Ivan Sidorov"}, Type: EntryTypeIndividual, Country: "RU", Program: "SAMPLE", Source: "builtin"},
+			{ID: "SAMPLE-0003", Name: "Golden Crescent Electronics Co Ltd", Type: EntryTypeEntity, Country: "HK", Address: "88 Nathan Road, Kowloon", Program: "SAMPLE", Source: "builtin"},
+			{ID: "SAMPLE-0004", Name: "Northern Star Precision Machinery", Aliases: []string{"NSPM"}, Type: EntryTypeEntity, Country: "IR", Program: "SAMPLE", Source: "builtin"},
+			{ID: "SAMPLE-0005", Name: "Maria Elena Vasquez", Type: EntryTypeIndividual, Country: "VE", Program: "SAMPLE", Source: "builtin"},
+		},
+	}
+}
+
+// LoadList 从文件加载名单（按扩展名识别 .csv 或 .json）
+func LoadList(path string) (*List, error) {
+	var list *List
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		list, err = loadCSV(path)
+	case ".json":
+		list, err = loadJSON(path)
+	default:
+		return nil, fmt.Errorf("unsupported denied-party list format: %s (expected .csv or .json)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := list.Normalize(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// loadJSON 加载 JSON 名单
+func loadJSON(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read denied-party list failed: %w", err)
+	}
+
+	var list List
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("unmarshal denied-party list failed: %w", err)
+	}
+	return &list, nil
+}
+
+// loadCSV 加载 CSV 名单（首行为表头；版本取文件修改时间）
+func loadCSV(path string) (*List, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read denied-party list failed: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("read denied-party list failed: %w", err)
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read denied-party list header failed: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"id", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("denied-party list missing column %q (columns: %s)", required, strings.Join(csvHeader, ","))
+		}
+	}
+
+	list := &List{
+		Version:   "csv-" + info.ModTime().UTC().Format("20060102T150405Z"),
+		Entries:   make([]Entry, 0),
+		UpdatedAt: info.ModTime(),
+	}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read denied-party list line %d failed: %w", line, err)
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		entry := Entry{
+			ID:      field("id"),
+			Name:    field("name"),
+			Type:    field("type"),
+			Country: field("country"),
+			Address: field("address"),
+			Program: field("program"),
+			Source:  field("source"),
+		}
+		for _, alias := range strings.Split(field("aliases"), ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		list.Entries = append(list.Entries, entry)
+	}
+
+	return list, nil
+}
+
+// Normalize 校验名单并预计算归一化名称（ID 唯一，名称必填）
+func (l *List) Normalize() error {
+	if l.Version == "" {
+		return fmt.Errorf("denied-party list version is required")
+	}
+
+	seen := make(map[string]bool, len(l.Entries))
+	for i := range l.Entries {
+		entry := &l.Entries[i]
+		entry.ID = strings.TrimSpace(entry.ID)
+		if entry.ID == "" {
+			return fmt.Errorf("entries[%d]: id is required", i)
+		}
+		if seen[entry.ID] {
+			return fmt.Errorf("duplicate entry id: %s", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		if strings.TrimSpace(entry.Name) == "" {
+			return fmt.Errorf("entry %s: name is required", entry.ID)
+		}
+		entry.Type = strings.ToUpper(strings.TrimSpace(entry.Type))
+		if entry.Type == "" {
+			entry.Type = EntryTypeEntity
+		}
+		if entry.Type != EntryTypeIndividual && entry.Type != EntryTypeEntity {
+			return fmt.Errorf("entry %s: invalid type %q", entry.ID, entry.Type)
+		}
+		entry.Country = strings.ToUpper(strings.TrimSpace(entry.Country))
+
+		entry.names = make([]normalizedName, 0, 1+len(entry.Aliases))
+		for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+			if normalized := normalizeName(name); len(normalized.tokens) > 0 {
+				entry.names = append(entry.names, normalized)
+			}
+		}
+		entry.address = tokenize(entry.Address)
+	}
+
+	if l.UpdatedAt.IsZero() {
+		l.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝（对外返回副本，避免调用方修改内部状态）
+func (l *List) clone() *List {
+	entries := make([]Entry, len(l.Entries))
+	for i, entry := range l.Entries {
+		entries[i] = Entry{
+			ID:      entry.ID,
+			Name:    entry.Name,
+			Aliases: append([]string(nil), entry.Aliases...),
+			Type:    entry.Type,
+			Country: entry.Country,
+			Address: entry.Address,
+			Program: entry.Program,
+			Source:  entry.Source,
+		}
+	}
+
+	return &List{
+		Version:   l.Version,
+		Entries:   entries,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
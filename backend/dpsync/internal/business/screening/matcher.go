@@ -0,0 +1,210 @@
+package screening
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// noiseTokens 比对时忽略的公司后缀与虚词（不同名单/订单写法差异最大的部分）
+var noiseTokens = map[string]bool{
+	"the": true, "and": true, "of": true,
+	"co": true, "company": true, "corp": true, "corporation": true,
+	"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true,
+	"plc": true, "gmbh": true, "ag": true, "sa": true, "srl": true, "bv": true,
+	"pte": true, "jsc": true, "ooo": true, "pjsc": true, "lp": true, "llp": true,
+}
+
+// normalizedName 归一化名称：去变音符号、转小写、去标点与公司后缀，词元去重排序
+type normalizedName struct {
+	raw    string   // 原始名称
+	tokens []string // 排序去重后的词元
+	text   string   // 词元以空格拼接（词序无关的比对文本）
+}
+
+// similarity 名称相似度
+type similarity struct {
+	tokenSet    float64 // 词元集合相似度（对词序与多余词元不敏感）
+	jaroWinkler float64 // Jaro-Winkler 相似度（对拼写差异敏感）
+	score       float64 // 综合得分（两者平均）
+}
+
+// normalizeName 归一化名称
+func normalizeName(name string) normalizedName {
+	tokens := make([]string, 0)
+	for _, token := range tokenize(name) {
+		if !noiseTokens[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	return normalizedName{raw: name, tokens: tokens, text: strings.Join(tokens, " ")}
+}
+
+// tokenize 文本切分为词元：NFD 分解后去掉变音符号，非字母数字字符视为分隔符，结果去重排序
+func tokenize(text string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+	for _, token := range strings.Fields(b.String()) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// compare 计算两个归一化名称的相似度
+func compare(a, b normalizedName) similarity {
+	if len(a.tokens) == 0 || len(b.tokens) == 0 {
+		return similarity{}
+	}
+	s := similarity{
+		tokenSet:    tokenSetRatio(a.tokens, b.tokens),
+		jaroWinkler: jaroWinkler(a.text, b.text),
+	}
+	s.score = (s.tokenSet + s.jaroWinkler) / 2
+	return s
+}
+
+// tokenSetRatio 词元集合相似度
+// 以交集为公共前缀，分别拼接两侧独有词元，取三种组合编辑距离相似度的最大值
+func tokenSetRatio(a, b []string) float64 {
+	inB := make(map[string]bool, len(b))
+	for _, token := range b {
+		inB[token] = true
+	}
+	inA := make(map[string]bool, len(a))
+	for _, token := range a {
+		inA[token] = true
+	}
+
+	common := make([]string, 0)
+	onlyA := make([]string, 0)
+	for _, token := range a {
+		if inB[token] {
+			common = append(common, token)
+		} else {
+			onlyA = append(onlyA, token)
+		}
+	}
+	onlyB := make([]string, 0)
+	for _, token := range b {
+		if !inA[token] {
+			onlyB = append(onlyB, token)
+		}
+	}
+
+	t0 := strings.Join(common, " ")
+	t1 := strings.TrimSpace(t0 + " " + strings.Join(onlyA, " "))
+	t2 := strings.TrimSpace(t0 + " " + strings.Join(onlyB, " "))
+
+	best := levenshteinRatio(t1, t2)
+	if t0 != "" {
+		if r := levenshteinRatio(t0, t1); r > best {
+			best = r
+		}
+		if r := levenshteinRatio(t0, t2); r > best {
+			best = r
+		}
+	}
+	return best
+}
+
+// levenshteinRatio 编辑距离相似度：1 - 距离 / 较长串长度
+func levenshteinRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// jaroWinkler Jaro-Winkler 相似度（公共前缀最多计 4 个字符，缩放系数 0.1）
+func jaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		if len(ra) == len(rb) {
+			return 1
+		}
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb)-1, i+window)
+		for j := lo; j <= hi; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,103 @@
+package screening
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+const epsilon = 1e-4
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "martha", b: "marhta", want: 0.9611},
+		{a: "dwayne", b: "duane", want: 0.8400},
+		{a: "dixon", b: "dicksonx", want: 0.8133},
+		{a: "acme", b: "acme", want: 1},
+		{a: "abc", b: "xyz", want: 0},
+		{a: "", b: "", want: 1},
+		{a: "abc", b: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			got := jaroWinkler(tt.a, tt.b)
+			if math.Abs(got-tt.want) > epsilon {
+				t.Errorf("jaroWinkler(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+			}
+			if reverse := jaroWinkler(tt.b, tt.a); math.Abs(reverse-got) > epsilon {
+				t.Errorf("jaroWinkler is not symmetric: %.4f vs %.4f", got, reverse)
+			}
+		})
+	}
+}
+
+func TestTokenSetRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{name: "identical", a: []string{"acme", "trading"}, b: []string{"acme", "trading"}, want: 1},
+		{name: "subset", a: []string{"acme", "trading"}, b: []string{"acme"}, want: 1},
+		{name: "one typo", a: []string{"john", "smith"}, b: []string{"jon", "smith"}, want: 0.9},
+		{name: "disjoint", a: []string{"abc"}, b: []string{"xyz"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenSetRatio(tt.a, tt.b)
+			if math.Abs(got-tt.want) > epsilon {
+				t.Errorf("tokenSetRatio(%v, %v) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "Société Générale SA", want: []string{"generale", "societe"}},
+		{name: "ACME Trading Co., Ltd.", want: []string{"acme", "trading"}},
+		{name: "Trading ACME trading", want: []string{"acme", "trading"}},
+		{name: "The Company Inc", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeName(tt.name).tokens
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeName(%q).tokens = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		minScore float64
+		maxScore float64
+	}{
+		{name: "suffix and word order ignored", a: "ACME Trading Ltd", b: "Trading ACME", minScore: 1, maxScore: 1},
+		{name: "diacritics ignored", a: "Société Générale", b: "Societe Generale SA", minScore: 1, maxScore: 1},
+		{name: "small misspelling scores high", a: "Rosoboronexport", b: "Rosoboronexpert", minScore: 0.9, maxScore: 0.99},
+		{name: "unrelated names score low", a: "Blue Ocean Logistics", b: "Zhang Wei", minScore: 0, maxScore: 0.5},
+		{name: "noise-only name never matches", a: "The Company Inc", b: "The Company Inc", minScore: 0, maxScore: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compare(normalizeName(tt.a), normalizeName(tt.b)).score
+			if got < tt.minScore-epsilon || got > tt.maxScore+epsilon {
+				t.Errorf("compare(%q, %q) score = %.4f, want within [%.2f, %.2f]", tt.a, tt.b, got, tt.minScore, tt.maxScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package screening
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"oip/common/model"
+)
+
+// 匹配阈值
+const (
+	// reviewScore 综合得分达到该值时报告为疑似命中（REVIEW）
+	reviewScore = 0.85
+	// blockScore 综合得分达到该值且国家一致时升级为 BLOCK
+	blockScore = 0.95
+	// addressScore 地址词元集合相似度达到该值时视为地址一致
+	addressScore = 0.85
+)
+
+// Query 待筛查的收件方字段
+type Query struct {
+	Field string // contact_name/company_name
+	Value string
+}
+
+// Party 待筛查的收件方
+type Party struct {
+	Names   []Query // 姓名与公司名
+	Address string  // 街道、城市、邮编拼接的地址
+	Country string  // ISO 两位国家代码
+}
+
+// Service 拒绝往来方筛查服务（并发安全，支持通过管理接口替换或从文件重新加载名单）
+type Service struct {
+	mu   sync.RWMutex
+	list *List
+	path string // 名单文件路径（为空表示使用内置名单，不支持重新加载）
+}
+
+// NewService 创建筛查服务，path 为名单文件路径（用于 Reload，可为空）
+func NewService(list *List, path string) (*Service, error) {
+	s := &Service{path: path}
+	if err := s.Update(list); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前名单
+func (s *Service) Update(list *List) error {
+	if list == nil {
+		return fmt.Errorf("denied-party list cannot be nil")
+	}
+
+	normalized := list.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.list = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Reload 从名单文件重新加载（名单供应商定期更新文件后调用）
+func (s *Service) Reload() (*List, error) {
+	if s.path == "" {
+		return nil, fmt.Errorf("no denied-party list file configured")
+	}
+
+	list, err := LoadList(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.list = list
+	s.mu.Unlock()
+
+	return list.clone(), nil
+}
+
+// List 返回当前名单副本
+func (s *Service) List() *List {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.clone()
+}
+
+// Version 名单版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.Version
+}
+
+// Screen 筛查收件方，返回命中的名单条目（每个条目只保留得分最高的字段，按得分降序）
+func (s *Service) Screen(party Party) []model.ScreeningMatch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queries := make([]normalizedName, len(party.Names))
+	for i, query := range party.Names {
+		queries[i] = normalizeName(query.Value)
+	}
+	address := tokenize(party.Address)
+	country := strings.ToUpper(strings.TrimSpace(party.Country))
+
+	matches := make([]model.ScreeningMatch, 0)
+	for i := range s.list.Entries {
+		entry := &s.list.Entries[i]
+		countryMatch := entry.Country == "" || entry.Country == country
+		addressMatch := len(entry.address) > 0 && len(address) > 0 && tokenSetRatio(address, entry.address) >= addressScore
+
+		var best *model.ScreeningMatch
+		for q, query := range queries {
+			for _, name := range entry.names {
+				sim := compare(query, name)
+				if sim.score < reviewScore || (best != nil && sim.score <= best.Score) {
+					continue
+				}
+				best = &model.ScreeningMatch{
+					EntryID:          entry.ID,
+					EntryName:        entry.Name,
+					MatchedName:      name.raw,
+					EntryType:        entry.Type,
+					Program:          entry.Program,
+					Source:           entry.Source,
+					Field:            party.Names[q].Field,
+					Query:            party.Names[q].Value,
+					Score:            round3(sim.score),
+					TokenSetScore:    round3(sim.tokenSet),
+					JaroWinklerScore: round3(sim.jaroWinkler),
+				}
+			}
+		}
+
+		// 名称未命中但地址与名单条目一致（同国）时仍需复核
+		if best == nil && addressMatch && entry.Country == country {
+			best = &model.ScreeningMatch{
+				EntryID:     entry.ID,
+				EntryName:   entry.Name,
+				MatchedName: entry.Address,
+				EntryType:   entry.Type,
+				Program:     entry.Program,
+				Source:      entry.Source,
+				Field:       "address",
+				Query:       party.Address,
+				Score:       round3(tokenSetRatio(address, entry.address)),
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		best.CountryMatch = countryMatch
+		best.AddressMatch = addressMatch
+		best.ReviewLevel = model.ScreeningLevelReview
+		if best.Field != "address" && ((best.Score >= blockScore && countryMatch) || addressMatch) {
+			best.ReviewLevel = model.ScreeningLevelBlock
+		}
+		matches = append(matches, *best)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// round3 保留三位小数
+func round3(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,130 @@
+package shadow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis 键前缀：最近结果为 List（新的在前），累计统计为 Hash
+const (
+	redisResultsPrefix = "oip:shadow:results:"
+	redisStatsPrefix   = "oip:shadow:stats:"
+	redisFieldPrefix   = "field:" // 统计 Hash 中各结果字段不一致次数的字段前缀
+)
+
+// RedisStore 基于 Redis 的影子诊断结果存储（多 Worker 实例共享，统计为全部实例的汇总）
+type RedisStore struct {
+	client      *redis.Client
+	historySize int
+}
+
+// NewRedisStore 创建 Redis 存储，historySize<=0 时使用默认值
+func NewRedisStore(addr, password string, db int, historySize int) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	return &RedisStore{client: client, historySize: historySize}, nil
+}
+
+// Save 保存结果并累加统计（同一事务内完成）
+func (r *RedisStore) Save(ctx context.Context, result *Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal shadow result failed: %w", err)
+	}
+
+	resultsKey := redisResultsPrefix + result.Name
+	statsKey := redisStatsPrefix + result.Name
+
+	pipe := r.client.TxPipeline()
+	pipe.LPush(ctx, resultsKey, data)
+	pipe.LTrim(ctx, resultsKey, 0, int64(r.historySize-1))
+	pipe.HIncrBy(ctx, statsKey, "total", 1)
+	switch result.Outcome {
+	case OutcomeAgreed:
+		pipe.HIncrBy(ctx, statsKey, "agreed", 1)
+	case OutcomeDisagreed:
+		pipe.HIncrBy(ctx, statsKey, "disagreed", 1)
+		for _, field := range result.Differences {
+			pipe.HIncrBy(ctx, statsKey, redisFieldPrefix+field, 1)
+		}
+	default:
+		pipe.HIncrBy(ctx, statsKey, "failed", 1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("save shadow result failed: %w", err)
+	}
+
+	return nil
+}
+
+// Recent 返回最近的结果（新的在前，无法解析的条目跳过）
+func (r *RedisStore) Recent(ctx context.Context, name string, limit int) ([]*Result, error) {
+	if limit <= 0 {
+		return []*Result{}, nil
+	}
+
+	values, err := r.client.LRange(ctx, redisResultsPrefix+name, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("read shadow results failed: %w", err)
+	}
+
+	results := make([]*Result, 0, len(values))
+	for _, value := range values {
+		var result Result
+		if err := json.Unmarshal([]byte(value), &result); err != nil {
+			continue
+		}
+		results = append(results, &result)
+	}
+	return results, nil
+}
+
+// Counters 返回累计统计
+func (r *RedisStore) Counters(ctx context.Context, name string) (*Counters, error) {
+	values, err := r.client.HGetAll(ctx, redisStatsPrefix+name).Result()
+	if err != nil {
+		return nil, fmt.Errorf("read shadow stats failed: %w", err)
+	}
+
+	counters := NewCounters()
+	for key, value := range values {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case key == "total":
+			counters.Total = count
+		case key == "agreed":
+			counters.Agreed = count
+		case key == "disagreed":
+			counters.Disagreed = count
+		case key == "failed":
+			counters.Failed = count
+		case strings.HasPrefix(key, redisFieldPrefix):
+			counters.Fields[strings.TrimPrefix(key, redisFieldPrefix)] = count
+		}
+	}
+	return counters, nil
+}
+
+// Close 关闭 Redis 连接
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,113 @@
+package shadow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 影子诊断对比结论
+const (
+	OutcomeAgreed    = "AGREED"    // 与线上诊断结果一致
+	OutcomeDisagreed = "DISAGREED" // 与线上诊断结果不一致（差异字段见 Differences）
+	OutcomeFailed    = "FAILED"    // 影子诊断失败或超时
+)
+
+// Result 一次影子诊断的结果（与同一订单的线上诊断项对比）
+type Result struct {
+	Name          string          `json:"name"` // 影子诊断器名称
+	Type          string          `json:"type"` // 诊断类型
+	OrderID       string          `json:"order_id"`
+	RequestID     string          `json:"request_id"`
+	LiveVersion   string          `json:"live_version,omitempty"`   // 线上诊断项的规则/费率卡版本
+	ShadowVersion string          `json:"shadow_version,omitempty"` // 影子诊断器的规则/费率卡版本
+	Outcome       string          `json:"outcome"`                  // AGREED/DISAGREED/FAILED
+	Differences   []string        `json:"differences,omitempty"`    // 不一致的结果字段（顶层 JSON 字段名）
+	Data          json.RawMessage `json:"data,omitempty"`           // 影子诊断结果（DataJSON）
+	Error         string          `json:"error,omitempty"`
+	DurationMs    int64           `json:"duration_ms"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Counters 影子诊断累计统计
+type Counters struct {
+	Total     int64            `json:"total"`     // 完成对比的次数（AGREED + DISAGREED + FAILED）
+	Agreed    int64            `json:"agreed"`    // 结果一致次数
+	Disagreed int64            `json:"disagreed"` // 结果不一致次数
+	Failed    int64            `json:"failed"`    // 影子诊断失败或超时次数
+	Fields    map[string]int64 `json:"fields"`    // 各结果字段的不一致次数
+}
+
+// NewCounters 创建空的累计统计
+func NewCounters() *Counters {
+	return &Counters{Fields: make(map[string]int64)}
+}
+
+// Add 计入一次对比结果
+func (c *Counters) Add(result *Result) {
+	c.Total++
+	switch result.Outcome {
+	case OutcomeAgreed:
+		c.Agreed++
+	case OutcomeDisagreed:
+		c.Disagreed++
+		for _, field := range result.Differences {
+			c.Fields[field]++
+		}
+	default:
+		c.Failed++
+	}
+}
+
+// AgreementRate 一致率（一致次数 / 成功对比次数，尚无成功对比时为 0）
+func (c *Counters) AgreementRate() float64 {
+	compared := c.Agreed + c.Disagreed
+	if compared == 0 {
+		return 0
+	}
+	return float64(c.Agreed) / float64(compared)
+}
+
+// Compare 比较线上与影子诊断结果，返回不一致的顶层字段（按字段名排序）
+// 版本标识字段（*_version）随候选规则/费率卡必然不同，不计入差异；结果不是 JSON 对象时整体比较，差异记为 "$"
+func Compare(live, shadow json.RawMessage) ([]string, error) {
+	var liveValue, shadowValue interface{}
+	if err := json.Unmarshal(live, &liveValue); err != nil {
+		return nil, fmt.Errorf("unmarshal live result failed: %w", err)
+	}
+	if err := json.Unmarshal(shadow, &shadowValue); err != nil {
+		return nil, fmt.Errorf("unmarshal shadow result failed: %w", err)
+	}
+
+	liveObject, liveOK := liveValue.(map[string]interface{})
+	shadowObject, shadowOK := shadowValue.(map[string]interface{})
+	if !liveOK || !shadowOK {
+		if reflect.DeepEqual(liveValue, shadowValue) {
+			return nil, nil
+		}
+		return []string{"$"}, nil
+	}
+
+	fields := make(map[string]bool, len(liveObject)+len(shadowObject))
+	for field := range liveObject {
+		fields[field] = true
+	}
+	for field := range shadowObject {
+		fields[field] = true
+	}
+
+	differences := make([]string, 0)
+	for field := range fields {
+		if strings.HasSuffix(field, "_version") {
+			continue
+		}
+		if !reflect.DeepEqual(liveObject[field], shadowObject[field]) {
+			differences = append(differences, field)
+		}
+	}
+	sort.Strings(differences)
+	return differences, nil
+}
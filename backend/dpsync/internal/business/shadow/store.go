@@ -0,0 +1,96 @@
+package shadow
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultHistorySize 每个影子诊断器默认保留的最近结果条数
+const defaultHistorySize = 1000
+
+// Store 影子诊断结果存储（与线上诊断结果分开保存，不进入回调）
+type Store interface {
+	// Save 保存一次影子诊断结果并累加统计
+	Save(ctx context.Context, result *Result) error
+
+	// Recent 返回影子诊断器最近的结果（新的在前，最多 limit 条）
+	Recent(ctx context.Context, name string, limit int) ([]*Result, error)
+
+	// Counters 返回影子诊断器的累计统计
+	Counters(ctx context.Context, name string) (*Counters, error)
+}
+
+// MemoryStore 进程内影子诊断结果存储（并发安全，仅适用于单实例部署或本地调试）
+type MemoryStore struct {
+	mu          sync.Mutex
+	historySize int
+	results     map[string][]*Result // 影子诊断器名称 → 最近结果（旧的在前）
+	counters    map[string]*Counters
+}
+
+// NewMemoryStore 创建进程内存储，historySize<=0 时使用默认值
+func NewMemoryStore(historySize int) *MemoryStore {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	return &MemoryStore{
+		historySize: historySize,
+		results:     make(map[string][]*Result),
+		counters:    make(map[string]*Counters),
+	}
+}
+
+// Save 保存结果（超出保留条数时丢弃最旧的结果，统计不受影响）
+func (m *MemoryStore) Save(ctx context.Context, result *Result) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	results := append(m.results[result.Name], result)
+	if len(results) > m.historySize {
+		results = results[len(results)-m.historySize:]
+	}
+	m.results[result.Name] = results
+
+	counters, ok := m.counters[result.Name]
+	if !ok {
+		counters = NewCounters()
+		m.counters[result.Name] = counters
+	}
+	counters.Add(result)
+	return nil
+}
+
+// Recent 返回最近的结果（新的在前）
+func (m *MemoryStore) Recent(ctx context.Context, name string, limit int) ([]*Result, error) {
+	if limit <= 0 {
+		return []*Result{}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	results := m.results[name]
+	recent := make([]*Result, 0, min(limit, len(results)))
+	for i := len(results) - 1; i >= 0 && len(recent) < limit; i-- {
+		recent = append(recent, results[i])
+	}
+	return recent, nil
+}
+
+// Counters 返回累计统计（副本）
+func (m *MemoryStore) Counters(ctx context.Context, name string) (*Counters, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := NewCounters()
+	if counters, ok := m.counters[name]; ok {
+		snapshot.Total = counters.Total
+		snapshot.Agreed = counters.Agreed
+		snapshot.Disagreed = counters.Disagreed
+		snapshot.Failed = counters.Failed
+		for field, count := range counters.Fields {
+			snapshot.Fields[field] = count
+		}
+	}
+	return snapshot, nil
+}
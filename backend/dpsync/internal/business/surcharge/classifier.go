@@ -0,0 +1,118 @@
+package surcharge
+
+import (
+	"strings"
+
+	"oip/common/model"
+)
+
+// Address 附加费判定所需的收件地址信息
+type Address struct {
+	CompanyName string
+	Street1     string
+	Street2     string
+	PostalCode  string
+	Country     string // ISO 两位国家代码
+}
+
+// Classification 地址类型判定结果
+type Classification struct {
+	Type   string `json:"type"`   // RESIDENTIAL/COMMERCIAL/UNKNOWN
+	Reason string `json:"reason"` // 判定依据
+}
+
+// commercialSuffixes 公司名称后缀（出现在街道地址中时视为商业地址）
+var commercialSuffixes = []string{
+	"INC", "LLC", "LTD", "CORP", "CORPORATION", "COMPANY", "GMBH", "SARL", "BV", "PLC", "PTY",
+}
+
+// commercialKeywords 商业地址关键词
+var commercialKeywords = []string{
+	"SUITE", "STE", "FLOOR", "FL", "BLDG", "BUILDING", "WAREHOUSE", "DOCK", "INDUSTRIAL", "PLAZA",
+	"OFFICE", "TOWER", "MALL",
+}
+
+// residentialKeywords 住宅地址关键词
+var residentialKeywords = []string{
+	"APT", "APARTMENT", "HOUSE", "RESIDENCE", "LOT", "TRAILER", "CONDO",
+}
+
+// Classify 判定收件地址为住宅或商业地址
+// 1. 填写了公司名称 → 商业地址
+// 2. PO Box → 住宅地址
+// 3. 街道地址包含公司后缀或商业关键词 → 商业地址
+// 4. 街道地址包含住宅关键词 → 住宅地址
+// 5. 有街道信息但无上述特征 → 住宅地址（与承运商默认口径一致）
+// 6. 无街道信息 → 无法判定
+func Classify(addr *Address) Classification {
+	if addr == nil {
+		return Classification{Type: model.AddressTypeUnknown, Reason: "ship_to is missing"}
+	}
+
+	if strings.TrimSpace(addr.CompanyName) != "" {
+		return Classification{Type: model.AddressTypeCommercial, Reason: "company name present"}
+	}
+
+	tokens := tokenize(addr.Street1 + " " + addr.Street2)
+	if len(tokens) == 0 {
+		return Classification{Type: model.AddressTypeUnknown, Reason: "street address is missing"}
+	}
+
+	if isPOBox(tokens) {
+		return Classification{Type: model.AddressTypeResidential, Reason: "PO Box address"}
+	}
+	if keyword := matchAny(tokens, commercialSuffixes); keyword != "" {
+		return Classification{Type: model.AddressTypeCommercial, Reason: "company suffix in street: " + keyword}
+	}
+	if keyword := matchAny(tokens, commercialKeywords); keyword != "" {
+		return Classification{Type: model.AddressTypeCommercial, Reason: "commercial keyword in street: " + keyword}
+	}
+	if keyword := matchAny(tokens, residentialKeywords); keyword != "" {
+		return Classification{Type: model.AddressTypeResidential, Reason: "residential keyword in street: " + keyword}
+	}
+
+	return Classification{Type: model.AddressTypeResidential, Reason: "no commercial indicators"}
+}
+
+// IsPOBox 收件地址是否为邮政信箱（部分承运商服务不派送 PO Box）
+func IsPOBox(addr *Address) bool {
+	if addr == nil {
+		return false
+	}
+	return isPOBox(tokenize(addr.Street1 + " " + addr.Street2))
+}
+
+// tokenize 地址分词：转大写，非字母数字字符视为分隔符
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9')
+	})
+}
+
+// matchAny 返回第一个命中的关键词
+func matchAny(tokens []string, keywords []string) string {
+	for _, token := range tokens {
+		for _, keyword := range keywords {
+			if token == keyword {
+				return keyword
+			}
+		}
+	}
+	return ""
+}
+
+// isPOBox 是否为邮政信箱地址（PO BOX / P.O. BOX / POBOX）
+func isPOBox(tokens []string) bool {
+	for i, token := range tokens {
+		if token == "POBOX" {
+			return true
+		}
+		if token == "BOX" && i > 0 {
+			prev := tokens[i-1]
+			if prev == "PO" || prev == "O" || prev == "POST" || prev == "OFFICE" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package surcharge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// Dataset 附加费数据集
+// 按承运商维护偏远/扩展派送区域邮编区间及住宅派送附加费
+type Dataset struct {
+	Version   string                       `json:"version"`  // 数据集版本（写入诊断结果用于审计）
+	Currency  string                       `json:"currency"` // 附加费金额币种
+	Carriers  map[string]*CarrierSurcharge `json:"carriers"` // key: 承运商名称（查找时大小写不敏感）
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
+
+// CarrierSurcharge 单个承运商的附加费规则
+type CarrierSurcharge struct {
+	ResidentialFee float64     `json:"residential_fee"` // 住宅派送附加费（0 表示不收取）
+	Areas          []AreaRange `json:"areas"`           // 偏远/扩展区域邮编区间
+}
+
+// AreaRange 邮编区间
+// From/To 为等长的邮编前缀（闭区间），按字典序比较
+type AreaRange struct {
+	Country string  `json:"country"` // ISO 两位国家代码
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Type    string  `json:"type"` // REMOTE_AREA/EXTENDED_AREA
+	Fee     float64 `json:"fee"`
+}
+
+// DefaultDataset 内置附加费数据集（未配置数据文件时使用）
+func DefaultDataset() *Dataset {
+	// 美国偏远地区：阿拉斯加、夏威夷、波多黎各及关岛
+	usRemote := []AreaRange{
+		{Country: "US", From: "995", To: "999", Type: model.SurchargeTypeRemoteArea},
+		{Country: "US", From: "967", To: "968", Type: model.SurchargeTypeRemoteArea},
+		{Country: "US", From: "006", To: "009", Type: model.SurchargeTypeExtendedArea},
+		{Country: "US", From: "969", To: "969", Type: model.SurchargeTypeRemoteArea},
+	}
+
+	withFees := func(areas []AreaRange, remoteFee, extendedFee float64) []AreaRange {
+		out := make([]AreaRange, 0, len(areas))
+		for _, area := range areas {
+			if area.Type == model.SurchargeTypeRemoteArea {
+				area.Fee = remoteFee
+			} else {
+				area.Fee = extendedFee
+			}
+			out = append(out, area)
+		}
+		return out
+	}
+
+	return &Dataset{
+		Version:  "builtin-2025-12",
+		Currency: "USD",
+		Carriers: map[string]*CarrierSurcharge{
+			"FedEx": {
+				ResidentialFee: 5.55,
+				Areas:          withFees(usRemote, 15.50, 4.95),
+			},
+			"UPS": {
+				ResidentialFee: 5.85,
+				Areas:          withFees(usRemote, 16.00, 5.20),
+			},
+			"USPS": {
+				ResidentialFee: 0,
+				Areas:          nil, // USPS 全境统一资费
+			},
+			"DHL": {
+				ResidentialFee: 0,
+				Areas: append(withFees(usRemote, 25.00, 25.00),
+					AreaRange{Country: "CA", From: "X0", To: "X1", Type: model.SurchargeTypeRemoteArea, Fee: 25.00},
+					AreaRange{Country: "CA", From: "Y0", To: "Y1", Type: model.SurchargeTypeRemoteArea, Fee: 25.00},
+					AreaRange{Country: "AU", From: "0800", To: "0899", Type: model.SurchargeTypeRemoteArea, Fee: 25.00},
+				),
+			},
+		},
+		UpdatedAt: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// LoadDataset 从 JSON 文件加载附加费数据集
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read surcharge dataset failed: %w", err)
+	}
+
+	var dataset Dataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, fmt.Errorf("unmarshal surcharge dataset failed: %w", err)
+	}
+
+	if err := dataset.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &dataset, nil
+}
+
+// Normalize 校验并归一化数据集（国家代码统一大写，邮编去除分隔符）
+func (d *Dataset) Normalize() error {
+	if d.Version == "" {
+		return fmt.Errorf("surcharge dataset version is required")
+	}
+	d.Currency = strings.ToUpper(strings.TrimSpace(d.Currency))
+	if d.Currency == "" {
+		return fmt.Errorf("surcharge dataset currency is required")
+	}
+
+	carriers := make(map[string]*CarrierSurcharge, len(d.Carriers))
+	for name, carrier := range d.Carriers {
+		if carrier == nil {
+			continue
+		}
+		if carrier.ResidentialFee < 0 {
+			return fmt.Errorf("invalid residential fee for %s: %v", name, carrier.ResidentialFee)
+		}
+
+		areas := make([]AreaRange, 0, len(carrier.Areas))
+		for i, area := range carrier.Areas {
+			area.Country = strings.ToUpper(strings.TrimSpace(area.Country))
+			area.From = normalizePostalCode(area.From)
+			area.To = normalizePostalCode(area.To)
+
+			if area.Country == "" || area.From == "" || area.To == "" {
+				return fmt.Errorf("carrier %s area #%d: country/from/to are required", name, i+1)
+			}
+			if len(area.From) != len(area.To) || area.From > area.To {
+				return fmt.Errorf("carrier %s area #%d: invalid postal range %s-%s", name, i+1, area.From, area.To)
+			}
+			if area.Type != model.SurchargeTypeRemoteArea && area.Type != model.SurchargeTypeExtendedArea {
+				return fmt.Errorf("carrier %s area #%d: invalid type %q", name, i+1, area.Type)
+			}
+			if area.Fee < 0 {
+				return fmt.Errorf("carrier %s area #%d: invalid fee %v", name, i+1, area.Fee)
+			}
+			areas = append(areas, area)
+		}
+
+		carriers[strings.TrimSpace(name)] = &CarrierSurcharge{
+			ResidentialFee: carrier.ResidentialFee,
+			Areas:          areas,
+		}
+	}
+	d.Carriers = carriers
+
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// clone 深拷贝（对外返回副本，避免调用方修改内部状态）
+func (d *Dataset) clone() *Dataset {
+	carriers := make(map[string]*CarrierSurcharge, len(d.Carriers))
+	for name, carrier := range d.Carriers {
+		if carrier == nil {
+			continue
+		}
+		areas := make([]AreaRange, len(carrier.Areas))
+		copy(areas, carrier.Areas)
+		carriers[name] = &CarrierSurcharge{
+			ResidentialFee: carrier.ResidentialFee,
+			Areas:          areas,
+		}
+	}
+	return &Dataset{
+		Version:   d.Version,
+		Currency:  d.Currency,
+		Carriers:  carriers,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+// normalizePostalCode 邮编归一化：转大写并去除空格和连字符
+func normalizePostalCode(postalCode string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(postalCode) {
+		if r == ' ' || r == '-' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
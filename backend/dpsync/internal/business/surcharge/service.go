@@ -0,0 +1,167 @@
+package surcharge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"oip/common/model"
+)
+
+// Service 附加费查询服务（并发安全，支持整体替换数据集）
+type Service struct {
+	mu      sync.RWMutex
+	dataset *Dataset
+}
+
+// AreaMatch 命中的偏远/扩展区域
+type AreaMatch struct {
+	Carrier string
+	Area    AreaRange
+}
+
+// NewService 创建附加费查询服务
+func NewService(dataset *Dataset) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(dataset); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前数据集
+func (s *Service) Update(dataset *Dataset) error {
+	if dataset == nil {
+		return fmt.Errorf("surcharge dataset cannot be nil")
+	}
+
+	normalized := dataset.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.dataset = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Dataset 返回当前数据集副本
+func (s *Service) Dataset() *Dataset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataset.clone()
+}
+
+// Currency 附加费金额币种
+func (s *Service) Currency() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataset.Currency
+}
+
+// Version 数据集版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataset.Version
+}
+
+// LookupArea 查询指定承运商下收件邮编所在的偏远/扩展区域，未命中返回 nil
+func (s *Service) LookupArea(carrier, country, postalCode string) *AreaRange {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rule := s.carrierRule(carrier)
+	if rule == nil {
+		return nil
+	}
+	return matchArea(rule.Areas, country, normalizePostalCode(postalCode))
+}
+
+// MatchAreas 查询收件邮编在各承运商下命中的偏远/扩展区域（按承运商名称排序）
+func (s *Service) MatchAreas(country, postalCode string) []AreaMatch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	postal := normalizePostalCode(postalCode)
+	matches := make([]AreaMatch, 0)
+	for carrier, rule := range s.dataset.Carriers {
+		if area := matchArea(rule.Areas, country, postal); area != nil {
+			matches = append(matches, AreaMatch{Carrier: carrier, Area: *area})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Carrier < matches[j].Carrier
+	})
+
+	return matches
+}
+
+// Surcharges 计算指定承运商对该地址收取的附加费明细（金额币种为数据集币种）
+func (s *Service) Surcharges(carrier string, addr *Address, classification Classification) []model.Surcharge {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rule := s.carrierRule(carrier)
+	if rule == nil || addr == nil {
+		return nil
+	}
+
+	surcharges := make([]model.Surcharge, 0)
+
+	if area := matchArea(rule.Areas, addr.Country, normalizePostalCode(addr.PostalCode)); area != nil && area.Fee > 0 {
+		surcharges = append(surcharges, model.Surcharge{
+			Type:        area.Type,
+			Description: fmt.Sprintf("%s postal code %s-%s", area.Country, area.From, area.To),
+			Amount:      area.Fee,
+			Currency:    s.dataset.Currency,
+		})
+	}
+
+	if classification.Type == model.AddressTypeResidential && rule.ResidentialFee > 0 {
+		surcharges = append(surcharges, model.Surcharge{
+			Type:        model.SurchargeTypeResidential,
+			Description: classification.Reason,
+			Amount:      rule.ResidentialFee,
+			Currency:    s.dataset.Currency,
+		})
+	}
+
+	return surcharges
+}
+
+// carrierRule 查找承运商规则（大小写不敏感，调用方需持有读锁）
+func (s *Service) carrierRule(carrier string) *CarrierSurcharge {
+	carrier = strings.TrimSpace(carrier)
+	if rule, ok := s.dataset.Carriers[carrier]; ok {
+		return rule
+	}
+	for name, rule := range s.dataset.Carriers {
+		if strings.EqualFold(name, carrier) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matchArea 在区间列表中查找命中项（postal 需已归一化）
+func matchArea(areas []AreaRange, country, postal string) *AreaRange {
+	if postal == "" {
+		return nil
+	}
+	for i := range areas {
+		area := &areas[i]
+		if area.Country != country || len(postal) < len(area.From) {
+			continue
+		}
+		prefix := postal[:len(area.From)]
+		if prefix >= area.From && prefix <= area.To {
+			return area
+		}
+	}
+	return nil
+}
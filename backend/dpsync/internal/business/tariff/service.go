@@ -0,0 +1,66 @@
+package tariff
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Service 进口税费查询服务（并发安全，支持整体替换税率表）
+type Service struct {
+	mu    sync.RWMutex
+	table *Table
+}
+
+// NewService 创建进口税费查询服务
+func NewService(table *Table) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(table); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前税率表
+func (s *Service) Update(table *Table) error {
+	if table == nil {
+		return fmt.Errorf("tariff table cannot be nil")
+	}
+
+	normalized := table.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.table = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Table 返回当前税率表副本
+func (s *Service) Table() *Table {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.clone()
+}
+
+// Version 税率表版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Version
+}
+
+// Lookup 查询目的国税费规则（返回副本，未登记时 ok=false）
+func (s *Service) Lookup(country string) (*Destination, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	destination, ok := s.table.Destinations[strings.ToUpper(strings.TrimSpace(country))]
+	if !ok || destination == nil {
+		return nil, false
+	}
+	return destination.clone(), true
+}
@@ -0,0 +1,290 @@
+package tariff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// Table 进口税费参考数据：按目的国登记关税/进口环节税税率与起征点
+// 税率为常见品类的简化 MFN 税率，仅用于下单前估算，不替代报关行的正式核定
+type Table struct {
+	Version      string                  `json:"version"`      // 数据版本（写入诊断结果用于审计）
+	Destinations map[string]*Destination `json:"destinations"` // key: 目的国 ISO 两位代码
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// Destination 目的国税费规则（金额均以 Currency 计）
+type Destination struct {
+	Currency           string             `json:"currency"`             // 起征点与手续费币种（目的国本币）
+	DutyDeMinimis      float64            `json:"duty_de_minimis"`      // 申报货值不超过该值时免征关税（0 表示无豁免）
+	TaxDeMinimis       float64            `json:"tax_de_minimis"`       // 申报货值不超过该值时免征进口环节税（0 表示无豁免）
+	TaxName            string             `json:"tax_name,omitempty"`   // 进口环节税名称（VAT/GST/消费税）
+	TaxPercent         float64            `json:"tax_percent"`          // 进口环节税率（百分比）
+	TaxIncludesDuty    bool               `json:"tax_includes_duty"`    // 进口环节税税基是否包含关税
+	DefaultDutyPercent float64            `json:"default_duty_percent"` // 未登记 HS 章或缺少 HS 编码时的关税税率
+	DutyPercents       map[string]float64 `json:"duty_percents"`        // key: HS 编码章（前两位）
+	ClearanceFee       float64            `json:"clearance_fee"`        // 产生税费时承运商收取的代垫手续费
+}
+
+// DefaultTable 内置税率表（未配置数据文件时使用）
+func DefaultTable() *Table {
+	// 欧盟成员国共用关税税率，增值税率各国不同
+	euDuty := map[string]float64{
+		"33": 0,   // 化妆品
+		"42": 3,   // 箱包皮具
+		"61": 12,  // 针织服装
+		"62": 12,  // 梭织服装
+		"64": 8,   // 鞋靴
+		"71": 2.5, // 首饰
+		"85": 2,   // 电子产品
+		"95": 2.7, // 玩具
+	}
+
+	return &Table{
+		Version: "builtin-2025-12",
+		Destinations: map[string]*Destination{
+			// 美国自 2025-08-29 起暂停小额豁免，所有进口商品均需缴纳关税；无联邦进口环节税
+			"US": {
+				Currency:           "USD",
+				DefaultDutyPercent: 5,
+				DutyPercents: map[string]float64{
+					"33": 3, "42": 8, "61": 15, "62": 15, "64": 12, "71": 5, "85": 2, "95": 0,
+				},
+				ClearanceFee: 15,
+			},
+			"CA": {
+				Currency:           "CAD",
+				DutyDeMinimis:      150,
+				TaxDeMinimis:       40,
+				TaxName:            "GST",
+				TaxPercent:         5,
+				TaxIncludesDuty:    true,
+				DefaultDutyPercent: 6.5,
+				DutyPercents: map[string]float64{
+					"33": 6.5, "42": 10, "61": 18, "62": 18, "64": 18, "71": 8.5, "85": 0, "95": 0,
+				},
+				ClearanceFee: 10,
+			},
+			// 英国 135 英镑以下由卖家在销售环节代征 VAT，此处仍按进口环节计入，避免低估商家成本
+			"GB": {
+				Currency:           "GBP",
+				DutyDeMinimis:      135,
+				TaxName:            "VAT",
+				TaxPercent:         20,
+				TaxIncludesDuty:    true,
+				DefaultDutyPercent: 4,
+				DutyPercents: map[string]float64{
+					"33": 0, "42": 4, "61": 12, "62": 12, "64": 8, "71": 2.5, "85": 0, "95": 0,
+				},
+				ClearanceFee: 12,
+			},
+			"DE": {
+				Currency:           "EUR",
+				DutyDeMinimis:      150,
+				TaxName:            "VAT",
+				TaxPercent:         19,
+				TaxIncludesDuty:    true,
+				DefaultDutyPercent: 4,
+				DutyPercents:       euDuty,
+				ClearanceFee:       10,
+			},
+			"FR": {
+				Currency:           "EUR",
+				DutyDeMinimis:      150,
+				TaxName:            "VAT",
+				TaxPercent:         20,
+				TaxIncludesDuty:    true,
+				DefaultDutyPercent: 4,
+				DutyPercents:       euDuty,
+				ClearanceFee:       10,
+			},
+			"AU": {
+				Currency:           "AUD",
+				DutyDeMinimis:      1000,
+				TaxDeMinimis:       1000,
+				TaxName:            "GST",
+				TaxPercent:         10,
+				TaxIncludesDuty:    true,
+				DefaultDutyPercent: 5,
+				DutyPercents: map[string]float64{
+					"33": 5, "42": 5, "61": 5, "62": 5, "64": 5, "71": 5, "85": 0, "95": 0,
+				},
+				ClearanceFee: 15,
+			},
+			"JP": {
+				Currency:           "JPY",
+				DutyDeMinimis:      10000,
+				TaxDeMinimis:       10000,
+				TaxName:            "Consumption Tax",
+				TaxPercent:         10,
+				TaxIncludesDuty:    true,
+				DefaultDutyPercent: 5,
+				DutyPercents: map[string]float64{
+					"33": 0, "42": 10, "61": 10, "62": 10, "64": 30, "71": 5.2, "85": 0, "95": 0,
+				},
+				ClearanceFee: 1500,
+			},
+			"CN": {
+				Currency:           "CNY",
+				DutyDeMinimis:      50,
+				TaxDeMinimis:       50,
+				TaxName:            "VAT",
+				TaxPercent:         13,
+				TaxIncludesDuty:    true,
+				DefaultDutyPercent: 10,
+				DutyPercents: map[string]float64{
+					"33": 5, "42": 10, "61": 8, "62": 8, "64": 10, "71": 10, "85": 5, "95": 5,
+				},
+				ClearanceFee: 50,
+			},
+		},
+	}
+}
+
+// LoadTable 从 JSON 文件加载税率表
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tariff table failed: %w", err)
+	}
+
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("unmarshal tariff table failed: %w", err)
+	}
+
+	if err := table.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+// Normalize 校验并归一化税率表（国家代码与币种大写，HS 章为两位数字）
+func (t *Table) Normalize() error {
+	if t.Version == "" {
+		return fmt.Errorf("tariff table version is required")
+	}
+
+	destinations := make(map[string]*Destination, len(t.Destinations))
+	for code, destination := range t.Destinations {
+		if destination == nil {
+			return fmt.Errorf("destination %s: tariff is required", code)
+		}
+		if err := destination.normalize(); err != nil {
+			return fmt.Errorf("destination %s: %w", code, err)
+		}
+		destinations[strings.ToUpper(strings.TrimSpace(code))] = destination
+	}
+	t.Destinations = destinations
+
+	if t.UpdatedAt.IsZero() {
+		t.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// normalize 校验目的国税费规则
+func (d *Destination) normalize() error {
+	d.Currency = model.NormalizeCurrency(d.Currency)
+	if d.Currency == "" {
+		return fmt.Errorf("currency is required")
+	}
+	if err := model.ValidateCurrencyCode(d.Currency); err != nil {
+		return err
+	}
+	if d.DutyDeMinimis < 0 || d.TaxDeMinimis < 0 || d.ClearanceFee < 0 {
+		return fmt.Errorf("de minimis thresholds and clearance_fee cannot be negative")
+	}
+	if err := validatePercent("tax_percent", d.TaxPercent); err != nil {
+		return err
+	}
+	if err := validatePercent("default_duty_percent", d.DefaultDutyPercent); err != nil {
+		return err
+	}
+
+	percents := make(map[string]float64, len(d.DutyPercents))
+	for chapter, percent := range d.DutyPercents {
+		normalized := Chapter(chapter)
+		if normalized == "" || len(strings.TrimSpace(chapter)) != 2 {
+			return fmt.Errorf("invalid HS chapter %q: expected 2 digits", chapter)
+		}
+		if err := validatePercent("duty_percents."+chapter, percent); err != nil {
+			return err
+		}
+		percents[normalized] = percent
+	}
+	d.DutyPercents = percents
+
+	return nil
+}
+
+// validatePercent 税率须在 [0, 100] 区间
+func validatePercent(field string, percent float64) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("%s must be between 0 and 100", field)
+	}
+	return nil
+}
+
+// clone 深拷贝（对外返回副本，避免调用方修改内部状态）
+func (t *Table) clone() *Table {
+	destinations := make(map[string]*Destination, len(t.Destinations))
+	for code, destination := range t.Destinations {
+		if destination == nil {
+			destinations[code] = nil
+			continue
+		}
+		destinations[code] = destination.clone()
+	}
+
+	return &Table{
+		Version:      t.Version,
+		Destinations: destinations,
+		UpdatedAt:    t.UpdatedAt,
+	}
+}
+
+// clone 深拷贝目的国规则
+func (d *Destination) clone() *Destination {
+	copied := *d
+	copied.DutyPercents = make(map[string]float64, len(d.DutyPercents))
+	for chapter, percent := range d.DutyPercents {
+		copied.DutyPercents[chapter] = percent
+	}
+	return &copied
+}
+
+// DutyPercent 按 HS 编码章查找关税税率，未登记时返回默认税率（matched=false）
+func (d *Destination) DutyPercent(chapter string) (percent float64, matched bool) {
+	if percent, ok := d.DutyPercents[chapter]; ok {
+		return percent, true
+	}
+	return d.DefaultDutyPercent, false
+}
+
+// Chapter 提取 HS 编码章（前两位数字，忽略点号与空格；不足两位时返回空串）
+func Chapter(hsCode string) string {
+	digits := make([]byte, 0, 2)
+	for i := 0; i < len(hsCode) && len(digits) < 2; i++ {
+		c := hsCode[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == '.' || c == ' ':
+			continue
+		default:
+			return ""
+		}
+	}
+	if len(digits) < 2 {
+		return ""
+	}
+	return string(digits)
+}
@@ -0,0 +1,295 @@
+package transit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"oip/common/model"
+)
+
+// dateLayout 节假日日期格式
+const dateLayout = model.DateLayout
+
+// Calendar 时效估算参考数据：各国工作日历与承运商揽收截单时间
+type Calendar struct {
+	Version   string                      `json:"version"`   // 数据版本（写入诊断结果用于审计）
+	Countries map[string]*CountryCalendar `json:"countries"` // key: ISO 两位国家代码
+	Carriers  map[string]*CarrierSchedule `json:"carriers"`  // key: 承运商名称（查找时大小写不敏感）
+	UpdatedAt time.Time                   `json:"updated_at"`
+}
+
+// CountryCalendar 国家工作日历
+type CountryCalendar struct {
+	Timezone       string            `json:"timezone"`                  // 默认时区（IANA 名称）
+	StateTimezones map[string]string `json:"state_timezones,omitempty"` // 按州/省覆盖时区（跨时区国家）
+	Weekend        []string          `json:"weekend"`                   // 周末（英文星期名，如 Saturday）
+	Holidays       []string          `json:"holidays"`                  // 法定节假日（YYYY-MM-DD）
+
+	location *time.Location
+	weekend  map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+// CarrierSchedule 承运商揽收与派送规则
+type CarrierSchedule struct {
+	CutoffTime       string `json:"cutoff_time"`       // 当日揽收截单时间（始发地时区，HH:MM）
+	DeliversSaturday bool   `json:"delivers_saturday"` // 周六是否派送（仍受目的国节假日约束）
+	VarianceDays     int    `json:"variance_days"`     // 时效波动天数（最晚送达 = 最早送达 + 波动）
+
+	cutoffMinutes int
+}
+
+// defaultSchedule 未登记承运商使用的揽收规则
+var defaultSchedule = &CarrierSchedule{CutoffTime: "17:00", VarianceDays: 1, cutoffMinutes: 17 * 60}
+
+// DefaultCalendar 内置日历（未配置数据文件时使用）
+func DefaultCalendar() *Calendar {
+	saturdaySunday := []string{"Saturday", "Sunday"}
+
+	return &Calendar{
+		Version: "builtin-2025-12",
+		Countries: map[string]*CountryCalendar{
+			"US": {
+				Timezone: "America/New_York",
+				StateTimezones: map[string]string{
+					"CA": "America/Los_Angeles", "WA": "America/Los_Angeles", "OR": "America/Los_Angeles", "NV": "America/Los_Angeles",
+					"AZ": "America/Phoenix", "CO": "America/Denver", "UT": "America/Denver", "NM": "America/Denver",
+					"TX": "America/Chicago", "IL": "America/Chicago", "MN": "America/Chicago", "MO": "America/Chicago",
+					"AK": "America/Anchorage", "HI": "Pacific/Honolulu",
+				},
+				Weekend: saturdaySunday,
+				Holidays: []string{
+					"2025-12-25", "2026-01-01", "2026-01-19", "2026-02-16", "2026-05-25", "2026-06-19",
+					"2026-07-03", "2026-09-07", "2026-10-12", "2026-11-11", "2026-11-26", "2026-12-25",
+				},
+			},
+			"CA": {
+				Timezone: "America/Toronto",
+				StateTimezones: map[string]string{
+					"BC": "America/Vancouver", "AB": "America/Edmonton", "SK": "America/Regina", "MB": "America/Winnipeg",
+				},
+				Weekend: saturdaySunday,
+				Holidays: []string{
+					"2025-12-25", "2025-12-26", "2026-01-01", "2026-04-03", "2026-05-18", "2026-07-01",
+					"2026-09-07", "2026-10-12", "2026-12-25", "2026-12-28",
+				},
+			},
+			"GB": {
+				Timezone: "Europe/London",
+				Weekend:  saturdaySunday,
+				Holidays: []string{
+					"2025-12-25", "2025-12-26", "2026-01-01", "2026-04-03", "2026-04-06", "2026-05-04",
+					"2026-05-25", "2026-08-31", "2026-12-25", "2026-12-28",
+				},
+			},
+			"DE": {
+				Timezone: "Europe/Berlin",
+				Weekend:  saturdaySunday,
+				Holidays: []string{
+					"2025-12-25", "2025-12-26", "2026-01-01", "2026-04-03", "2026-04-06", "2026-05-01",
+					"2026-05-14", "2026-05-25", "2026-10-03", "2026-12-25", "2026-12-26",
+				},
+			},
+			"FR": {
+				Timezone: "Europe/Paris",
+				Weekend:  saturdaySunday,
+				Holidays: []string{
+					"2025-12-25", "2026-01-01", "2026-04-06", "2026-05-01", "2026-05-08", "2026-05-14",
+					"2026-05-25", "2026-07-14", "2026-08-15", "2026-11-01", "2026-11-11", "2026-12-25",
+				},
+			},
+			"CN": {
+				Timezone: "Asia/Shanghai",
+				Weekend:  saturdaySunday,
+				Holidays: []string{
+					"2026-01-01", "2026-02-16", "2026-02-17", "2026-02-18", "2026-02-19", "2026-02-20",
+					"2026-04-06", "2026-05-01", "2026-05-04", "2026-05-05", "2026-06-19",
+					"2026-10-01", "2026-10-02", "2026-10-05", "2026-10-06", "2026-10-07",
+				},
+			},
+			"JP": {
+				Timezone: "Asia/Tokyo",
+				Weekend:  saturdaySunday,
+				Holidays: []string{
+					"2026-01-01", "2026-01-02", "2026-01-12", "2026-02-11", "2026-02-23", "2026-03-20",
+					"2026-04-29", "2026-05-04", "2026-05-05", "2026-05-06", "2026-07-20", "2026-08-11",
+					"2026-09-21", "2026-09-22", "2026-09-23", "2026-10-12", "2026-11-03", "2026-11-23",
+				},
+			},
+			"AU": {
+				Timezone: "Australia/Sydney",
+				StateTimezones: map[string]string{
+					"WA": "Australia/Perth", "SA": "Australia/Adelaide", "QLD": "Australia/Brisbane", "NT": "Australia/Darwin",
+				},
+				Weekend: saturdaySunday,
+				Holidays: []string{
+					"2025-12-25", "2025-12-26", "2026-01-01", "2026-01-26", "2026-04-03", "2026-04-06",
+					"2026-04-25", "2026-06-08", "2026-12-25", "2026-12-28",
+				},
+			},
+		},
+		Carriers: map[string]*CarrierSchedule{
+			"FedEx": {CutoffTime: "17:00", DeliversSaturday: false, VarianceDays: 1},
+			"UPS":   {CutoffTime: "18:00", DeliversSaturday: false, VarianceDays: 1},
+			"USPS":  {CutoffTime: "17:00", DeliversSaturday: true, VarianceDays: 2},
+			"DHL":   {CutoffTime: "15:00", DeliversSaturday: false, VarianceDays: 1},
+		},
+		UpdatedAt: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// LoadCalendar 从 JSON 文件加载日历
+func LoadCalendar(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read transit calendar failed: %w", err)
+	}
+
+	var calendar Calendar
+	if err := json.Unmarshal(data, &calendar); err != nil {
+		return nil, fmt.Errorf("unmarshal transit calendar failed: %w", err)
+	}
+
+	if err := calendar.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return &calendar, nil
+}
+
+// Normalize 校验并归一化日历（国家代码大写，解析时区、周末、节假日与截单时间）
+func (c *Calendar) Normalize() error {
+	if c.Version == "" {
+		return fmt.Errorf("transit calendar version is required")
+	}
+
+	countries := make(map[string]*CountryCalendar, len(c.Countries))
+	for code, country := range c.Countries {
+		if country == nil {
+			return fmt.Errorf("country %s: calendar is required", code)
+		}
+		if err := country.normalize(); err != nil {
+			return fmt.Errorf("country %s: %w", code, err)
+		}
+		countries[strings.ToUpper(strings.TrimSpace(code))] = country
+	}
+	c.Countries = countries
+
+	for name, schedule := range c.Carriers {
+		if schedule == nil {
+			return fmt.Errorf("carrier %s: schedule is required", name)
+		}
+		if err := schedule.normalize(); err != nil {
+			return fmt.Errorf("carrier %s: %w", name, err)
+		}
+	}
+
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+// normalize 解析国家日历
+func (cc *CountryCalendar) normalize() error {
+	location, err := time.LoadLocation(cc.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", cc.Timezone, err)
+	}
+	cc.location = location
+
+	states := make(map[string]string, len(cc.StateTimezones))
+	for state, tz := range cc.StateTimezones {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("state %s: invalid timezone %q: %w", state, tz, err)
+		}
+		states[strings.ToUpper(strings.TrimSpace(state))] = tz
+	}
+	cc.StateTimezones = states
+
+	cc.weekend = make(map[time.Weekday]bool, len(cc.Weekend))
+	for _, day := range cc.Weekend {
+		weekday, ok := parseWeekday(day)
+		if !ok {
+			return fmt.Errorf("invalid weekend day %q", day)
+		}
+		cc.weekend[weekday] = true
+	}
+
+	cc.holidays = make(map[string]bool, len(cc.Holidays))
+	for _, day := range cc.Holidays {
+		if _, err := time.Parse(dateLayout, day); err != nil {
+			return fmt.Errorf("invalid holiday %q: expected YYYY-MM-DD", day)
+		}
+		cc.holidays[day] = true
+	}
+
+	return nil
+}
+
+// normalize 解析截单时间
+func (s *CarrierSchedule) normalize() error {
+	cutoff, err := time.Parse("15:04", strings.TrimSpace(s.CutoffTime))
+	if err != nil {
+		return fmt.Errorf("invalid cutoff_time %q: expected HH:MM", s.CutoffTime)
+	}
+	if s.VarianceDays < 0 {
+		return fmt.Errorf("variance_days cannot be negative")
+	}
+	s.cutoffMinutes = cutoff.Hour()*60 + cutoff.Minute()
+	return nil
+}
+
+// clone 深拷贝（对外返回副本，避免调用方修改内部状态）
+func (c *Calendar) clone() *Calendar {
+	countries := make(map[string]*CountryCalendar, len(c.Countries))
+	for code, country := range c.Countries {
+		if country == nil {
+			countries[code] = nil
+			continue
+		}
+		states := make(map[string]string, len(country.StateTimezones))
+		for state, tz := range country.StateTimezones {
+			states[state] = tz
+		}
+		countries[code] = &CountryCalendar{
+			Timezone:       country.Timezone,
+			StateTimezones: states,
+			Weekend:        append([]string(nil), country.Weekend...),
+			Holidays:       append([]string(nil), country.Holidays...),
+		}
+	}
+
+	carriers := make(map[string]*CarrierSchedule, len(c.Carriers))
+	for name, schedule := range c.Carriers {
+		if schedule == nil {
+			carriers[name] = nil
+			continue
+		}
+		copied := *schedule
+		carriers[name] = &copied
+	}
+
+	return &Calendar{
+		Version:   c.Version,
+		Countries: countries,
+		Carriers:  carriers,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// parseWeekday 解析英文星期名（大小写不敏感，支持三字母缩写）
+func parseWeekday(s string) (time.Weekday, bool) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
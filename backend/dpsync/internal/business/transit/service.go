@@ -0,0 +1,192 @@
+package transit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // 容器镜像未必带系统时区库，内嵌一份保证 LoadLocation 可用
+
+	"oip/common/model"
+)
+
+// maxScanDays 寻找工作日时最多向后扫描的天数（防止日历配置错误导致死循环）
+const maxScanDays = 60
+
+// defaultCountry 未登记国家使用的日历：UTC、周六日休息、无节假日
+var defaultCountry = &CountryCalendar{
+	Timezone: "UTC",
+	location: time.UTC,
+	weekend:  map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+	holidays: map[string]bool{},
+}
+
+// EstimateRequest 时效估算请求
+type EstimateRequest struct {
+	CreatedAt          time.Time // 下单时间
+	Carrier            string
+	OriginCountry      string // 始发国（ISO 两位代码）
+	OriginState        string // 始发州/省（用于跨时区国家确定时区）
+	DestinationCountry string // 目的国（ISO 两位代码）
+	TransitDays        int    // 承运商标称时效（工作日）
+}
+
+// Service 时效估算服务（并发安全，支持整体替换日历）
+type Service struct {
+	mu       sync.RWMutex
+	calendar *Calendar
+}
+
+// NewService 创建时效估算服务
+func NewService(calendar *Calendar) (*Service, error) {
+	s := &Service{}
+	if err := s.Update(calendar); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update 替换当前日历
+func (s *Service) Update(calendar *Calendar) error {
+	if calendar == nil {
+		return fmt.Errorf("transit calendar cannot be nil")
+	}
+
+	normalized := calendar.clone()
+	if err := normalized.Normalize(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.calendar = normalized
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Calendar 返回当前日历副本
+func (s *Service) Calendar() *Calendar {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.calendar.clone()
+}
+
+// Version 日历版本
+func (s *Service) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.calendar.Version
+}
+
+// Estimate 估算送达日期区间
+// 1. 下单时间换算到始发地时区，超过承运商截单时间或当天非工作日时顺延到下一个始发地工作日揽收
+// 2. 从揽收日次日起按目的国派送日（排除周末与节假日，承运商周六派送时周六计入）累计标称时效，得到最早送达日
+// 3. 最早送达日再累计承运商波动天数，得到最晚送达日
+func (s *Service) Estimate(req *EstimateRequest) (*model.DeliveryEstimate, error) {
+	s.mu.RLock()
+	calendar := s.calendar
+	s.mu.RUnlock()
+
+	origin := calendar.country(req.OriginCountry)
+	destination := calendar.country(req.DestinationCountry)
+	schedule := calendar.schedule(req.Carrier)
+
+	local := req.CreatedAt.In(origin.locationFor(req.OriginState))
+	day := civilDate(local)
+	if local.Hour()*60+local.Minute() >= schedule.cutoffMinutes || !origin.isWorkday(day) {
+		next, err := nextDay(day, origin.isWorkday)
+		if err != nil {
+			return nil, fmt.Errorf("origin %s: %w", req.OriginCountry, err)
+		}
+		day = next
+	}
+	shipDate := day
+
+	isDeliveryDay := func(d time.Time) bool {
+		return destination.isDeliveryDay(d, schedule.DeliversSaturday)
+	}
+
+	earliest := shipDate
+	for i := 0; i < req.TransitDays; i++ {
+		next, err := nextDay(earliest, isDeliveryDay)
+		if err != nil {
+			return nil, fmt.Errorf("destination %s: %w", req.DestinationCountry, err)
+		}
+		earliest = next
+	}
+
+	latest := earliest
+	for i := 0; i < schedule.VarianceDays; i++ {
+		next, err := nextDay(latest, isDeliveryDay)
+		if err != nil {
+			return nil, fmt.Errorf("destination %s: %w", req.DestinationCountry, err)
+		}
+		latest = next
+	}
+
+	return &model.DeliveryEstimate{
+		ShipDate: shipDate.Format(dateLayout),
+		Earliest: earliest.Format(dateLayout),
+		Latest:   latest.Format(dateLayout),
+	}, nil
+}
+
+// country 查询国家日历，未登记时使用默认日历
+func (c *Calendar) country(code string) *CountryCalendar {
+	if country, ok := c.Countries[strings.ToUpper(strings.TrimSpace(code))]; ok && country != nil {
+		return country
+	}
+	return defaultCountry
+}
+
+// schedule 查询承运商揽收规则（大小写不敏感），未登记时使用默认规则
+func (c *Calendar) schedule(carrier string) *CarrierSchedule {
+	for name, schedule := range c.Carriers {
+		if strings.EqualFold(name, strings.TrimSpace(carrier)) && schedule != nil {
+			return schedule
+		}
+	}
+	return defaultSchedule
+}
+
+// locationFor 始发地时区（州/省有覆盖配置时优先）
+func (cc *CountryCalendar) locationFor(state string) *time.Location {
+	if tz, ok := cc.StateTimezones[strings.ToUpper(strings.TrimSpace(state))]; ok {
+		if location, err := time.LoadLocation(tz); err == nil {
+			return location
+		}
+	}
+	return cc.location
+}
+
+// isWorkday 是否为工作日（揽收日）
+func (cc *CountryCalendar) isWorkday(d time.Time) bool {
+	return !cc.weekend[d.Weekday()] && !cc.holidays[d.Format(dateLayout)]
+}
+
+// isDeliveryDay 是否为派送日
+func (cc *CountryCalendar) isDeliveryDay(d time.Time, deliversSaturday bool) bool {
+	if cc.holidays[d.Format(dateLayout)] {
+		return false
+	}
+	if d.Weekday() == time.Saturday && deliversSaturday {
+		return true
+	}
+	return !cc.weekend[d.Weekday()]
+}
+
+// nextDay 从 d 的次日起找到第一个满足条件的日期
+func nextDay(d time.Time, ok func(time.Time) bool) (time.Time, error) {
+	for i := 0; i < maxScanDays; i++ {
+		d = d.AddDate(0, 0, 1)
+		if ok(d) {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no working day within %d days", maxScanDays)
+}
+
+// civilDate 取本地日期（去掉时分秒，统一为 UTC 零点便于按天运算）
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"oip/dpsync/internal/business/order/diagnose"
+	"oip/dpsync/internal/business/order/diagnose/services"
 	"oip/dpsync/internal/framework"
 	"oip/dpsync/pkg/lmstfy"
 )
@@ -14,12 +15,13 @@ type HandlerFactory func(
 	baseHandler *framework.BaseHandler,
 	lmstfyClient *lmstfy.Client,
 	callbackQueue string,
+	compositeHandler *services.CompositeHandler,
 ) (framework.BusinessHandler, error)
 
 // HandlerMap 路由表（ActionType → Handler 映射）
 var HandlerMap = map[string]HandlerFactory{
 	"order_diagnose": diagnose.NewDiagnoseHandler,
 
-	// 未来扩展示例：
-	// "order_risk_check": order_risk.NewRiskCheckHandler,
+	// 新增诊断能力（如风险评分 risk）优先实现为 services.Diagnoser 在 order_diagnose 中执行；
+	// 只有处理流程与订单诊断不同的任务才需要新的 ActionType
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bitleak/lmstfy/client"
 
+	"oip/dpsync/internal/business/order/diagnose/services"
 	"oip/dpsync/internal/framework"
 	"oip/dpsync/pkg/lmstfy"
 	"oip/dpsync/pkg/lmstfyx"
@@ -13,7 +14,12 @@ import (
 )
 
 // GetProcess 返回核心处理函数
-func GetProcess(log logger.Logger, lmstfyClient *lmstfy.Client, callbackQueue string) lmstfyx.Proc {
+func GetProcess(
+	log logger.Logger,
+	lmstfyClient *lmstfy.Client,
+	callbackQueue string,
+	compositeHandler *services.CompositeHandler,
+) lmstfyx.Proc {
 	return func(ctx context.Context, lmstfyJob *client.Job) *lmstfyx.JobResp {
 		startTime := time.Now()
 
@@ -39,7 +45,7 @@ func GetProcess(log logger.Logger, lmstfyClient *lmstfy.Client, callbackQueue st
 			}
 		}
 
-		handler, err := handlerFactory(ctx, baseHandler, lmstfyClient, callbackQueue)
+		handler, err := handlerFactory(ctx, baseHandler, lmstfyClient, callbackQueue, compositeHandler)
 		if err != nil {
 			log.Errorf(ctx, "[GetProcess] handler creation failed: %v", err)
 			return &lmstfyx.JobResp{
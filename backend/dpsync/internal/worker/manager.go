@@ -3,10 +3,28 @@ package worker
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync"
+	"time"
 
 	"go.uber.org/atomic"
 
+	"oip/dpsync/internal/admin"
+	"oip/dpsync/internal/business/carbon"
+	"oip/dpsync/internal/business/dedup"
+	"oip/dpsync/internal/business/eligibility"
+	"oip/dpsync/internal/business/fx"
+	"oip/dpsync/internal/business/geo"
+	"oip/dpsync/internal/business/insurance"
+	"oip/dpsync/internal/business/order/diagnose/services"
+	"oip/dpsync/internal/business/rating"
+	"oip/dpsync/internal/business/risk"
+	"oip/dpsync/internal/business/screening"
+	"oip/dpsync/internal/business/shadow"
+	"oip/dpsync/internal/business/surcharge"
+	"oip/dpsync/internal/business/tariff"
+	"oip/dpsync/internal/business/transit"
 	"oip/dpsync/internal/domains"
 	"oip/dpsync/internal/framework"
 	"oip/dpsync/pkg/config"
@@ -26,6 +44,10 @@ type ManagerInstance struct {
 	cfg           *config.Config
 	lmstfyClient  *lmstfy.Client
 	callbackQueue string
+	composite     *services.CompositeHandler
+	adminServer   *admin.Server
+	shadows       *services.ShadowRunner // 影子诊断（可选，退出时等待执行中的影子诊断）
+	duplicates    *dedup.Detector        // 重复订单检测（可选，退出时关闭近期订单索引连接）
 	workers       []Worker
 	closing       *atomic.Bool
 	shutdownCh    chan struct{}
@@ -52,6 +74,41 @@ func NewManagerInstance(cfg *config.Config, log logger.Logger) (Manager, error)
 		return nil, fmt.Errorf("callback_queue is required in worker config")
 	}
 
+	// 重复订单检测（线上与影子诊断器共享近期订单索引）
+	duplicates, err := newDuplicateDetector(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// 初始化诊断依赖（汇率等参考数据）
+	deps, err := newDependencies(ctx, cfg.Diagnose, duplicates, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// 初始化诊断器注册表（进程级共享，所有 Handler 复用）
+	registry := services.NewDefaultRegistry(deps)
+	log.Infof(ctx, "[Manager] Registered diagnosers: %v", registry.Types())
+
+	// 影子诊断器（可选）
+	shadows, err := newShadowRunner(ctx, cfg, registry, duplicates, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// 诊断结果缓存（可选）
+	var cache services.ResultCache
+	if cfg.Diagnose.ResultCacheSize > 0 {
+		cache = services.NewMemoryResultCache(cfg.Diagnose.ResultCacheSize, cfg.Diagnose.ResultCacheTTL)
+		log.Infof(ctx, "[Manager] Result cache enabled: size=%d, ttl=%s", cfg.Diagnose.ResultCacheSize, cfg.Diagnose.ResultCacheTTL)
+	}
+
+	// 管理接口（可选）
+	var adminServer *admin.Server
+	if cfg.Admin.Addr != "" {
+		adminServer = admin.NewServer(cfg.Admin.Addr, cfg.Admin.Token, deps, shadows, log)
+	}
+
 	log.Infof(ctx, "[Manager] Initialized with callback_queue: %s", callbackQueue)
 
 	return &ManagerInstance{
@@ -59,6 +116,10 @@ func NewManagerInstance(cfg *config.Config, log logger.Logger) (Manager, error)
 		cfg:           cfg,
 		lmstfyClient:  lmstfyClient,
 		callbackQueue: callbackQueue,
+		composite:     services.NewCompositeHandler(registry, cache, shadows),
+		adminServer:   adminServer,
+		shadows:       shadows,
+		duplicates:    duplicates,
 		closing:       atomic.NewBool(false),
 		shutdownCh:    make(chan struct{}),
 		workers:       make([]Worker, 0),
@@ -70,6 +131,13 @@ func NewManagerInstance(cfg *config.Config, log logger.Logger) (Manager, error)
 func (m *ManagerInstance) Start() error {
 	m.logger.Infof(m.ctx, "[Manager] Starting...")
 
+	// 0. 启动管理接口
+	if m.adminServer != nil {
+		if err := m.adminServer.Start(m.ctx); err != nil {
+			return err
+		}
+	}
+
 	// 1. 加载所有 Worker
 	if err := m.loadWorkers(); err != nil {
 		return fmt.Errorf("failed to load workers: %w", err)
@@ -111,7 +179,32 @@ func (m *ManagerInstance) Shutdown() {
 		// 2. 等待所有 Worker 退出
 		m.wg.Wait()
 
-		// 3. 关闭信号通道
+		// 3. 关闭管理接口
+		if m.adminServer != nil {
+			ctx, cancel := context.WithTimeout(m.ctx, 5*time.Second)
+			if err := m.adminServer.Shutdown(ctx); err != nil {
+				m.logger.Errorf(m.ctx, "[Manager] Admin server shutdown error: %v", err)
+			}
+			cancel()
+		}
+
+		// 4. 等待执行中的影子诊断写入结果（Worker 均已退出，不再提交新的影子诊断）
+		if m.shadows != nil {
+			ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
+			if err := m.shadows.Close(ctx); err != nil {
+				m.logger.Errorf(m.ctx, "[Manager] Shadow runner close error: %v", err)
+			}
+			cancel()
+		}
+
+		// 5. 关闭近期订单索引连接（线上与影子诊断器均已退出）
+		if m.duplicates != nil {
+			if err := m.duplicates.Close(); err != nil {
+				m.logger.Errorf(m.ctx, "[Manager] Close recent order index error: %v", err)
+			}
+		}
+
+		// 6. 关闭信号通道
 		close(m.shutdownCh)
 
 		m.logger.Infof(m.ctx, "[Manager] Shutdown complete")
@@ -140,7 +233,7 @@ func (m *ManagerInstance) loadWorkers() error {
 		}
 
 		// 获取 GetProcess 函数
-		getProcess := domains.GetProcess(m.logger, m.lmstfyClient, m.callbackQueue)
+		getProcess := domains.GetProcess(m.logger, m.lmstfyClient, m.callbackQueue, m.composite)
 
 		// 创建 Worker 实例
 		worker, err := NewWorkerInstance(
@@ -161,3 +254,287 @@ func (m *ManagerInstance) loadWorkers() error {
 
 	return nil
 }
+
+// newDependencies 初始化诊断依赖（重复订单检测由调用方创建，线上与影子诊断器共享）
+func newDependencies(ctx context.Context, cfg config.DiagnoseConfig, duplicates *dedup.Detector, log logger.Logger) (*services.Dependencies, error) {
+	table := fx.DefaultRateTable()
+	if cfg.FXRatesFile != "" {
+		loaded, err := fx.LoadRateTable(cfg.FXRatesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fx rates: %w", err)
+		}
+		table = loaded
+	}
+
+	converter, err := fx.NewConverter(table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fx converter: %w", err)
+	}
+	log.Infof(ctx, "[Manager] FX rate table loaded: base=%s, version=%s", converter.Base(), converter.Table().Version)
+
+	dataset := surcharge.DefaultDataset()
+	if cfg.SurchargeFile != "" {
+		loaded, err := surcharge.LoadDataset(cfg.SurchargeFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load surcharge dataset: %w", err)
+		}
+		dataset = loaded
+	}
+
+	surcharges, err := surcharge.NewService(dataset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create surcharge service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Surcharge dataset loaded: version=%s", surcharges.Version())
+
+	catalogue := eligibility.DefaultCatalogue()
+	if cfg.ConstraintsFile != "" {
+		loaded, err := eligibility.LoadCatalogue(cfg.ConstraintsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load constraint catalogue: %w", err)
+		}
+		catalogue = loaded
+	}
+
+	constraints, err := eligibility.NewService(catalogue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eligibility service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Constraint catalogue loaded: version=%s", constraints.Version())
+
+	calendar := transit.DefaultCalendar()
+	if cfg.CalendarFile != "" {
+		loaded, err := transit.LoadCalendar(cfg.CalendarFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load transit calendar: %w", err)
+		}
+		calendar = loaded
+	}
+
+	estimator, err := transit.NewService(calendar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transit service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Transit calendar loaded: version=%s", estimator.Version())
+
+	tariffTable := tariff.DefaultTable()
+	if cfg.TariffFile != "" {
+		loaded, err := tariff.LoadTable(cfg.TariffFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tariff table: %w", err)
+		}
+		tariffTable = loaded
+	}
+
+	tariffs, err := tariff.NewService(tariffTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tariff service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Tariff table loaded: version=%s", tariffs.Version())
+
+	deniedParties := screening.DefaultList()
+	if cfg.ScreeningFile != "" {
+		loaded, err := screening.LoadList(cfg.ScreeningFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load denied-party list: %w", err)
+		}
+		deniedParties = loaded
+	} else {
+		log.Warnf(ctx, "[Manager] screening_file is not configured, using built-in sample denied-party list")
+	}
+
+	screeningService, err := screening.NewService(deniedParties, cfg.ScreeningFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create screening service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Denied-party list loaded: version=%s, entries=%d", screeningService.Version(), len(deniedParties.Entries))
+
+	riskRules := risk.DefaultRules()
+	if cfg.RiskRulesFile != "" {
+		loaded, err := risk.LoadRules(cfg.RiskRulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load risk rules: %w", err)
+		}
+		riskRules = loaded
+	}
+
+	riskService, err := risk.NewService(riskRules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create risk service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Risk rules loaded: version=%s", riskService.Version())
+
+	geoDataset := geo.DefaultDataset()
+	if cfg.GeoFile != "" {
+		loaded, err := geo.LoadDataset(cfg.GeoFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load geo dataset: %w", err)
+		}
+		geoDataset = loaded
+	}
+
+	locator, err := geo.NewService(geoDataset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create geo service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Geo dataset loaded: version=%s, countries=%d", locator.Version(), len(geoDataset.Countries))
+
+	carbonFactors := carbon.DefaultFactors()
+	if cfg.CarbonFile != "" {
+		loaded, err := carbon.LoadFactors(cfg.CarbonFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load carbon factors: %w", err)
+		}
+		carbonFactors = loaded
+	}
+
+	emissions, err := carbon.NewService(carbonFactors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create carbon service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Carbon factors loaded: version=%s", emissions.Version())
+
+	insuranceTable := insurance.DefaultTable()
+	if cfg.InsuranceFile != "" {
+		loaded, err := insurance.LoadTable(cfg.InsuranceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load insurance table: %w", err)
+		}
+		insuranceTable = loaded
+	}
+
+	insuranceService, err := insurance.NewService(insuranceTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create insurance service: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Insurance table loaded: version=%s", insuranceService.Version())
+
+	quoter, err := newQuoter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "[Manager] Carrier quoter initialized: %s", quoter.Version())
+
+	return &services.Dependencies{
+		FX:          converter,
+		Surcharge:   surcharges,
+		Quoter:      quoter,
+		Eligibility: constraints,
+		Transit:     estimator,
+		Tariff:      tariffs,
+		Duplicates:  duplicates,
+		Screening:   screeningService,
+		Risk:        riskService,
+		Geo:         locator,
+		Carbon:      emissions,
+		Insurance:   insuranceService,
+	}, nil
+}
+
+// newDuplicateDetector 初始化重复订单检测（未配置时间窗口时返回 nil）
+// 近期订单索引优先使用 Redis 以便多个 Worker 实例共享，未配置 Redis 时退化为进程内索引
+func newDuplicateDetector(ctx context.Context, cfg *config.Config, log logger.Logger) (*dedup.Detector, error) {
+	if cfg.Diagnose.DuplicateWindow <= 0 {
+		return nil, nil
+	}
+
+	var index dedup.Index
+	if cfg.Redis.Addr != "" {
+		redisIndex, err := dedup.NewRedisIndex(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recent order index: %w", err)
+		}
+		index = redisIndex
+	} else {
+		log.Warnf(ctx, "[Manager] Redis is not configured, recent order index is kept in process memory")
+		index = dedup.NewMemoryIndex()
+	}
+
+	detector, err := dedup.NewDetector(index, cfg.Diagnose.DuplicateWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create duplicate detector: %w", err)
+	}
+	log.Infof(ctx, "[Manager] Duplicate order detection enabled: window=%s", cfg.Diagnose.DuplicateWindow)
+
+	return detector, nil
+}
+
+// newQuoter 初始化承运商报价器
+// 未配置承运商接口时使用费率卡离线报价；配置了接口的承运商按需降级为同名费率卡
+// 费率卡来自 rate_cards_file，未配置时使用内置费率卡
+func newQuoter(cfg config.DiagnoseConfig) (*rating.Quoter, error) {
+	rateCards := rating.DefaultRateCards()
+	if cfg.RateCardsFile != "" {
+		loaded, err := rating.LoadRateCards(cfg.RateCardsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rate cards: %w", err)
+		}
+		rateCards = loaded
+	}
+
+	if len(cfg.Carriers) == 0 {
+		return rating.NewStaticQuoter(rateCards), nil
+	}
+
+	cards := make(map[string]*rating.RateCard)
+	for _, card := range rateCards {
+		cards[strings.ToUpper(card.Carrier)] = card
+	}
+
+	quoter := rating.NewQuoter(cfg.CarrierTimeout)
+	client := &http.Client{}
+	for _, carrier := range cfg.Carriers {
+		var fallback rating.CarrierAdapter
+		if card, ok := cards[strings.ToUpper(carrier.Name)]; ok && carrier.Fallback {
+			fallback = rating.NewStaticAdapter(card)
+		}
+		quoter.Add(rating.NewHTTPAdapter(carrier.Name, carrier.Endpoint, client), fallback)
+	}
+
+	return quoter, nil
+}
+
+// newShadowRunner 初始化影子诊断器（未配置时返回 nil）
+// 每个影子按线上配置叠加 overrides 加载一套独立的参考数据，取其中 type 对应的诊断器
+// 结果存储优先使用 Redis 以便汇总多个 Worker 实例的统计，未配置 Redis 时退化为进程内存储
+func newShadowRunner(ctx context.Context, cfg *config.Config, live *services.Registry, duplicates *dedup.Detector, log logger.Logger) (*services.ShadowRunner, error) {
+	if len(cfg.Diagnose.Shadows) == 0 {
+		return nil, nil
+	}
+
+	var store shadow.Store
+	if cfg.Redis.Addr != "" {
+		redisStore, err := shadow.NewRedisStore(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, cfg.Diagnose.ShadowHistorySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shadow result store: %w", err)
+		}
+		store = redisStore
+	} else {
+		log.Warnf(ctx, "[Manager] Redis is not configured, shadow results are kept in process memory")
+		store = shadow.NewMemoryStore(cfg.Diagnose.ShadowHistorySize)
+	}
+
+	runner := services.NewShadowRunner(live, store, cfg.Diagnose.ShadowConcurrency, log)
+	for _, shadowCfg := range cfg.Diagnose.Shadows {
+		log.Infof(ctx, "[Manager] Loading reference data for shadow diagnoser %s", shadowCfg.Name)
+		deps, err := newDependencies(ctx, cfg.Diagnose.WithOverrides(shadowCfg.Overrides), duplicates, log)
+		if err != nil {
+			return nil, fmt.Errorf("shadow diagnoser %s: %w", shadowCfg.Name, err)
+		}
+
+		diagnoser, ok := services.NewDefaultRegistry(deps).Get(shadowCfg.Type)
+		if !ok {
+			return nil, fmt.Errorf("shadow diagnoser %s: unknown type %s", shadowCfg.Name, shadowCfg.Type)
+		}
+		if err := runner.Register(shadowCfg.Name, diagnoser, shadowCfg.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, info := range runner.Shadows() {
+		log.Infof(ctx, "[Manager] Shadow diagnoser registered: name=%s, type=%s, live_version=%s, shadow_version=%s",
+			info.Name, info.Type, info.LiveVersion, info.ShadowVersion)
+	}
+
+	return runner, nil
+}
@@ -9,11 +9,13 @@ import (
 
 // Config 全局配置
 type Config struct {
-	App     AppConfig      `mapstructure:"app"`
-	MySQL   MySQLConfig    `mapstructure:"mysql"`
-	Redis   RedisConfig    `mapstructure:"redis"`
-	Lmstfy  LmstfyConfig   `mapstructure:"lmstfy"`
-	Workers []WorkerConfig `mapstructure:"workers"`
+	App      AppConfig      `mapstructure:"app"`
+	MySQL    MySQLConfig    `mapstructure:"mysql"`
+	Redis    RedisConfig    `mapstructure:"redis"`
+	Lmstfy   LmstfyConfig   `mapstructure:"lmstfy"`
+	Admin    AdminConfig    `mapstructure:"admin"`
+	Diagnose DiagnoseConfig `mapstructure:"diagnose"`
+	Workers  []WorkerConfig `mapstructure:"workers"`
 }
 
 // AppConfig 应用配置
@@ -43,6 +45,89 @@ type LmstfyConfig struct {
 	Token     string `mapstructure:"token"`
 }
 
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	Addr  string `mapstructure:"addr"`  // 监听地址（为空时不启动管理接口；建议只监听 127.0.0.1）
+	Token string `mapstructure:"token"` // 写操作（PUT/POST）需在 X-Admin-Token 请求头携带的共享密钥（为空时拒绝全部写操作）
+}
+
+// DiagnoseConfig 诊断参考数据配置
+type DiagnoseConfig struct {
+	FXRatesFile     string `mapstructure:"fx_rates_file"`    // 汇率表文件（为空时使用内置汇率表）
+	SurchargeFile   string `mapstructure:"surcharge_file"`   // 附加费数据集文件（为空时使用内置数据集）
+	ConstraintsFile string `mapstructure:"constraints_file"` // 承运商服务约束目录文件（为空时使用内置目录）
+	CalendarFile    string `mapstructure:"calendar_file"`    // 时效估算日历文件（节假日、截单时间，为空时使用内置日历）
+	TariffFile      string `mapstructure:"tariff_file"`      // 进口税率表文件（关税/进口环节税税率与起征点，为空时使用内置税率表）
+	ScreeningFile   string `mapstructure:"screening_file"`   // 拒绝往来方名单文件（.csv/.json，为空时使用内置示例名单）
+	RiskRulesFile   string `mapstructure:"risk_rules_file"`  // 风险评分规则文件（信号权重、等级阈值、货代地址与一次性邮箱名单，为空时使用内置规则）
+	GeoFile         string `mapstructure:"geo_file"`         // 邮编质心数据文件（.csv/.json，用于线路距离与距离分区计价，为空时使用内置数据）
+	CarbonFile      string `mapstructure:"carbon_file"`      // 运输碳排放因子文件（各运输方式排放因子与服务运输方式，为空时使用内置数据）
+	InsuranceFile   string `mapstructure:"insurance_file"`   // 运输保险参考数据文件（承运商默认赔付责任、保费费率与线路丢损率，为空时使用内置数据）
+	RateCardsFile   string `mapstructure:"rate_cards_file"`  // 承运商费率卡文件（{"cards": [...]}，离线报价与接口降级使用，为空时使用内置费率卡）
+
+	ResultCacheSize int           `mapstructure:"result_cache_size"` // 诊断结果缓存条目数（0 表示不启用缓存）
+	ResultCacheTTL  time.Duration `mapstructure:"result_cache_ttl"`  // 诊断结果缓存有效期（0 表示不过期，仅按 LRU 淘汰）
+
+	DuplicateWindow time.Duration `mapstructure:"duplicate_window"` // 重复订单检测时间窗口（0 表示不启用；配置了 Redis 时索引存 Redis，否则存进程内）
+
+	Carriers       []CarrierConfig `mapstructure:"carriers"`        // 承运商报价接口（为空时使用内置费率卡离线报价）
+	CarrierTimeout time.Duration   `mapstructure:"carrier_timeout"` // 单个承运商报价超时（默认 2s）
+
+	Shadows           []ShadowConfig `mapstructure:"shadows"`             // 影子诊断器（候选规则/费率卡，结果单独保存不回传）
+	ShadowHistorySize int            `mapstructure:"shadow_history_size"` // 每个影子诊断器保留的最近结果条数（默认 1000）
+	ShadowConcurrency int            `mapstructure:"shadow_concurrency"`  // 同时执行的影子诊断任务数上限（默认 16，超出时丢弃）
+}
+
+// ShadowConfig 影子诊断器配置
+// 使用线上参考数据配置叠加 overrides 构建一套独立的诊断器，只取 type 对应的诊断器作为影子
+type ShadowConfig struct {
+	Name      string         `mapstructure:"name"`      // 影子诊断器名称（唯一，建议包含候选版本，如 risk-2026-11）
+	Type      string         `mapstructure:"type"`      // 诊断类型（必须已有线上诊断器）
+	Timeout   time.Duration  `mapstructure:"timeout"`   // 诊断超时（默认与线上诊断器默认超时一致）
+	Overrides DiagnoseConfig `mapstructure:"overrides"` // 候选参考数据（非空的文件与承运商配置替换线上配置）
+}
+
+// WithOverrides 叠加候选参考数据配置（非空字段替换，缓存、重复检测与影子配置不参与叠加）
+func (c DiagnoseConfig) WithOverrides(o DiagnoseConfig) DiagnoseConfig {
+	merged := c
+	files := []struct {
+		dst *string
+		src string
+	}{
+		{&merged.FXRatesFile, o.FXRatesFile},
+		{&merged.SurchargeFile, o.SurchargeFile},
+		{&merged.ConstraintsFile, o.ConstraintsFile},
+		{&merged.CalendarFile, o.CalendarFile},
+		{&merged.TariffFile, o.TariffFile},
+		{&merged.ScreeningFile, o.ScreeningFile},
+		{&merged.RiskRulesFile, o.RiskRulesFile},
+		{&merged.GeoFile, o.GeoFile},
+		{&merged.CarbonFile, o.CarbonFile},
+		{&merged.InsuranceFile, o.InsuranceFile},
+		{&merged.RateCardsFile, o.RateCardsFile},
+	}
+	for _, f := range files {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	if len(o.Carriers) > 0 {
+		merged.Carriers = o.Carriers
+	}
+	if o.CarrierTimeout > 0 {
+		merged.CarrierTimeout = o.CarrierTimeout
+	}
+	merged.Shadows = nil
+	return merged
+}
+
+// CarrierConfig 承运商报价接口配置
+type CarrierConfig struct {
+	Name     string `mapstructure:"name"`     // 承运商名称（与内置费率卡名称一致时可降级）
+	Endpoint string `mapstructure:"endpoint"` // 报价接口地址，如 http://localhost:8095
+	Fallback bool   `mapstructure:"fallback"` // 接口超时/失败时是否降级为内置费率卡
+}
+
 // WorkerConfig Worker 配置
 type WorkerConfig struct {
 	Name          string           `mapstructure:"name"`
@@ -96,5 +181,25 @@ func (c *Config) Validate() error {
 	if len(c.Workers) == 0 {
 		return fmt.Errorf("at least one worker is required")
 	}
+	for i, carrier := range c.Diagnose.Carriers {
+		if carrier.Name == "" || carrier.Endpoint == "" {
+			return fmt.Errorf("diagnose.carriers[%d]: name and endpoint are required", i)
+		}
+	}
+	names := make(map[string]bool, len(c.Diagnose.Shadows))
+	for i, shadow := range c.Diagnose.Shadows {
+		if shadow.Name == "" || shadow.Type == "" {
+			return fmt.Errorf("diagnose.shadows[%d]: name and type are required", i)
+		}
+		if names[shadow.Name] {
+			return fmt.Errorf("diagnose.shadows[%d]: duplicate name %s", i, shadow.Name)
+		}
+		names[shadow.Name] = true
+		for j, carrier := range shadow.Overrides.Carriers {
+			if carrier.Name == "" || carrier.Endpoint == "" {
+				return fmt.Errorf("diagnose.shadows[%d].overrides.carriers[%d]: name and endpoint are required", i, j)
+			}
+		}
+	}
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"time"
 
+	"oip/common/model"
 	"oip/dpsync/internal/business/order/diagnose/services"
 	"oip/dpsync/pkg/config"
 )
@@ -20,10 +21,11 @@ var (
 
 // TestCase 测试用例结构
 type TestCase struct {
-	OrderID         string                 `json:"order_id"`
-	AccountID       int64                  `json:"account_id"`
-	MerchantOrderNo string                 `json:"merchant_order_no"`
-	Shipment        map[string]interface{} `json:"shipment"`
+	OrderID         string          `json:"order_id"`
+	AccountID       int64           `json:"account_id"`
+	MerchantOrderNo string          `json:"merchant_order_no"`
+	Shipment        json.RawMessage `json:"shipment"`
+	Diagnosers      []string        `json:"diagnosers,omitempty"`
 }
 
 func main() {
@@ -118,7 +120,13 @@ func runTestCaseSkipDB(tc TestCase) error {
 	ctx := context.Background()
 
 	// 创建 CompositeHandler
-	compositeHandler := services.NewCompositeHandler()
+	compositeHandler := services.NewCompositeHandler(services.NewDefaultRegistry(services.NewDefaultDependencies()), nil, nil)
+
+	// 与 PreProcess 一致：严格解码并校验 shipment
+	shipment, err := model.DecodeShipment(tc.Shipment)
+	if err != nil {
+		return fmt.Errorf("invalid shipment: %w", err)
+	}
 
 	// 执行诊断
 	input := &services.DiagnoseInput{
@@ -126,7 +134,8 @@ func runTestCaseSkipDB(tc TestCase) error {
 		OrderID:         tc.OrderID,
 		AccountID:       tc.AccountID,
 		MerchantOrderNo: tc.MerchantOrderNo,
-		Shipment:        tc.Shipment,
+		Shipment:        shipment,
+		Diagnosers:      tc.Diagnosers,
 	}
 
 	result, err := compositeHandler.Diagnose(ctx, input)
@@ -152,12 +161,27 @@ func runTestCaseSkipDB(tc TestCase) error {
 					if recommended, ok := data["recommended_code"].(string); ok {
 						fmt.Printf("      Recommended: %s\n", recommended)
 					}
+					if addressType, ok := data["address_type"].(string); ok {
+						fmt.Printf("      Address type: %s\n", addressType)
+					}
 				} else if item.Type == "anomaly" {
 					if hasRisk, ok := data["has_risk"].(bool); ok {
 						fmt.Printf("      Has risk: %v\n", hasRisk)
 					}
 					if issues, ok := data["issues"].([]interface{}); ok {
 						fmt.Printf("      Issues count: %d\n", len(issues))
+						for _, issue := range issues {
+							if issueMap, ok := issue.(map[string]interface{}); ok {
+								fmt.Printf("        [%v] %v\n", issueMap["type"], issueMap["message"])
+							}
+						}
+					}
+				} else if item.Type == "compliance" {
+					if hasIssue, ok := data["has_issue"].(bool); ok {
+						fmt.Printf("      Has issue: %v\n", hasIssue)
+					}
+					if items, ok := data["items"].([]interface{}); ok {
+						fmt.Printf("      Items need attention: %d\n", len(items))
 					}
 				}
 			}